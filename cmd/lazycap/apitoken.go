@@ -0,0 +1,45 @@
+package lazycap
+
+import (
+	"fmt"
+
+	"github.com/integrii/flaggy"
+
+	"github.com/icarus-itcs/lazycap/internal/controlapi"
+	"github.com/icarus-itcs/lazycap/internal/settings"
+)
+
+// newAPITokenSubcommand builds the `api-token [--rotate]` subcommand: with
+// no flag it prints the current control API bearer token (generating one
+// on first use); --rotate replaces it, invalidating any token already in
+// use by an IDE extension or CI script.
+func newAPITokenSubcommand() (cmd *flaggy.Subcommand, rotate *bool) {
+	cmd = flaggy.NewSubcommand("api-token")
+	cmd.Description = "Print (or rotate) the local control API's bearer token"
+
+	rotate = new(bool)
+	cmd.Bool(rotate, "", "rotate", "generate a new token, invalidating the old one")
+
+	return cmd, rotate
+}
+
+func runAPITokenCmd(rotate bool) error {
+	userSettings, err := settings.Load()
+	if err != nil {
+		return fmt.Errorf("failed to load settings: %w", err)
+	}
+
+	var token string
+	if rotate {
+		token = controlapi.RotateToken(userSettings)
+	} else {
+		token, _ = controlapi.EnsureToken(userSettings)
+	}
+
+	if err := userSettings.Save(); err != nil {
+		return fmt.Errorf("failed to save settings: %w", err)
+	}
+
+	fmt.Println(token)
+	return nil
+}
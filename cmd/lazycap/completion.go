@@ -0,0 +1,58 @@
+package lazycap
+
+import (
+	"fmt"
+
+	"github.com/integrii/flaggy"
+)
+
+// newCompletionSubcommand builds the `completion [bash|zsh|fish]`
+// subcommand. The shell name is a required positional argument.
+func newCompletionSubcommand() (cmd *flaggy.Subcommand, shell *string) {
+	cmd = flaggy.NewSubcommand("completion")
+	cmd.Description = "Emit a shell completion script"
+
+	shell = new(string)
+	cmd.AddPositionalValue(shell, "shell", 1, true, "bash, zsh, or fish")
+
+	return cmd, shell
+}
+
+func runCompletionCmd(shell string) error {
+	switch shell {
+	case "bash":
+		fmt.Print(bashCompletionScript)
+	case "zsh":
+		fmt.Print(zshCompletionScript)
+	case "fish":
+		fmt.Print(fishCompletionScript)
+	default:
+		return fmt.Errorf("unsupported shell %q (expected bash, zsh, or fish)", shell)
+	}
+	return nil
+}
+
+const bashCompletionScript = `# lazycap bash completion
+_lazycap() {
+	local cur prev subcommands
+	COMPREPLY=()
+	cur="${COMP_WORDS[COMP_CWORD]}"
+	subcommands="run config completion version devices theme notify api-token plugin"
+	COMPREPLY=($(compgen -W "${subcommands}" -- "${cur}"))
+	return 0
+}
+complete -F _lazycap lazycap
+`
+
+const zshCompletionScript = `#compdef lazycap
+_lazycap() {
+	local -a subcommands
+	subcommands=(run config completion version devices theme notify api-token plugin)
+	_describe 'command' subcommands
+}
+_lazycap
+`
+
+const fishCompletionScript = `# lazycap fish completion
+complete -c lazycap -f -a "run config completion version devices theme notify api-token plugin"
+`
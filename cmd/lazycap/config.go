@@ -0,0 +1,58 @@
+package lazycap
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+
+	"github.com/integrii/flaggy"
+)
+
+// newConfigSubcommand builds the `config` subcommand and its `print`,
+// `path`, and `edit` children.
+func newConfigSubcommand() (cmd, print, path, edit *flaggy.Subcommand) {
+	cmd = flaggy.NewSubcommand("config")
+	cmd.Description = "Inspect lazycap configuration"
+
+	print = flaggy.NewSubcommand("print")
+	print.Description = "Print the effective default config"
+	cmd.AttachSubcommand(print, 1)
+
+	path = flaggy.NewSubcommand("path")
+	path.Description = "Print the resolved config file path"
+	cmd.AttachSubcommand(path, 1)
+
+	edit = flaggy.NewSubcommand("edit")
+	edit.Description = "Open the config file in $EDITOR"
+	cmd.AttachSubcommand(edit, 1)
+
+	return cmd, print, path, edit
+}
+
+// runConfigCmd dispatches to whichever `config` child subcommand was used,
+// defaulting to `print` when none was given.
+func runConfigCmd(print, path, edit *flaggy.Subcommand) error {
+	switch {
+	case path.Used:
+		fmt.Println(appConfig.ConfigPath)
+		return nil
+	case edit.Used:
+		return openConfigInEditor()
+	default:
+		fmt.Print(defaultConfigYAML)
+		return nil
+	}
+}
+
+func openConfigInEditor() error {
+	editor := os.Getenv("EDITOR")
+	if editor == "" {
+		editor = "vi"
+	}
+
+	cmd := exec.Command(editor, appConfig.ConfigPath)
+	cmd.Stdin = os.Stdin
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	return cmd.Run()
+}
@@ -0,0 +1,53 @@
+package lazycap
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/integrii/flaggy"
+
+	"github.com/icarus-itcs/lazycap/internal/notify"
+)
+
+// newNotifySubcommand builds the `notify test` subcommand, which dispatches
+// a synthetic event through every backend in the "notifications" setting
+// so users can validate setup without triggering a real build.
+func newNotifySubcommand() (cmd, test *flaggy.Subcommand) {
+	cmd = flaggy.NewSubcommand("notify")
+	cmd.Description = "Manage and test configured notification backends"
+
+	test = flaggy.NewSubcommand("test")
+	test.Description = "Send a synthetic event through every configured backend"
+	cmd.AttachSubcommand(test, 1)
+
+	return cmd, test
+}
+
+func runNotifyTestCmd() error {
+	dispatcher, errs := notify.BuildDispatcher(appConfig.Settings.GetRaw("notifications"), nil)
+	for _, err := range errs {
+		fmt.Fprintf(os.Stderr, "Warning: %v\n", err)
+	}
+
+	event := notify.Event{
+		Kind:     notify.KindTest,
+		Severity: notify.SeverityInfo,
+		Title:    "lazycap test notification",
+		Message:  "If you can see this, your notification backend is configured correctly.",
+	}
+
+	results := dispatcher.SendSync(context.Background(), event)
+	if len(results) == 0 {
+		fmt.Println(`No notification backends configured (see the "notifications" setting).`)
+		return nil
+	}
+	for name, err := range results {
+		if err != nil {
+			fmt.Printf("FAIL  %s: %v\n", name, err)
+		} else {
+			fmt.Printf("OK    %s\n", name)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,137 @@
+package lazycap
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/integrii/flaggy"
+)
+
+// newPluginSubcommand builds the `plugin` subcommand and its `scaffold`
+// child.
+func newPluginSubcommand() (cmd, scaffold *flaggy.Subcommand, scaffoldName, scaffoldDir *string) {
+	cmd = flaggy.NewSubcommand("plugin")
+	cmd.Description = "Develop out-of-process lazycap plugins"
+
+	scaffold = flaggy.NewSubcommand("scaffold")
+	scaffold.Description = "Generate a minimal Go module implementing the external plugin protocol"
+	scaffoldName = new(string)
+	scaffold.AddPositionalValue(scaffoldName, "name", 1, true, "plugin id, e.g. supabase")
+	scaffoldDir = new(string)
+	scaffold.String(scaffoldDir, "", "dir", "directory to write the module into (default: ./<name>)")
+	cmd.AttachSubcommand(scaffold, 1)
+
+	return cmd, scaffold, scaffoldName, scaffoldDir
+}
+
+// runPluginCmd dispatches to whichever `plugin` child subcommand was used.
+func runPluginCmd(scaffold *flaggy.Subcommand, name, dir string) error {
+	switch {
+	case scaffold.Used:
+		return runPluginScaffoldCmd(name, dir)
+	default:
+		return fmt.Errorf("specify a plugin subcommand (scaffold)")
+	}
+}
+
+func runPluginScaffoldCmd(name, dir string) error {
+	if name == "" {
+		return fmt.Errorf("plugin name is required")
+	}
+	if dir == "" {
+		dir = name
+	}
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return fmt.Errorf("failed to create %s: %w", dir, err)
+	}
+
+	mainPath := filepath.Join(dir, "main.go")
+	if _, err := os.Stat(mainPath); err == nil {
+		return fmt.Errorf("%s already exists, refusing to overwrite", mainPath)
+	}
+
+	ident := exportedIdent(name)
+	source := strings.NewReplacer(
+		"__PLUGIN_ID__", name,
+		"__PLUGIN_IDENT__", ident,
+	).Replace(pluginScaffoldTemplate)
+
+	if err := os.WriteFile(mainPath, []byte(source), 0o644); err != nil {
+		return fmt.Errorf("failed to write %s: %w", mainPath, err)
+	}
+
+	goModPath := filepath.Join(dir, "go.mod")
+	goMod := fmt.Sprintf("module lazycap-plugin-%s\n\ngo 1.21\n", name)
+	if err := os.WriteFile(goModPath, []byte(goMod), 0o644); err != nil {
+		return fmt.Errorf("failed to write %s: %w", goModPath, err)
+	}
+
+	fmt.Printf("Scaffolded %s plugin in %s\n", name, dir)
+	fmt.Println("Next steps:")
+	fmt.Println("  1. go mod tidy")
+	fmt.Println("  2. implement the TODOs in main.go")
+	fmt.Printf("  3. go build -o %s && mv %s ~/.config/lazycap/plugins/\n", name, name)
+	return nil
+}
+
+// exportedIdent turns a kebab/snake-case plugin id into an exported Go
+// identifier, e.g. "revenue-cat" -> "RevenueCat".
+func exportedIdent(id string) string {
+	var b strings.Builder
+	upperNext := true
+	for _, r := range id {
+		switch {
+		case r == '-' || r == '_':
+			upperNext = true
+		case upperNext:
+			b.WriteRune(toUpperRune(r))
+			upperNext = false
+		default:
+			b.WriteRune(r)
+		}
+	}
+	return b.String()
+}
+
+func toUpperRune(r rune) rune {
+	if r >= 'a' && r <= 'z' {
+		return r - ('a' - 'A')
+	}
+	return r
+}
+
+// pluginScaffoldTemplate is a minimal go-plugin server implementing
+// lazycap's external plugin protocol (see internal/plugin/pluginproto).
+// It's deliberately not wired up to the real generated gRPC stubs here -
+// copy this file next to your protoc-generated pluginproto package (or
+// vendor lazycap's) and fill in the TODOs.
+const pluginScaffoldTemplate = `package main
+
+// __PLUGIN_IDENT__Plugin is a minimal lazycap external plugin. Build it
+// and drop the binary in ~/.config/lazycap/plugins/ (or ./.lazycap/plugins/
+// for a project-local plugin) and lazycap will launch and proxy it
+// automatically - see "lazycap plugin scaffold" and
+// internal/plugin/external_client.go on the lazycap side.
+//
+// TODO: vendor or go-get lazycap's internal/plugin/pluginproto package and
+// implement pluginproto.PluginServer below, then serve it with
+// hashicorp/go-plugin's plugin.Serve using the same HandshakeConfig
+// lazycap dials with (protocol version __PLUGIN_IDENT__, magic cookie key
+// "LAZYCAP_PLUGIN").
+//
+// func main() {
+// 	plugin.Serve(&plugin.ServeConfig{
+// 		HandshakeConfig: handshakeConfig,
+// 		Plugins: map[string]plugin.Plugin{
+// 			"plugin": &pluginproto.GRPCPlugin{Impl: &__PLUGIN_IDENT__Plugin{}},
+// 		},
+// 		GRPCServer: plugin.DefaultGRPCServer,
+// 	})
+// }
+
+func main() {
+	panic("TODO: implement the __PLUGIN_ID__ plugin protocol - see the comment above")
+}
+`
@@ -4,83 +4,184 @@ import (
 	"fmt"
 	"os"
 	"os/signal"
+	"runtime"
 	"syscall"
 
 	tea "github.com/charmbracelet/bubbletea"
-	"github.com/spf13/cobra"
+	"github.com/integrii/flaggy"
 
-	"lazycap/internal/cap"
-	"lazycap/internal/plugin"
-	"lazycap/internal/plugins"
-	"lazycap/internal/ui"
+	"github.com/icarus-itcs/lazycap/internal/cap"
+	"github.com/icarus-itcs/lazycap/internal/controlapi"
+	"github.com/icarus-itcs/lazycap/internal/i18n"
+	"github.com/icarus-itcs/lazycap/internal/notify"
+	"github.com/icarus-itcs/lazycap/internal/plugin"
+	"github.com/icarus-itcs/lazycap/internal/plugins"
+	"github.com/icarus-itcs/lazycap/internal/settings"
+	"github.com/icarus-itcs/lazycap/internal/ui"
+	"github.com/icarus-itcs/lazycap/internal/update"
 )
 
+// AppMeta carries build-time information into Execute. It replaces the
+// trio of positional version/commit/date strings so future top-level
+// flags don't keep growing the Execute signature.
+type AppMeta struct {
+	Version string
+	Commit  string
+	Date    string
+}
+
 var (
-	appVersion string
-	appCommit  string
-	appDate    string
-	demoMode   bool
+	appMeta       AppMeta
+	appConfig     *settings.AppConfig
+	demoMode      bool
+	debugFlag     bool
+	projectPaths  []string
+	metricsStdout bool
+	dumpI18nKeys  bool
+	themeFlag     string
+	apiFlag       bool
+	apiPortFlag   int
 )
 
-var rootCmd = &cobra.Command{
-	Use:   "lazycap",
-	Short: "A slick terminal UI for Capacitor & Ionic development",
-	Long: `lazycap is a terminal UI for Capacitor/Ionic mobile development.
-Manage devices, emulators, builds, and live reload from one beautiful interface.
+// defaultConfigYAML is the effective default configuration, printed by
+// `lazycap config print` so users can pipe it into a starter config file.
+const defaultConfigYAML = `# lazycap default configuration
+liveReloadDefault: false
+webDevPort: 5173
+webHost: localhost
+webHttps: false
+webOpenBrowser: true
+webBrowserPath: ""
+webDevCommand: ""
+webAutoRestart: false
+editorCommand: ""
+metricsPort: 0
+preflightWatchSeconds: 30
+language: ""
+pluginManifestURL: ""
+theme: capacitor
+apiEnabled: false
+apiPort: 4550
+apiBindAddress: 127.0.0.1
+`
 
-Navigate to your Capacitor project directory and run 'lazycap' to get started.`,
-	RunE: func(cmd *cobra.Command, args []string) error {
-		if demoMode {
-			return runDemoMode()
-		}
-		return runApp()
-	},
+// printVersion prints the full build info block, mirroring the
+// lazygit/lazydocker `--version` output.
+func printVersion() {
+	fmt.Printf("lazycap\n")
+	fmt.Printf("  version:    %s\n", appMeta.Version)
+	fmt.Printf("  commit:     %s\n", appMeta.Commit)
+	fmt.Printf("  built:      %s\n", appMeta.Date)
+	fmt.Printf("  os/arch:    %s/%s\n", runtime.GOOS, runtime.GOARCH)
+	fmt.Printf("  go version: %s\n", runtime.Version())
 }
 
-var versionCmd = &cobra.Command{
-	Use:   "version",
-	Short: "Print version information",
-	Run: func(cmd *cobra.Command, args []string) {
-		fmt.Printf("lazycap %s\n", appVersion)
-		fmt.Printf("  commit: %s\n", appCommit)
-		fmt.Printf("  built:  %s\n", appDate)
-	},
-}
-
-var devicesCmd = &cobra.Command{
-	Use:   "devices",
-	Short: "List available devices and emulators",
-	RunE: func(cmd *cobra.Command, args []string) error {
-		devices, err := cap.ListDevices()
-		if err != nil {
-			return err
-		}
-		for _, d := range devices {
-			status := "offline"
-			if d.Online {
-				status = "online"
-			}
-			fmt.Printf("%s\t%s\t%s\t%s\n", d.ID, d.Name, d.Platform, status)
+func runDevicesCmd() error {
+	devices, err := cap.ListDevices()
+	if err != nil {
+		return err
+	}
+	for _, d := range devices {
+		status := "offline"
+		if d.Online {
+			status = "online"
 		}
-		return nil
-	},
+		fmt.Printf("%s\t%s\t%s\t%s\n", d.ID, d.Name, d.Platform, status)
+	}
+	return nil
 }
 
-func init() {
-	rootCmd.AddCommand(versionCmd)
-	rootCmd.AddCommand(devicesCmd)
+// Execute builds the flaggy parser and dispatches to the matching
+// subcommand using the given build metadata.
+func Execute(meta AppMeta) error {
+	appMeta = meta
 
-	// Global flags
-	rootCmd.PersistentFlags().StringP("config", "c", "", "config file (default: .lazycap.yaml)")
-	rootCmd.PersistentFlags().BoolP("verbose", "v", false, "verbose output")
-	rootCmd.Flags().BoolVar(&demoMode, "demo", false, "run in demo mode with mock data (for screenshots)")
-}
+	flaggy.SetName("lazycap")
+	flaggy.SetDescription("A slick terminal UI for Capacitor & Ionic development")
+	flaggy.SetVersion(meta.Version)
+
+	runCmd := flaggy.NewSubcommand("run")
+	runCmd.Description = "Launch the TUI (default)"
+	runCmd.Bool(&demoMode, "", "demo", "run in demo mode with mock data (for screenshots)")
+	runCmd.Bool(&debugFlag, "d", "debug", "enable verbose debug logging")
+	runCmd.StringSlice(&projectPaths, "f", "file", "Capacitor project directory to launch against (repeatable)")
+	runCmd.Bool(&metricsStdout, "", "metrics-stdout", "periodically print Prometheus metrics to stdout instead of serving them over HTTP")
+	runCmd.Bool(&dumpI18nKeys, "", "dump-keys", "on exit, print every i18n translation key used this session (for adding new language bundles)")
+	runCmd.String(&themeFlag, "", "theme", "styleset to use for this run, overriding the configured theme setting")
+	runCmd.Bool(&apiFlag, "", "api", "start the local control API for IDE/CI integration (see lazycap api-token)")
+	runCmd.Int(&apiPortFlag, "", "api-port", "port for the local control API, overriding the apiPort setting")
+	flaggy.AttachSubcommand(runCmd, 1)
+
+	versionCmd := flaggy.NewSubcommand("version")
+	versionCmd.Description = "Print structured build information"
+	flaggy.AttachSubcommand(versionCmd, 1)
 
-func Execute(version, commit, date string) error {
-	appVersion = version
-	appCommit = commit
-	appDate = date
-	return rootCmd.Execute()
+	devicesCmd := flaggy.NewSubcommand("devices")
+	devicesCmd.Description = "List available devices and emulators"
+	flaggy.AttachSubcommand(devicesCmd, 1)
+
+	configCmd, configPrintCmd, configPathCmd, configEditCmd := newConfigSubcommand()
+	flaggy.AttachSubcommand(configCmd, 1)
+
+	completionCmd, shell := newCompletionSubcommand()
+	flaggy.AttachSubcommand(completionCmd, 1)
+
+	serveCmd, serveListen, serveHostKey := newServeSubcommand()
+	flaggy.AttachSubcommand(serveCmd, 1)
+
+	themeCmd, themeName := newThemeSubcommand()
+	flaggy.AttachSubcommand(themeCmd, 1)
+
+	notifyCmd, notifyTestCmd := newNotifySubcommand()
+	flaggy.AttachSubcommand(notifyCmd, 1)
+
+	apiTokenCmd, apiTokenRotate := newAPITokenSubcommand()
+	flaggy.AttachSubcommand(apiTokenCmd, 1)
+
+	pluginCmd, pluginScaffoldCmd, pluginScaffoldName, pluginScaffoldDir := newPluginSubcommand()
+	flaggy.AttachSubcommand(pluginCmd, 1)
+
+	flaggy.Bool(&debugFlag, "d", "debug", "enable verbose debug logging")
+	flaggy.String(&themeFlag, "", "theme", "styleset to use for this run, overriding the configured theme setting")
+
+	flaggy.Parse()
+	ui.Debug = debugFlag
+	ui.ThemeOverride = themeFlag
+
+	cfg, err := settings.NewAppConfig("lazycap", meta.Version, meta.Commit, meta.Date, "binary", debugFlag)
+	if err != nil {
+		return fmt.Errorf("failed to resolve lazycap config: %w", err)
+	}
+	appConfig = cfg
+
+	switch {
+	case versionCmd.Used:
+		printVersion()
+		return nil
+	case devicesCmd.Used:
+		return runDevicesCmd()
+	case configCmd.Used:
+		return runConfigCmd(configPrintCmd, configPathCmd, configEditCmd)
+	case completionCmd.Used:
+		return runCompletionCmd(*shell)
+	case serveCmd.Used:
+		return runServeCmd(*serveListen, *serveHostKey)
+	case themeCmd.Used:
+		return runThemeCmd(*themeName)
+	case notifyTestCmd.Used:
+		return runNotifyTestCmd()
+	case apiTokenCmd.Used:
+		return runAPITokenCmd(*apiTokenRotate)
+	case pluginCmd.Used:
+		return runPluginCmd(pluginScaffoldCmd, *pluginScaffoldName, *pluginScaffoldDir)
+	default:
+		// `run` is also the default when no subcommand is given, matching
+		// the previous implicit "launch the TUI" behavior.
+		if demoMode {
+			return runDemoMode()
+		}
+		return runApp(projectPaths, metricsStdout, dumpI18nKeys, apiFlag, apiPortFlag)
+	}
 }
 
 func runDemoMode() error {
@@ -109,7 +210,37 @@ func runDemoMode() error {
 	return nil
 }
 
-func runApp() error {
+// runApp launches the TUI against the current directory, or against the
+// first of paths if given. lazycap manages exactly one Capacitor project
+// per run (there's no multi-file browser view or stdin stream loader like
+// lazydocker's compose-file equivalent), so only the first path is used;
+// additional paths are reported but otherwise ignored.
+func runApp(paths []string, metricsStdout, dumpI18nKeys, apiFlag bool, apiPortFlag int) error {
+	// Detect whether this version crashed on its last run or the one
+	// before it (see internal/update/health.go) and auto-revert `current`
+	// to `previous` after two strikes in a row.
+	if appMeta.Version != "" {
+		rolledBack, err := update.CheckStartupHealth(appMeta.Version)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: update health check failed: %v\n", err)
+		} else if rolledBack {
+			fmt.Fprintf(os.Stderr, "lazycap %s crashed on startup twice in a row; reverted to the previous version. Please relaunch lazycap.\n", appMeta.Version)
+			return nil
+		}
+	}
+
+	if len(paths) > 0 {
+		if paths[0] == "-" {
+			return fmt.Errorf("reading a project from stdin is not supported; pass a Capacitor project directory")
+		}
+		if len(paths) > 1 {
+			fmt.Fprintf(os.Stderr, "Warning: lazycap only supports one project per run; using %s and ignoring %d other path(s)\n", paths[0], len(paths)-1)
+		}
+		if err := os.Chdir(paths[0]); err != nil {
+			return fmt.Errorf("failed to open project at %s: %w", paths[0], err)
+		}
+	}
+
 	// Check if we're in a Capacitor project
 	if !cap.IsCapacitorProject() {
 		return fmt.Errorf("not a Capacitor project (no capacitor.config.ts/js/json found)")
@@ -133,8 +264,57 @@ func runApp() error {
 	appContext := plugin.NewAppContext(pluginManager)
 	appContext.SetProject(project)
 
+	// Discover and launch out-of-process plugins (see
+	// ~/.config/lazycap/plugins/ and ./.lazycap/plugins/). A plugin that
+	// fails to start is logged and skipped, same as a built-in that fails
+	// InitAll below.
+	for _, err := range pluginManager.LoadExternalPlugins(appContext) {
+		fmt.Fprintf(os.Stderr, "Warning: failed to load external plugin: %v\n", err)
+	}
+
+	dispatcher, notifyErrs := notify.BuildDispatcher(appConfig.Settings.GetRaw("notifications"), func(backend string, err error) {
+		fmt.Fprintf(os.Stderr, "Warning: notification backend %s failed: %v\n", backend, err)
+	})
+	for _, err := range notifyErrs {
+		fmt.Fprintf(os.Stderr, "Warning: %v\n", err)
+	}
+	appContext.SetNotifier(dispatcher)
+
 	// Initialize and run the TUI with plugin support
+	if dumpI18nKeys {
+		i18n.SetDumpKeys(true)
+	}
 	model := ui.NewModelWithPlugins(project, pluginManager, appContext)
+	if metricsStdout {
+		model.SetMetricsStdout(true)
+	}
+
+	if apiFlag || appConfig.Settings.GetBool("apiEnabled") {
+		apiPort := apiPortFlag
+		if apiPort <= 0 {
+			apiPort = appConfig.Settings.GetInt("apiPort")
+		}
+		token, generated := controlapi.EnsureToken(appConfig.Settings)
+		if generated {
+			_ = appConfig.Settings.Save()
+		}
+
+		bindAddress := appConfig.Settings.GetString("apiBindAddress")
+		if bindAddress == "" {
+			bindAddress = "127.0.0.1"
+		}
+		fmt.Fprintf(os.Stderr, "lazycap control API: listening on http://%s:%d (token: %s)\n", bindAddress, apiPort, token)
+
+		apiServer := controlapi.New(appContext, token)
+		dispatcher.Register(apiServer.Notifier(), notify.Filter{})
+		model.SetAPIStatus(apiPort)
+		go func() {
+			if err := apiServer.Serve(fmt.Sprintf("%s:%d", bindAddress, apiPort)); err != nil {
+				fmt.Fprintf(os.Stderr, "Warning: control API server: %v\n", err)
+			}
+		}()
+	}
+
 	p := tea.NewProgram(model, tea.WithAltScreen())
 
 	// Handle graceful shutdown for plugins
@@ -143,6 +323,9 @@ func runApp() error {
 	go func() {
 		<-sigCh
 		pluginManager.StopAll()
+		if appMeta.Version != "" {
+			_ = update.RecordCleanExit(appMeta.Version)
+		}
 		os.Exit(0)
 	}()
 
@@ -155,6 +338,14 @@ func runApp() error {
 	// Start auto-start plugins
 	pluginManager.StartAutoStart()
 
+	// Startup got this far without crashing, so this version is no longer
+	// a trial install (see update.IsTrial).
+	if appMeta.Version != "" && update.IsTrial(appMeta.Version) {
+		if err := update.MarkFirstRunComplete(appMeta.Version); err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: failed to record first successful run: %v\n", err)
+		}
+	}
+
 	// Run the TUI
 	if _, err := p.Run(); err != nil {
 		pluginManager.StopAll()
@@ -164,5 +355,17 @@ func runApp() error {
 	// Stop all plugins on exit
 	pluginManager.StopAll()
 
+	if appMeta.Version != "" {
+		if err := update.RecordCleanExit(appMeta.Version); err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: failed to record clean exit: %v\n", err)
+		}
+	}
+
+	if dumpI18nKeys {
+		for _, key := range i18n.DumpKeys() {
+			fmt.Println(key)
+		}
+	}
+
 	return nil
 }
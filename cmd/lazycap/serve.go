@@ -0,0 +1,184 @@
+package lazycap
+
+import (
+	"fmt"
+	"net"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/wish"
+	"github.com/charmbracelet/wish/bubbletea"
+	"github.com/charmbracelet/wish/logging"
+	"github.com/gliderlabs/ssh"
+	"github.com/integrii/flaggy"
+	gossh "golang.org/x/crypto/ssh"
+
+	"github.com/icarus-itcs/lazycap/internal/cap"
+	"github.com/icarus-itcs/lazycap/internal/plugin"
+	"github.com/icarus-itcs/lazycap/internal/plugins"
+	"github.com/icarus-itcs/lazycap/internal/ui"
+)
+
+// chdirMu serializes the os.Chdir/cap.LoadProject window in
+// serveTeaHandler - os.Chdir mutates process-wide state, and `serve`
+// handles every SSH session as a goroutine in the same process, so two
+// sessions opening different projects at once would otherwise race on the
+// cwd. Once a session's Model is built, runCmd/runWebCmd take the
+// project's RootDir directly rather than reading the cwd again, so this
+// lock only needs to cover project discovery, not the session's whole
+// lifetime.
+var chdirMu sync.Mutex
+
+// newServeSubcommand builds the `serve` subcommand, which exposes the TUI
+// over SSH instead of running it against the local terminal.
+func newServeSubcommand() (cmd *flaggy.Subcommand, listen, hostKeyPath *string) {
+	cmd = flaggy.NewSubcommand("serve")
+	cmd.Description = "Expose the TUI over SSH for remote/shared access"
+
+	listenAddr := ":2222"
+	keyPath := "~/.ssh/lazycap_ed25519"
+	cmd.String(&listenAddr, "l", "listen", "address to listen on")
+	cmd.String(&keyPath, "", "host-key", "path to the SSH host key (generated on first connection if missing)")
+
+	return cmd, &listenAddr, &keyPath
+}
+
+// runServeCmd starts the SSH server. Each incoming session gets its own
+// independent Model (see serveTeaHandler) bound to the project in the
+// server's working directory - there's no live multiplexing of a single
+// running process's output across multiple viewers yet, but since every
+// session shares the same on-disk internal/session store, a process
+// started from one session is reattached (read-only, once finished; live
+// if still running) by the next session opened against the same project.
+func runServeCmd(listen, hostKeyPath string) error {
+	hostKeyPath = expandHome(hostKeyPath)
+
+	srv, err := wish.NewServer(
+		wish.WithAddress(listen),
+		wish.WithHostKeyPath(hostKeyPath),
+		wish.WithPublicKeyAuth(authorizedKeyHandler),
+		wish.WithMiddleware(
+			bubbletea.Middleware(serveTeaHandler),
+			logging.Middleware(),
+		),
+	)
+	if err != nil {
+		return fmt.Errorf("failed to configure ssh server: %w", err)
+	}
+
+	ln, err := net.Listen("tcp", listen)
+	if err != nil {
+		return fmt.Errorf("failed to listen on %s: %w", listen, err)
+	}
+
+	fmt.Printf("lazycap serve: listening on %s (host key %s)\n", listen, hostKeyPath)
+	return srv.Serve(ln)
+}
+
+// loadProjectAt chdirs to dir, validates and loads the Capacitor project
+// there, and restores the previous cwd - all under chdirMu, since
+// cap.IsCapacitorProject/cap.LoadProject only know how to inspect the
+// process's current directory. The returned *cap.Project carries its own
+// RootDir, so callers never need to touch the cwd again afterward.
+func loadProjectAt(dir string) (*cap.Project, error) {
+	chdirMu.Lock()
+	defer chdirMu.Unlock()
+
+	prevDir, err := os.Getwd()
+	if err != nil {
+		return nil, err
+	}
+	if err := os.Chdir(dir); err != nil {
+		return nil, fmt.Errorf("failed to open project at %s: %w", dir, err)
+	}
+	defer os.Chdir(prevDir)
+
+	if !cap.IsCapacitorProject() {
+		return nil, fmt.Errorf("not a Capacitor project (no capacitor.config.ts/js/json found) at %s", dir)
+	}
+	return cap.LoadProject()
+}
+
+// serveTeaHandler builds the Model for one SSH session: it loads the
+// Capacitor project from the server's working directory (or, if the
+// client passed one, `ssh lazycap-host -- /path/to/project`), registers a
+// fresh plugin manager for the session, and redirects clipboard/export so
+// this session can't collide with or leak another session's files.
+func serveTeaHandler(s ssh.Session) (tea.Model, []tea.ProgramOption) {
+	projectDir, err := os.Getwd()
+	if err != nil {
+		wish.Fatalln(s, err)
+		return nil, nil
+	}
+	if args := s.Command(); len(args) > 0 {
+		projectDir = args[0]
+	}
+
+	project, err := loadProjectAt(projectDir)
+	if err != nil {
+		wish.Fatalln(s, err)
+		return nil, nil
+	}
+
+	if err := plugins.RegisterAll(); err != nil {
+		wish.Fatalln(s, err)
+		return nil, nil
+	}
+	pluginManager := plugin.NewManager()
+	appContext := plugin.NewAppContext(pluginManager)
+	appContext.SetProject(project)
+
+	model := ui.NewModelWithPlugins(project, pluginManager, appContext)
+	if exportDir, err := os.MkdirTemp("", "lazycap-ssh-"); err == nil {
+		model.SetRemoteSession(exportDir)
+	} else {
+		model.SetRemoteSession("")
+	}
+
+	if err := pluginManager.InitAll(appContext); err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: some plugins failed to initialize for ssh session %s: %v\n", s.User(), err)
+	}
+	pluginManager.StartAutoStart()
+
+	return model, []tea.ProgramOption{tea.WithAltScreen()}
+}
+
+// authorizedKeyHandler accepts a connecting client iff its public key is
+// listed in the server operator's ~/.ssh/authorized_keys, mirroring what
+// plain sshd would do.
+func authorizedKeyHandler(ctx ssh.Context, key ssh.PublicKey) bool {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return false
+	}
+	data, err := os.ReadFile(filepath.Join(home, ".ssh", "authorized_keys"))
+	if err != nil {
+		return false
+	}
+
+	for len(data) > 0 {
+		allowed, _, _, rest, err := gossh.ParseAuthorizedKey(data)
+		if err != nil {
+			break
+		}
+		if ssh.KeysEqual(key, allowed) {
+			return true
+		}
+		data = rest
+	}
+	return false
+}
+
+func expandHome(path string) string {
+	if !strings.HasPrefix(path, "~") {
+		return path
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return path
+	}
+	return filepath.Join(home, strings.TrimPrefix(path, "~"))
+}
@@ -0,0 +1,83 @@
+package lazycap
+
+import (
+	"fmt"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/integrii/flaggy"
+
+	"github.com/icarus-itcs/lazycap/internal/settings"
+	"github.com/icarus-itcs/lazycap/internal/theme"
+)
+
+// themeSelectors are the selectors previewed by `lazycap theme`, in the
+// same order styles.go's applyTheme resolves them - enough to judge
+// whether a styleset "reads" well without launching the TUI.
+var themeSelectors = []string{
+	"brand.primary",
+	"brand.secondary",
+	"status.success",
+	"status.error",
+	"status.warning",
+	"text.muted",
+	"platform.ios",
+	"platform.android",
+	"platform.web",
+	"tab.active",
+	"tab.inactive",
+}
+
+// newThemeSubcommand builds the `theme [name]` subcommand: with no name it
+// lists every available styleset (marking the active one) and previews it;
+// with a name it previews that styleset without changing the active one.
+func newThemeSubcommand() (cmd *flaggy.Subcommand, name *string) {
+	cmd = flaggy.NewSubcommand("theme")
+	cmd.Description = "List and preview color themes (stylesets)"
+
+	name = new(string)
+	cmd.AddPositionalValue(name, "name", 1, false, "styleset to preview (defaults to the active one)")
+
+	return cmd, name
+}
+
+func runThemeCmd(name string) error {
+	userSettings, _ := settings.Load()
+	active := userSettings.GetString("theme")
+	if active == "" {
+		active = theme.DefaultName
+	}
+
+	dir, _ := settings.StylesetsDir()
+
+	previewing := name
+	if previewing == "" {
+		previewing = active
+	}
+
+	fmt.Println("Available themes:")
+	for _, n := range theme.List(dir) {
+		marker := "  "
+		if n == active {
+			marker = "* "
+		}
+		fmt.Printf("%s%s\n", marker, n)
+	}
+	fmt.Println()
+
+	ss, err := theme.Load(previewing, dir)
+	if err != nil {
+		return fmt.Errorf("failed to load theme %q: %w", previewing, err)
+	}
+
+	fmt.Printf("Preview of %q:\n", previewing)
+	for _, selector := range themeSelectors {
+		s := ss.Resolve(selector, theme.Style{})
+		swatch := lipgloss.NewStyle()
+		if s.FG != "" {
+			swatch = swatch.Foreground(lipgloss.Color(s.FG))
+		}
+		fmt.Printf("  %-20s %s\n", selector, swatch.Render("████ "+s.FG))
+	}
+
+	return nil
+}
@@ -0,0 +1,58 @@
+package controlapi
+
+import (
+	"net/http"
+	"strings"
+)
+
+// checkBearer reports whether an HTTP Authorization header value matches
+// "Bearer <token>". Always false for an empty token, mirroring the MCP
+// plugin's checkBearer so auth can never be silently bypassed.
+func checkBearer(header, token string) bool {
+	return token != "" && header == "Bearer "+token
+}
+
+// localOriginPrefixes are the only Origin values the control API's CORS
+// policy allows - IDE extensions and browser-based tools running on the
+// same machine, never an arbitrary remote page.
+var localOriginPrefixes = []string{
+	"http://localhost",
+	"http://127.0.0.1",
+	"http://[::1]",
+}
+
+func originIsLocal(origin string) bool {
+	for _, prefix := range localOriginPrefixes {
+		if strings.HasPrefix(origin, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+func applyCORS(w http.ResponseWriter, r *http.Request) {
+	origin := r.Header.Get("Origin")
+	if origin == "" || !originIsLocal(origin) {
+		return
+	}
+	w.Header().Set("Access-Control-Allow-Origin", origin)
+	w.Header().Set("Access-Control-Allow-Methods", "GET, POST, PATCH, OPTIONS")
+	w.Header().Set("Access-Control-Allow-Headers", "Authorization, Content-Type")
+}
+
+// withAuth wraps handler with the CORS policy above and bearer-token
+// auth, so neither check has to be repeated in every handler.
+func (s *Server) withAuth(handler http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		applyCORS(w, r)
+		if r.Method == http.MethodOptions {
+			w.WriteHeader(http.StatusNoContent)
+			return
+		}
+		if !checkBearer(r.Header.Get("Authorization"), s.token) {
+			http.Error(w, "unauthorized", http.StatusUnauthorized)
+			return
+		}
+		handler(w, r)
+	}
+}
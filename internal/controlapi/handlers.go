@@ -0,0 +1,170 @@
+package controlapi
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/icarus-itcs/lazycap/internal/notify"
+)
+
+// handleDevices implements GET /devices, mirroring cmd/lazycap's
+// `devices` subcommand.
+func (s *Server) handleDevices(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	writeJSON(w, s.ctx.GetDevices())
+}
+
+// pluginInfo is the GET /plugins summary for one registered plugin.
+type pluginInfo struct {
+	ID      string `json:"id"`
+	Name    string `json:"name"`
+	Version string `json:"version"`
+	Running bool   `json:"running"`
+}
+
+func (s *Server) handlePlugins(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	plugins := s.ctx.GetPlugins()
+	out := make([]pluginInfo, 0, len(plugins))
+	for _, p := range plugins {
+		out = append(out, pluginInfo{ID: p.ID(), Name: p.Name(), Version: p.Version(), Running: p.IsRunning()})
+	}
+	writeJSON(w, out)
+}
+
+// handlePluginByID dispatches /plugins/{id}/start|stop|settings based on
+// the path segment after the id - net/http's ServeMux has no path
+// variables in this Go version, so the split happens here.
+func (s *Server) handlePluginByID(w http.ResponseWriter, r *http.Request) {
+	rest := strings.TrimPrefix(r.URL.Path, "/plugins/")
+	parts := strings.SplitN(rest, "/", 2)
+	if len(parts) < 2 || parts[0] == "" {
+		http.NotFound(w, r)
+		return
+	}
+	id, action := parts[0], parts[1]
+
+	switch action {
+	case "start":
+		s.handlePluginToggle(w, r, id, true)
+	case "stop":
+		s.handlePluginToggle(w, r, id, false)
+	case "settings":
+		s.handlePluginSettings(w, r, id)
+	default:
+		http.NotFound(w, r)
+	}
+}
+
+func (s *Server) handlePluginToggle(w http.ResponseWriter, r *http.Request, id string, enable bool) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	if err := s.ctx.SetPluginEnabled(id, enable); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	writeJSON(w, map[string]bool{"running": enable})
+}
+
+func (s *Server) handlePluginSettings(w http.ResponseWriter, r *http.Request, id string) {
+	switch r.Method {
+	case http.MethodGet:
+		for _, p := range s.ctx.GetPlugins() {
+			if p.ID() == id {
+				writeJSON(w, p.GetSettings())
+				return
+			}
+		}
+		http.NotFound(w, r)
+
+	case http.MethodPatch:
+		var body struct {
+			Key   string      `json:"key"`
+			Value interface{} `json:"value"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			http.Error(w, "invalid request body", http.StatusBadRequest)
+			return
+		}
+		if err := s.ctx.SetPluginSetting(id, body.Key, body.Value); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+
+	default:
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+	}
+}
+
+// handleBuild implements POST /build/{platform}. There's no platform-
+// scoped Build in plugin.Context yet, so this reuses Sync - the same "cap
+// sync <platform>" the TUI's sync action runs - rather than adding a
+// second, narrower build path.
+func (s *Server) handleBuild(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	platform := strings.TrimPrefix(r.URL.Path, "/build/")
+	if platform == "" {
+		http.Error(w, "platform is required", http.StatusBadRequest)
+		return
+	}
+	if err := s.ctx.Sync(platform); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.WriteHeader(http.StatusAccepted)
+}
+
+// handleEvents implements GET /events: a server-sent-events stream of
+// every notify.Event the app dispatches (see Server.Notifier), so an IDE
+// or CI script can watch builds/processes/devices without polling.
+func (s *Server) handleEvents(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		http.Error(w, "streaming unsupported", http.StatusInternalServerError)
+		return
+	}
+
+	ch := make(chan notify.Event, 64)
+	unregister := s.hub.register(ch)
+	defer unregister()
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	w.WriteHeader(http.StatusOK)
+
+	ctx := r.Context()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case event := <-ch:
+			data, err := json.Marshal(event)
+			if err != nil {
+				continue
+			}
+			if _, err := fmt.Fprintf(w, "event: %s\ndata: %s\n\n", event.Kind, data); err != nil {
+				return
+			}
+			flusher.Flush()
+		}
+	}
+}
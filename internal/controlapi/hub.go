@@ -0,0 +1,54 @@
+package controlapi
+
+import (
+	"context"
+	"sync"
+
+	"github.com/icarus-itcs/lazycap/internal/notify"
+)
+
+// sseHub fans notify.Events out to every connected GET /events client. It
+// implements notify.Notifier so it can be registered with the app's
+// regular notification Dispatcher (see runApp) instead of needing a
+// second, parallel event-forwarding path.
+type sseHub struct {
+	mu   sync.Mutex
+	next int
+	subs map[int]chan notify.Event
+}
+
+func newSSEHub() *sseHub {
+	return &sseHub{subs: make(map[int]chan notify.Event)}
+}
+
+func (h *sseHub) Name() string { return "control-api" }
+
+// Send fans event out to every connected client, dropping it for any
+// client whose channel is full rather than blocking the Dispatcher.
+func (h *sseHub) Send(_ context.Context, event notify.Event) error {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	for _, ch := range h.subs {
+		select {
+		case ch <- event:
+		default:
+		}
+	}
+	return nil
+}
+
+// register adds ch to the hub's subscriber set and returns a func that
+// removes it again.
+func (h *sseHub) register(ch chan notify.Event) func() {
+	h.mu.Lock()
+	id := h.next
+	h.next++
+	h.subs[id] = ch
+	h.mu.Unlock()
+
+	return func() {
+		h.mu.Lock()
+		delete(h.subs, id)
+		h.mu.Unlock()
+	}
+}
@@ -0,0 +1,59 @@
+// Package controlapi implements lazycap's opt-in local control bridge
+// (see cmd/lazycap's --api flag and the "apiEnabled" setting): a plain
+// HTTP API exposing the same plugin manager and core cap operations the
+// TUI uses, for IDE extensions, CI scripts, and other external tooling.
+package controlapi
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/icarus-itcs/lazycap/internal/notify"
+	"github.com/icarus-itcs/lazycap/internal/plugin"
+)
+
+// Server serves the control API against a single *plugin.AppContext - the
+// same instance wired into the TUI's plugin manager, so state (running
+// processes, enabled plugins) stays consistent between the two.
+type Server struct {
+	ctx   *plugin.AppContext
+	token string
+	hub   *sseHub
+}
+
+// New returns a Server bound to ctx, authenticating every request against
+// token.
+func New(ctx *plugin.AppContext, token string) *Server {
+	return &Server{ctx: ctx, token: token, hub: newSSEHub()}
+}
+
+// Notifier returns the Server's notify.Notifier, meant to be registered
+// with the same Dispatcher the rest of the app notifies through (see
+// runApp) so GET /events carries exactly the events the notifications
+// subsystem does, rather than a second parallel event feed.
+func (s *Server) Notifier() notify.Notifier { return s.hub }
+
+func (s *Server) mux() *http.ServeMux {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/devices", s.withAuth(s.handleDevices))
+	mux.HandleFunc("/plugins", s.withAuth(s.handlePlugins))
+	mux.HandleFunc("/plugins/", s.withAuth(s.handlePluginByID))
+	mux.HandleFunc("/build/", s.withAuth(s.handleBuild))
+	mux.HandleFunc("/events", s.withAuth(s.handleEvents))
+	return mux
+}
+
+// Serve starts the control API on addr and blocks, matching
+// metrics.Registry.Serve's semantics - callers run it in its own
+// goroutine.
+func (s *Server) Serve(addr string) error {
+	return http.ListenAndServe(addr, s.mux())
+}
+
+func writeJSON(w http.ResponseWriter, v interface{}) {
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	if err := json.NewEncoder(w).Encode(v); err != nil {
+		http.Error(w, fmt.Sprintf("failed to encode response: %v", err), http.StatusInternalServerError)
+	}
+}
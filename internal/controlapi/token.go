@@ -0,0 +1,43 @@
+package controlapi
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"time"
+
+	"github.com/icarus-itcs/lazycap/internal/settings"
+)
+
+// tokenSettingKey is the settings key the control API's bearer token is
+// persisted under, mirroring the MCP plugin's own "authToken" setting.
+const tokenSettingKey = "apiToken"
+
+func newToken() string {
+	buf := make([]byte, 32)
+	if _, err := rand.Read(buf); err != nil {
+		return fmt.Sprintf("token-%d", time.Now().UnixNano())
+	}
+	return hex.EncodeToString(buf)
+}
+
+// EnsureToken returns the control API's bearer token, generating and
+// persisting one on first use - generated reports whether a new token was
+// created, so the caller can print it once (Jupyter-style) instead of
+// every run.
+func EnsureToken(s *settings.Settings) (token string, generated bool) {
+	if existing := s.GetString(tokenSettingKey); existing != "" {
+		return existing, false
+	}
+	token = newToken()
+	s.SetString(tokenSettingKey, token)
+	return token, true
+}
+
+// RotateToken generates and persists a fresh token unconditionally, for
+// `lazycap api-token --rotate`.
+func RotateToken(s *settings.Settings) string {
+	token := newToken()
+	s.SetString(tokenSettingKey, token)
+	return token
+}
@@ -0,0 +1,73 @@
+package download
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// CacheDir returns ~/.lazycap/cache, creating it if needed - the root of
+// the sha256-prefixed store Pool.Get reads and writes under. Deliberately
+// separate from settings.ConfigDir() and from the plugin distribution
+// blob store (~/.lazycap/plugins/blobs), since this cache is disposable:
+// deleting it only costs a re-download.
+func CacheDir() (string, error) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", fmt.Errorf("failed to resolve user home directory: %w", err)
+	}
+	dir := filepath.Join(home, ".lazycap", "cache")
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return "", fmt.Errorf("failed to create download cache: %w", err)
+	}
+	return dir, nil
+}
+
+// cachePathFor resolves the on-disk path content should live at: sharded
+// by the first two hex characters of expectedHash (or, if the caller
+// doesn't know the hash up front, of a hash of url instead) to keep any
+// one cache directory from growing too large.
+func cachePathFor(expectedHash, url string) (string, error) {
+	dir, err := CacheDir()
+	if err != nil {
+		return "", err
+	}
+
+	digest := strings.ToLower(expectedHash)
+	if digest == "" {
+		sum := sha256.Sum256([]byte(url))
+		digest = hex.EncodeToString(sum[:])
+	}
+
+	prefix := digest
+	if len(prefix) > 2 {
+		prefix = prefix[:2]
+	}
+	shardDir := filepath.Join(dir, prefix)
+	if err := os.MkdirAll(shardDir, 0o755); err != nil {
+		return "", fmt.Errorf("failed to create cache shard %s: %w", shardDir, err)
+	}
+	return filepath.Join(shardDir, digest), nil
+}
+
+// verifyFileHash returns nil if path's sha256 matches expectedHash.
+func verifyFileHash(path, expectedHash string) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	hasher := sha256.New()
+	if _, err := io.Copy(hasher, f); err != nil {
+		return err
+	}
+	if sum := hex.EncodeToString(hasher.Sum(nil)); !strings.EqualFold(sum, expectedHash) {
+		return fmt.Errorf("cached file %s hash mismatch", path)
+	}
+	return nil
+}
@@ -0,0 +1,248 @@
+// Package download provides a bounded-concurrency, deduplicated HTTP
+// download pool shared by the update subsystem (internal/update) and the
+// plugin installer (internal/plugin's distribution.go): a cache-key-keyed
+// Pull that never runs two HTTP requests for the same content at once, and
+// a progress channel every caller attached to a job receives updates on.
+package download
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/icarus-itcs/lazycap/internal/settings"
+)
+
+// GenericProgress is one update in a download's progress stream - the
+// same shape as the debug package's per-action progress, so the TUI can
+// drive both kinds of progress bar with one widget.
+type GenericProgress struct {
+	Current int
+	Total   int
+	Message string
+}
+
+const defaultConcurrency = 4
+
+// result is what every caller attached to a job eventually receives.
+type result struct {
+	path string
+	size int64
+	err  error
+}
+
+// job tracks one in-flight download, keyed by cache key. Every Get call
+// for the same key while a job is running attaches its own progress
+// channel via subscribe and blocks on done, instead of starting a second
+// HTTP request.
+type job struct {
+	mu          sync.Mutex
+	progressChs []chan<- GenericProgress
+	done        chan struct{}
+	result      result
+}
+
+func (j *job) subscribe(ch chan<- GenericProgress) {
+	if ch == nil {
+		return
+	}
+	j.mu.Lock()
+	j.progressChs = append(j.progressChs, ch)
+	j.mu.Unlock()
+}
+
+// broadcast fans p out to every subscriber, dropping the update for a
+// subscriber whose channel is full rather than blocking the download on a
+// slow consumer.
+func (j *job) broadcast(p GenericProgress) {
+	j.mu.Lock()
+	chs := append([]chan<- GenericProgress(nil), j.progressChs...)
+	j.mu.Unlock()
+	for _, ch := range chs {
+		select {
+		case ch <- p:
+		default:
+		}
+	}
+}
+
+// Pool runs HTTP downloads with a bounded concurrency and in-flight
+// deduplication by cache key.
+type Pool struct {
+	sem        chan struct{}
+	httpClient *http.Client
+
+	mu   sync.Mutex
+	jobs map[string]*job
+}
+
+// NewPool returns a Pool that runs at most concurrency downloads at once.
+// concurrency <= 0 falls back to defaultConcurrency.
+func NewPool(concurrency int) *Pool {
+	if concurrency <= 0 {
+		concurrency = defaultConcurrency
+	}
+	return &Pool{
+		sem:        make(chan struct{}, concurrency),
+		httpClient: &http.Client{Timeout: 5 * time.Minute},
+		jobs:       make(map[string]*job),
+	}
+}
+
+var (
+	defaultPool     *Pool
+	defaultPoolOnce sync.Once
+)
+
+// DefaultPool returns a process-wide Pool sized from the
+// "downloadConcurrency" setting, shared by every caller in this process so
+// the update subsystem and plugin installer don't each open their own
+// independent flood of connections.
+func DefaultPool() *Pool {
+	defaultPoolOnce.Do(func() {
+		concurrency := defaultConcurrency
+		if s, err := settings.Load(); err == nil {
+			if n := s.GetInt("downloadConcurrency"); n > 0 {
+				concurrency = n
+			}
+		}
+		defaultPool = NewPool(concurrency)
+	})
+	return defaultPool
+}
+
+// Get fetches url into the content-addressed cache under expectedHash
+// (sha256, hex; see CacheDir), reporting progress to progressCh if
+// non-nil, and returns an open *os.File positioned at the start of the
+// cached content. key identifies this download for deduplication - a
+// second concurrent Get for the same key attaches to the job already in
+// flight instead of starting a new HTTP request, and both callers receive
+// independently-opened handles to the same result once it completes.
+// expectedHash may be empty for a download with no known checksum up
+// front, in which case the cache key on disk is derived from url instead
+// and no integrity check is performed.
+//
+// ctx governs the underlying HTTP request only for whichever caller
+// happens to start the job; callers that attach to an already-running job
+// ride along with that request and cannot cancel it independently.
+func (p *Pool) Get(ctx context.Context, key, url, expectedHash string, progressCh chan<- GenericProgress) (*os.File, int64, error) {
+	p.mu.Lock()
+	j, inFlight := p.jobs[key]
+	if !inFlight {
+		j = &job{done: make(chan struct{})}
+		p.jobs[key] = j
+	}
+	j.subscribe(progressCh)
+	p.mu.Unlock()
+
+	if !inFlight {
+		go p.run(ctx, key, url, expectedHash, j)
+	}
+
+	<-j.done
+	j.mu.Lock()
+	res := j.result
+	j.mu.Unlock()
+	if res.err != nil {
+		return nil, 0, res.err
+	}
+
+	f, err := os.Open(res.path)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to open cached download %s: %w", res.path, err)
+	}
+	return f, res.size, nil
+}
+
+func (p *Pool) run(ctx context.Context, key, url, expectedHash string, j *job) {
+	p.sem <- struct{}{}
+	path, size, err := fetchOrVerifyCached(ctx, p.httpClient, url, expectedHash, j)
+	<-p.sem
+
+	j.mu.Lock()
+	j.result = result{path: path, size: size, err: err}
+	j.mu.Unlock()
+	close(j.done)
+
+	p.mu.Lock()
+	delete(p.jobs, key)
+	p.mu.Unlock()
+}
+
+// fetchOrVerifyCached returns the on-disk path of url's content, serving
+// it from the cache when present and intact, otherwise streaming it to a
+// .tmp file (hashing as it writes and reporting progress to j) and
+// renaming into place once both the byte count and checksum check out.
+func fetchOrVerifyCached(ctx context.Context, client *http.Client, url, expectedHash string, j *job) (string, int64, error) {
+	dest, err := cachePathFor(expectedHash, url)
+	if err != nil {
+		return "", 0, err
+	}
+
+	if info, statErr := os.Stat(dest); statErr == nil {
+		if expectedHash == "" || verifyFileHash(dest, expectedHash) == nil {
+			return dest, info.Size(), nil
+		}
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return "", 0, fmt.Errorf("failed to build download request: %w", err)
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return "", 0, fmt.Errorf("download failed: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return "", 0, fmt.Errorf("download returned %s", resp.Status)
+	}
+
+	tmp := dest + ".tmp"
+	f, err := os.Create(tmp)
+	if err != nil {
+		return "", 0, fmt.Errorf("failed to create %s: %w", tmp, err)
+	}
+	defer os.Remove(tmp)
+
+	hasher := sha256.New()
+	var written int64
+	buf := make([]byte, 32*1024)
+	for {
+		n, readErr := resp.Body.Read(buf)
+		if n > 0 {
+			if _, writeErr := f.Write(buf[:n]); writeErr != nil {
+				f.Close()
+				return "", 0, fmt.Errorf("failed to write %s: %w", tmp, writeErr)
+			}
+			hasher.Write(buf[:n])
+			written += int64(n)
+			j.broadcast(GenericProgress{Current: int(written), Total: int(resp.ContentLength), Message: url})
+		}
+		if readErr == io.EOF {
+			break
+		}
+		if readErr != nil {
+			f.Close()
+			return "", 0, fmt.Errorf("download failed: %w", readErr)
+		}
+	}
+	f.Close()
+
+	if expectedHash != "" {
+		if sum := hex.EncodeToString(hasher.Sum(nil)); !strings.EqualFold(sum, expectedHash) {
+			return "", 0, fmt.Errorf("checksum mismatch: expected %s, got %s", expectedHash, sum)
+		}
+	}
+	if err := os.Rename(tmp, dest); err != nil {
+		return "", 0, fmt.Errorf("failed to finalize download: %w", err)
+	}
+	return dest, written, nil
+}
@@ -0,0 +1,174 @@
+// Package i18n translates lazycap's user-facing TUI strings. Bundles are
+// plain YAML files embedded at build time (internal/i18n/bundles/*.yaml);
+// the active language is resolved from an explicit override (the
+// "language" setting) or, failing that, $LC_MESSAGES/$LANG, falling back
+// to English for anything missing from either.
+//
+// This covers the header's pluralized device/process counts and a handful
+// of named status messages - not yet a full sweep of every string in
+// internal/ui, which remains English-only until bundle keys are added for
+// it.
+package i18n
+
+import (
+	"embed"
+	"fmt"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	"gopkg.in/yaml.v3"
+)
+
+//go:embed bundles/*.yaml
+var bundleFS embed.FS
+
+// entry is one translation key's value. Most keys are a plain string;
+// keys that vary with a count (the header's device/process totals) carry
+// separate "one"/"other" forms, matching the shape the YAML bundles use.
+type entry struct {
+	one   string
+	other string
+}
+
+func (e *entry) UnmarshalYAML(value *yaml.Node) error {
+	if value.Kind == yaml.ScalarNode {
+		return value.Decode(&e.other)
+	}
+	var plural struct {
+		One   string `yaml:"one"`
+		Other string `yaml:"other"`
+	}
+	if err := value.Decode(&plural); err != nil {
+		return err
+	}
+	e.one, e.other = plural.One, plural.Other
+	return nil
+}
+
+type catalog map[string]entry
+
+var (
+	mu       sync.Mutex
+	active   catalog
+	fallback catalog
+	dumping  bool
+	usedKeys = make(map[string]bool)
+)
+
+func init() {
+	fallback = loadBundle("en")
+	active = fallback
+}
+
+func loadBundle(lang string) catalog {
+	data, err := bundleFS.ReadFile("bundles/" + lang + ".yaml")
+	if err != nil {
+		return catalog{}
+	}
+	var raw catalog
+	if err := yaml.Unmarshal(data, &raw); err != nil {
+		return catalog{}
+	}
+	return raw
+}
+
+// DetectLanguage resolves the language to load: override if non-empty
+// (the "language" setting), else $LC_MESSAGES/$LANG (taking the part
+// before the first "_" or "." - e.g. "ja_JP.UTF-8" -> "ja"), else "en".
+func DetectLanguage(override string) string {
+	if override != "" {
+		return override
+	}
+	for _, v := range []string{os.Getenv("LC_MESSAGES"), os.Getenv("LANG")} {
+		if v == "" {
+			continue
+		}
+		if idx := strings.IndexAny(v, "_."); idx != -1 {
+			v = v[:idx]
+		}
+		return v
+	}
+	return "en"
+}
+
+// SetLanguage loads lang's bundle as the active catalog. Keys missing
+// from it still resolve via the English fallback in T/Plural. Unknown
+// languages (no matching bundle file) leave English active.
+func SetLanguage(lang string) {
+	mu.Lock()
+	defer mu.Unlock()
+	if lang == "" || lang == "en" {
+		active = fallback
+		return
+	}
+	if loaded := loadBundle(lang); len(loaded) > 0 {
+		active = loaded
+		return
+	}
+	active = fallback
+}
+
+// SetDumpKeys turns on recording of every key looked up via T/Plural, for
+// --dump-keys (see DumpKeys).
+func SetDumpKeys(enabled bool) { dumping = enabled }
+
+// DumpKeys returns every translation key looked up so far this session,
+// sorted, so a new bundle can be started from a real usage list instead
+// of grepping the source for i18n.T/.Plural calls.
+func DumpKeys() []string {
+	mu.Lock()
+	defer mu.Unlock()
+	keys := make([]string, 0, len(usedKeys))
+	for k := range usedKeys {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func lookup(key string) entry {
+	mu.Lock()
+	if dumping {
+		usedKeys[key] = true
+	}
+	e, ok := active[key]
+	if !ok {
+		e, ok = fallback[key]
+	}
+	mu.Unlock()
+	if !ok {
+		return entry{other: key}
+	}
+	return e
+}
+
+// T returns key's translation, formatted with args via fmt.Sprintf if any
+// are given. Unknown keys fall back to the key itself, so a missing
+// translation is visibly wrong rather than silently blank.
+func T(key string, args ...interface{}) string {
+	e := lookup(key)
+	if len(args) == 0 {
+		return e.other
+	}
+	return fmt.Sprintf(e.other, args...)
+}
+
+// Plural returns key's translation for count: its "one" form when
+// count == 1 and a "one" form exists, its "other" form otherwise, with
+// "{{count}}" substituted and any remaining args applied via
+// fmt.Sprintf.
+func Plural(key string, count int, args ...interface{}) string {
+	e := lookup(key)
+	tmpl := e.other
+	if count == 1 && e.one != "" {
+		tmpl = e.one
+	}
+	tmpl = strings.ReplaceAll(tmpl, "{{count}}", strconv.Itoa(count))
+	if len(args) == 0 {
+		return tmpl
+	}
+	return fmt.Sprintf(tmpl, args...)
+}
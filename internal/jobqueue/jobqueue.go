@@ -0,0 +1,285 @@
+// Package jobqueue implements a persistent, asynq-inspired job queue: jobs
+// can be enqueued immediately or scheduled for a future time, failed jobs
+// retry with exponential backoff up to MaxRetries before landing in a
+// dead-letter bucket, and every bucket survives a lazycap restart via a
+// JSON file under the user's config directory (see Path).
+package jobqueue
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/icarus-itcs/lazycap/internal/settings"
+)
+
+// State is which bucket a Job currently lives in.
+type State string
+
+const (
+	StatePending    State = "pending"     // runnable now, not yet picked up
+	StateInProgress State = "in_progress" // handed off to a worker
+	StateScheduled  State = "scheduled"   // ScheduledAt is in the future
+	StateRetry      State = "retry"       // failed, waiting out backoff until NextRetryAt
+	StateDead       State = "dead"        // exhausted MaxRetries
+	StateDone       State = "done"        // finished successfully
+)
+
+// Job is one unit of work: a shell command plus its queue bookkeeping.
+type Job struct {
+	ID      string   `json:"id"`
+	Command string   `json:"command"`
+	Args    []string `json:"args,omitempty"`
+	Dir     string   `json:"dir,omitempty"`
+
+	State State `json:"state"`
+
+	CreatedAt   time.Time `json:"created_at"`
+	ScheduledAt time.Time `json:"scheduled_at,omitempty"`
+	NextRetryAt time.Time `json:"next_retry_at,omitempty"`
+
+	Retries    int `json:"retries"`
+	MaxRetries int `json:"max_retries"`
+
+	LastError string `json:"last_error,omitempty"`
+}
+
+// EnqueueOptions configures a job at creation time.
+type EnqueueOptions struct {
+	Args       []string
+	Dir        string
+	MaxRetries int
+}
+
+// Store is the persistent backing store for a job queue: every job,
+// bucketed by State, serialized as a single JSON file (see Path). A JSON
+// file keeps this dependency-free - swapping in BoltDB later only touches
+// Open/saveLocked.
+type Store struct {
+	path string
+
+	mu   sync.Mutex
+	jobs map[string]*Job
+}
+
+// Path returns the JSON file jobs are persisted to, under
+// settings.ConfigDir().
+func Path() (string, error) {
+	dir, err := settings.ConfigDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "jobs.json"), nil
+}
+
+// Open loads the store from disk, starting empty if the file doesn't
+// exist yet.
+func Open() (*Store, error) {
+	path, err := Path()
+	if err != nil {
+		return nil, err
+	}
+	s := &Store{path: path, jobs: make(map[string]*Job)}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return s, nil
+		}
+		return nil, fmt.Errorf("failed to read %s: %w", path, err)
+	}
+
+	var jobs []*Job
+	if err := json.Unmarshal(data, &jobs); err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", path, err)
+	}
+	for _, j := range jobs {
+		s.jobs[j.ID] = j
+	}
+	return s, nil
+}
+
+func (s *Store) saveLocked() error {
+	jobs := make([]*Job, 0, len(s.jobs))
+	for _, j := range s.jobs {
+		jobs = append(jobs, j)
+	}
+	sort.Slice(jobs, func(i, k int) bool { return jobs[i].CreatedAt.Before(jobs[k].CreatedAt) })
+
+	data, err := json.MarshalIndent(jobs, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode jobs: %w", err)
+	}
+	if err := os.MkdirAll(filepath.Dir(s.path), 0o755); err != nil {
+		return fmt.Errorf("failed to create jobs directory: %w", err)
+	}
+	if err := os.WriteFile(s.path, data, 0o644); err != nil {
+		return fmt.Errorf("failed to write %s: %w", s.path, err)
+	}
+	return nil
+}
+
+var jobIDCounter uint64
+
+// newJobID returns a unique-enough ID: a nanosecond timestamp plus an
+// atomic counter, so two jobs enqueued in the same instant never collide.
+func newJobID() string {
+	n := atomic.AddUint64(&jobIDCounter, 1)
+	return fmt.Sprintf("job-%d-%d", time.Now().UnixNano(), n)
+}
+
+// Enqueue adds cmd as a Pending job, runnable immediately.
+func (s *Store) Enqueue(cmd string, opts EnqueueOptions) (*Job, error) {
+	return s.enqueueAt(cmd, time.Time{}, opts)
+}
+
+// EnqueueIn schedules cmd to become runnable after d elapses.
+func (s *Store) EnqueueIn(d time.Duration, cmd string, opts EnqueueOptions) (*Job, error) {
+	return s.enqueueAt(cmd, time.Now().Add(d), opts)
+}
+
+func (s *Store) enqueueAt(cmd string, at time.Time, opts EnqueueOptions) (*Job, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	j := &Job{
+		ID:         newJobID(),
+		Command:    cmd,
+		Args:       opts.Args,
+		Dir:        opts.Dir,
+		CreatedAt:  time.Now(),
+		MaxRetries: opts.MaxRetries,
+	}
+	if at.IsZero() {
+		j.State = StatePending
+	} else {
+		j.State = StateScheduled
+		j.ScheduledAt = at
+	}
+	s.jobs[j.ID] = j
+	return j, s.saveLocked()
+}
+
+// Cancel removes a job that isn't currently in progress.
+func (s *Store) Cancel(id string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	j, ok := s.jobs[id]
+	if !ok {
+		return fmt.Errorf("job %q not found", id)
+	}
+	if j.State == StateInProgress {
+		return fmt.Errorf("job %q is in progress and can't be cancelled from the queue", id)
+	}
+	delete(s.jobs, id)
+	return s.saveLocked()
+}
+
+// Reap permanently removes a dead job.
+func (s *Store) Reap(id string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	j, ok := s.jobs[id]
+	if !ok {
+		return fmt.Errorf("job %q not found", id)
+	}
+	if j.State != StateDead {
+		return fmt.Errorf("job %q is not dead (state %s)", id, j.State)
+	}
+	delete(s.jobs, id)
+	return s.saveLocked()
+}
+
+// Requeue moves a dead job back to Pending with its retry counter reset,
+// so the user can give it another chance from the "Dead" tab.
+func (s *Store) Requeue(id string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	j, ok := s.jobs[id]
+	if !ok {
+		return fmt.Errorf("job %q not found", id)
+	}
+	j.State = StatePending
+	j.Retries = 0
+	j.LastError = ""
+	j.ScheduledAt = time.Time{}
+	j.NextRetryAt = time.Time{}
+	return s.saveLocked()
+}
+
+// MarkInProgress flips a due job to InProgress, called by a worker when it
+// picks the job up to run it.
+func (s *Store) MarkInProgress(id string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	j, ok := s.jobs[id]
+	if !ok {
+		return fmt.Errorf("job %q not found", id)
+	}
+	j.State = StateInProgress
+	return s.saveLocked()
+}
+
+// MarkDone retires a successfully finished job.
+func (s *Store) MarkDone(id string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	j, ok := s.jobs[id]
+	if !ok {
+		return fmt.Errorf("job %q not found", id)
+	}
+	j.State = StateDone
+	return s.saveLocked()
+}
+
+// MarkFailed records a failure: if the job still has retries left it moves
+// to Retry with an exponential backoff delay (2^Retries seconds, capped at
+// 5 minutes); otherwise it lands in Dead.
+func (s *Store) MarkFailed(id string, cause error) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	j, ok := s.jobs[id]
+	if !ok {
+		return fmt.Errorf("job %q not found", id)
+	}
+	if cause != nil {
+		j.LastError = cause.Error()
+	}
+	j.Retries++
+	if j.Retries > j.MaxRetries {
+		j.State = StateDead
+		return s.saveLocked()
+	}
+	j.State = StateRetry
+	backoff := time.Duration(math.Pow(2, float64(j.Retries))) * time.Second
+	if backoff > 5*time.Minute {
+		backoff = 5 * time.Minute
+	}
+	j.NextRetryAt = time.Now().Add(backoff)
+	return s.saveLocked()
+}
+
+func (s *Store) list(state State) []*Job {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var out []*Job
+	for _, j := range s.jobs {
+		if j.State == state {
+			out = append(out, j)
+		}
+	}
+	sort.Slice(out, func(i, k int) bool { return out[i].CreatedAt.Before(out[k].CreatedAt) })
+	return out
+}
+
+func (s *Store) ListPending() []*Job    { return s.list(StatePending) }
+func (s *Store) ListInProgress() []*Job { return s.list(StateInProgress) }
+func (s *Store) ListScheduled() []*Job  { return s.list(StateScheduled) }
+func (s *Store) ListRetry() []*Job      { return s.list(StateRetry) }
+func (s *Store) ListDead() []*Job       { return s.list(StateDead) }
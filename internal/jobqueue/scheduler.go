@@ -0,0 +1,87 @@
+package jobqueue
+
+import "time"
+
+// Scheduler polls a Store at a fixed interval, promoting Scheduled and
+// Retry jobs whose time has come into Pending, and publishes each one on
+// Ready for a worker (e.g. the TUI's process runner) to pick up.
+type Scheduler struct {
+	store    *Store
+	interval time.Duration
+	ready    chan *Job
+
+	stop chan struct{}
+	done chan struct{}
+}
+
+// NewScheduler returns a Scheduler over store, polling every interval.
+func NewScheduler(store *Store, interval time.Duration) *Scheduler {
+	return &Scheduler{
+		store:    store,
+		interval: interval,
+		ready:    make(chan *Job, 16),
+		stop:     make(chan struct{}),
+		done:     make(chan struct{}),
+	}
+}
+
+// Ready delivers each job as it gets promoted to Pending.
+func (sch *Scheduler) Ready() <-chan *Job {
+	return sch.ready
+}
+
+// Start runs the poll loop in its own goroutine until Stop is called.
+func (sch *Scheduler) Start() {
+	go func() {
+		defer close(sch.done)
+		ticker := time.NewTicker(sch.interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-sch.stop:
+				return
+			case <-ticker.C:
+				sch.promoteDue()
+			}
+		}
+	}()
+}
+
+// Stop signals the poll loop to exit and waits for it to do so.
+func (sch *Scheduler) Stop() {
+	close(sch.stop)
+	<-sch.done
+}
+
+func (sch *Scheduler) promoteDue() {
+	sch.store.mu.Lock()
+	now := time.Now()
+	var due []*Job
+	for _, j := range sch.store.jobs {
+		switch j.State {
+		case StateScheduled:
+			if !j.ScheduledAt.After(now) {
+				j.State = StatePending
+				due = append(due, j)
+			}
+		case StateRetry:
+			if !j.NextRetryAt.After(now) {
+				j.State = StatePending
+				due = append(due, j)
+			}
+		}
+	}
+	if len(due) > 0 {
+		sch.store.saveLocked()
+	}
+	sch.store.mu.Unlock()
+
+	for _, j := range due {
+		select {
+		case sch.ready <- j:
+		default:
+			// Ready is a best-effort notification; a full buffer just
+			// means the next ListPending() call picks the job up anyway.
+		}
+	}
+}
@@ -0,0 +1,40 @@
+// Package keybindings is a small, process-wide registry that views
+// populate with the keys they bind so tooling (the cheatsheet generator,
+// help screens, i18n audits) has one place to discover them.
+package keybindings
+
+import "sync"
+
+// Binding describes a single keybinding in one view.
+type Binding struct {
+	View        string
+	Key         string
+	Description string
+	I18nKey     string
+}
+
+var (
+	mu       sync.Mutex
+	registry []Binding
+)
+
+// Register adds bindings for a view to the global registry. Views
+// typically call this from an init() so the registry is populated as
+// soon as the package is imported.
+func Register(view string, bindings ...Binding) {
+	mu.Lock()
+	defer mu.Unlock()
+	for _, b := range bindings {
+		b.View = view
+		registry = append(registry, b)
+	}
+}
+
+// All returns every registered binding across all views.
+func All() []Binding {
+	mu.Lock()
+	defer mu.Unlock()
+	out := make([]Binding, len(registry))
+	copy(out, registry)
+	return out
+}
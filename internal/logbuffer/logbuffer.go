@@ -0,0 +1,418 @@
+// Package logbuffer provides a fixed-size in-memory ring of recent log
+// lines backed by rotating gzip-compressed segments on disk, so a
+// long-running process's full output stays searchable long after it has
+// scrolled out of the in-memory window - without the O(n) slice-shift a
+// plain "keep the last N lines" trim costs on every line once the window
+// is full.
+package logbuffer
+
+import (
+	"bufio"
+	"compress/gzip"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Stream tags which file descriptor a Line originated from.
+type Stream string
+
+const (
+	Stdout Stream = "stdout"
+	Stderr Stream = "stderr"
+)
+
+// Line is one timestamped, stream-tagged log line.
+type Line struct {
+	Time   time.Time
+	Stream Stream
+	Text   string
+}
+
+// RetentionPolicy bounds how much on-disk history a LogBuffer keeps,
+// enforced on every segment rotation. A zero field means "unbounded" for
+// that dimension.
+type RetentionPolicy struct {
+	MaxBytes int64
+	MaxAge   time.Duration
+}
+
+// segmentMaxBytes is how large an uncompressed segment grows before
+// LogBuffer rotates to a new one.
+const segmentMaxBytes = 4 << 20 // 4MB uncompressed per segment
+
+// LogBuffer keeps the most recent lines in a fixed-size in-memory ring and
+// mirrors every line to rotating gzip segment files under dir, so history
+// that falls out of the ring is still reachable via Search/Range/Export.
+// Safe for concurrent use.
+type LogBuffer struct {
+	mu  sync.Mutex
+	dir string
+
+	window    []Line
+	windowCap int
+	head      int
+	count     int
+
+	retention RetentionPolicy
+
+	segIndex  int
+	segFile   *os.File
+	segGzip   *gzip.Writer
+	segWriter *bufio.Writer
+	segBytes  int64
+
+	subs      map[int]chan Line
+	nextSubID int
+}
+
+// Open creates (or reopens) a LogBuffer persisting to dir, keeping the
+// last windowSize lines in memory. dir is created if it doesn't exist.
+func Open(dir string, windowSize int, retention RetentionPolicy) (*LogBuffer, error) {
+	if windowSize < 1 {
+		windowSize = 1
+	}
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, fmt.Errorf("failed to create log directory %s: %w", dir, err)
+	}
+	b := &LogBuffer{
+		dir:       dir,
+		window:    make([]Line, windowSize),
+		windowCap: windowSize,
+		retention: retention,
+		subs:      make(map[int]chan Line),
+	}
+	b.segIndex = nextSegmentIndex(dir)
+	if err := b.openSegment(); err != nil {
+		return nil, err
+	}
+	return b, nil
+}
+
+// nextSegmentIndex picks up numbering where a previous run of segments in
+// dir left off, so reopening a LogBuffer for a reattached process doesn't
+// clobber its prior history.
+func nextSegmentIndex(dir string) int {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return 0
+	}
+	highest := -1
+	for _, e := range entries {
+		var n int
+		if _, err := fmt.Sscanf(e.Name(), "segment-%d.log.gz", &n); err == nil && n > highest {
+			highest = n
+		}
+	}
+	return highest + 1
+}
+
+func (b *LogBuffer) segmentPath(index int) string {
+	return filepath.Join(b.dir, fmt.Sprintf("segment-%d.log.gz", index))
+}
+
+func (b *LogBuffer) openSegment() error {
+	f, err := os.Create(b.segmentPath(b.segIndex))
+	if err != nil {
+		return fmt.Errorf("failed to create log segment: %w", err)
+	}
+	b.segFile = f
+	b.segGzip = gzip.NewWriter(f)
+	b.segWriter = bufio.NewWriter(b.segGzip)
+	b.segBytes = 0
+	return nil
+}
+
+func (b *LogBuffer) rotateSegmentLocked() error {
+	if err := b.flushSegmentLocked(); err != nil {
+		return err
+	}
+	b.segGzip.Close()
+	b.segFile.Close()
+	b.segIndex++
+	if err := b.openSegment(); err != nil {
+		return err
+	}
+	return b.enforceRetentionLocked()
+}
+
+func (b *LogBuffer) flushSegmentLocked() error {
+	if b.segWriter != nil {
+		if err := b.segWriter.Flush(); err != nil {
+			return err
+		}
+	}
+	if b.segGzip != nil {
+		return b.segGzip.Flush()
+	}
+	return nil
+}
+
+// Append records one line: into the in-memory ring, the current on-disk
+// segment, and every live Subscribe channel. A full subscriber channel
+// drops the line rather than blocking the writer - Subscribe is for live
+// tailing, not guaranteed delivery (Search/Range/Export read the durable
+// copy instead).
+func (b *LogBuffer) Append(stream Stream, text string) error {
+	line := Line{Time: time.Now(), Stream: stream, Text: text}
+
+	b.mu.Lock()
+	b.window[(b.head+b.count)%b.windowCap] = line
+	if b.count < b.windowCap {
+		b.count++
+	} else {
+		b.head = (b.head + 1) % b.windowCap
+	}
+
+	encoded := fmt.Sprintf("%s\t%s\t%s\n", line.Time.Format(time.RFC3339Nano), stream, text)
+	n, writeErr := b.segWriter.WriteString(encoded)
+	b.segBytes += int64(n)
+	var rotateErr error
+	if b.segBytes >= segmentMaxBytes {
+		rotateErr = b.rotateSegmentLocked()
+	} else {
+		b.flushSegmentLocked()
+	}
+
+	subs := make([]chan Line, 0, len(b.subs))
+	for _, ch := range b.subs {
+		subs = append(subs, ch)
+	}
+	b.mu.Unlock()
+
+	for _, ch := range subs {
+		select {
+		case ch <- line:
+		default:
+		}
+	}
+
+	if writeErr != nil {
+		return writeErr
+	}
+	return rotateErr
+}
+
+// Window returns a copy of the current in-memory ring, oldest first.
+func (b *LogBuffer) Window() []Line {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	out := make([]Line, b.count)
+	for i := 0; i < b.count; i++ {
+		out[i] = b.window[(b.head+i)%b.windowCap]
+	}
+	return out
+}
+
+// Subscribe returns a channel delivering every line appended from now on,
+// and a cancel func that unregisters it. Append never closes the channel;
+// callers must call cancel once they stop reading (e.g. when the detail
+// view switches away, or the owning process finishes) so it can be
+// garbage collected.
+func (b *LogBuffer) Subscribe() (<-chan Line, func()) {
+	b.mu.Lock()
+	id := b.nextSubID
+	b.nextSubID++
+	ch := make(chan Line, 256)
+	b.subs[id] = ch
+	b.mu.Unlock()
+
+	cancel := func() {
+		b.mu.Lock()
+		delete(b.subs, id)
+		b.mu.Unlock()
+	}
+	return ch, cancel
+}
+
+// segments lists this buffer's on-disk segment file names in chronological
+// (ascending index) order.
+func (b *LogBuffer) segments() []string {
+	entries, err := os.ReadDir(b.dir)
+	if err != nil {
+		return nil
+	}
+	type seg struct {
+		name string
+		n    int
+	}
+	var segs []seg
+	for _, e := range entries {
+		var n int
+		if _, err := fmt.Sscanf(e.Name(), "segment-%d.log.gz", &n); err == nil {
+			segs = append(segs, seg{e.Name(), n})
+		}
+	}
+	sort.Slice(segs, func(i, j int) bool { return segs[i].n < segs[j].n })
+	names := make([]string, len(segs))
+	for i, s := range segs {
+		names[i] = s.name
+	}
+	return names
+}
+
+// readSegment decompresses and parses every line of one gzip segment file.
+func readSegment(path string) ([]Line, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	gz, err := gzip.NewReader(f)
+	if err != nil {
+		return nil, err
+	}
+	defer gz.Close()
+
+	var lines []Line
+	scanner := bufio.NewScanner(gz)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1<<20)
+	for scanner.Scan() {
+		parts := strings.SplitN(scanner.Text(), "\t", 3)
+		if len(parts) != 3 {
+			continue
+		}
+		t, err := time.Parse(time.RFC3339Nano, parts[0])
+		if err != nil {
+			continue
+		}
+		lines = append(lines, Line{Time: t, Stream: Stream(parts[1]), Text: parts[2]})
+	}
+	return lines, scanner.Err()
+}
+
+// All returns every persisted line across every on-disk segment plus the
+// current in-memory window, oldest first. It's the shared base for
+// Search/Range/Export and isn't cheap for a long-lived process by design -
+// it's the offline-inspection path, not the live-tail one (see Subscribe).
+func (b *LogBuffer) All() ([]Line, error) {
+	b.mu.Lock()
+	err := b.flushSegmentLocked()
+	dir := b.dir
+	b.mu.Unlock()
+	if err != nil {
+		return nil, err
+	}
+
+	var out []Line
+	for _, name := range b.segments() {
+		lines, err := readSegment(filepath.Join(dir, name))
+		if err != nil {
+			return nil, fmt.Errorf("failed to read %s: %w", name, err)
+		}
+		out = append(out, lines...)
+	}
+	return append(out, b.Window()...), nil
+}
+
+// Search returns every persisted line whose text matches re, oldest first.
+func (b *LogBuffer) Search(re *regexp.Regexp) ([]Line, error) {
+	all, err := b.All()
+	if err != nil {
+		return nil, err
+	}
+	var out []Line
+	for _, l := range all {
+		if re.MatchString(l.Text) {
+			out = append(out, l)
+		}
+	}
+	return out, nil
+}
+
+// Range returns every persisted line with Time within [from, to].
+func (b *LogBuffer) Range(from, to time.Time) ([]Line, error) {
+	all, err := b.All()
+	if err != nil {
+		return nil, err
+	}
+	var out []Line
+	for _, l := range all {
+		if l.Time.Before(from) || l.Time.After(to) {
+			continue
+		}
+		out = append(out, l)
+	}
+	return out, nil
+}
+
+// Export writes every persisted line to w as "<RFC3339Nano> <stream> <text>".
+func (b *LogBuffer) Export(w io.Writer) error {
+	all, err := b.All()
+	if err != nil {
+		return err
+	}
+	bw := bufio.NewWriter(w)
+	for _, l := range all {
+		if _, err := fmt.Fprintf(bw, "%s %s %s\n", l.Time.Format(time.RFC3339Nano), l.Stream, l.Text); err != nil {
+			return err
+		}
+	}
+	return bw.Flush()
+}
+
+// enforceRetentionLocked deletes the oldest segments until the retention
+// policy's MaxBytes/MaxAge are satisfied again. Called after each
+// rotation; the current (just-opened) segment is never a deletion
+// candidate since it isn't in segments()'s listing until it has content
+// and a later rotation runs.
+func (b *LogBuffer) enforceRetentionLocked() error {
+	if b.retention.MaxBytes == 0 && b.retention.MaxAge == 0 {
+		return nil
+	}
+	names := b.segments()
+
+	type info struct {
+		name    string
+		size    int64
+		modTime time.Time
+	}
+	var infos []info
+	var total int64
+	for _, name := range names {
+		fi, err := os.Stat(filepath.Join(b.dir, name))
+		if err != nil {
+			continue
+		}
+		infos = append(infos, info{name, fi.Size(), fi.ModTime()})
+		total += fi.Size()
+	}
+
+	now := time.Now()
+	for _, in := range infos {
+		tooOld := b.retention.MaxAge > 0 && now.Sub(in.modTime) > b.retention.MaxAge
+		tooBig := b.retention.MaxBytes > 0 && total > b.retention.MaxBytes
+		if !tooOld && !tooBig {
+			continue
+		}
+		if err := os.Remove(filepath.Join(b.dir, in.name)); err != nil {
+			continue
+		}
+		total -= in.size
+	}
+	return nil
+}
+
+// Close flushes and closes the current segment file. It does not cancel
+// outstanding Subscribe channels; callers holding one should still call
+// their own cancel func.
+func (b *LogBuffer) Close() error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if err := b.flushSegmentLocked(); err != nil {
+		return err
+	}
+	if b.segGzip != nil {
+		b.segGzip.Close()
+	}
+	if b.segFile != nil {
+		return b.segFile.Close()
+	}
+	return nil
+}
@@ -0,0 +1,157 @@
+// Package logparse turns raw process output lines into structured Entry
+// values: a severity level, an optional timestamp, and an optional
+// file:line[:col] reference so the UI can filter by severity, jump
+// between errors, and open the referenced file in an editor.
+package logparse
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Level is the severity classification assigned to a log line.
+type Level int
+
+const (
+	LevelInfo Level = iota
+	LevelWarn
+	LevelError
+)
+
+// String returns the short, lowercase name used for filter labels.
+func (l Level) String() string {
+	switch l {
+	case LevelWarn:
+		return "warn"
+	case LevelError:
+		return "error"
+	default:
+		return "info"
+	}
+}
+
+// FileRef is a file:line[:col] reference extracted from a log line, as
+// emitted by tools like tsc, vite, eslint, Xcode, and Gradle/kotlinc.
+type FileRef struct {
+	Path string
+	Line int
+	Col  int
+}
+
+// String formats the reference the way it's conventionally displayed,
+// omitting the column when one wasn't found.
+func (r FileRef) String() string {
+	if r.Col > 0 {
+		return fmt.Sprintf("%s:%d:%d", r.Path, r.Line, r.Col)
+	}
+	return fmt.Sprintf("%s:%d", r.Path, r.Line)
+}
+
+// Entry is a single parsed log line.
+type Entry struct {
+	Raw          string
+	Clean        string
+	Level        Level
+	Timestamp    time.Time
+	HasTimestamp bool
+	FileRef      *FileRef
+}
+
+var (
+	timestampRegex = regexp.MustCompile(`^\[?(\d{2}:\d{2}:\d{2}(?:\.\d{3})?)\]?\s*`)
+	errorRegex     = regexp.MustCompile(`(?i)\b(error|fatal|exception|failed?)\b|✗`)
+	warnRegex      = regexp.MustCompile(`(?i)\b(warn(?:ing)?|deprecated)\b`)
+
+	// Vue/TS/Vite/ESLint style: src/App.vue:12:34
+	webRefRegex = regexp.MustCompile(`([\w./\-]+\.(?:ts|tsx|js|jsx|vue|mjs|cjs)):(\d+)(?::(\d+))?`)
+	// Xcode/clang style: /path/File.swift:12:5: error: ...
+	xcodeRefRegex = regexp.MustCompile(`([\w./\-]+\.(?:swift|m|mm|h)):(\d+):(\d+)`)
+	// Gradle/kotlinc style: e: /path/File.kt: (12, 5)
+	gradleRefRegex = regexp.MustCompile(`([\w./\-]+\.(?:kt|java)):\s*\((\d+),\s*(\d+)\)`)
+)
+
+var devServerURLRegex = regexp.MustCompile(`(https?://[^\s]+)`)
+
+// DevServerURLMatcher watches a stream of log lines for the "Local:" /
+// "Network:" URL lines that Vite (and compatible dev servers) print on
+// startup, for the live-reload URL/QR overlay. It's stateful only in that
+// it remembers the most recent match of each kind; callers re-scan every
+// new line.
+type DevServerURLMatcher struct {
+	Local   string
+	Network string
+}
+
+// Feed inspects one log line and records it if it's a Local:/Network: dev
+// server URL line, returning true when it found one.
+func (d *DevServerURLMatcher) Feed(line string) bool {
+	lower := strings.ToLower(line)
+	m := devServerURLRegex.FindString(line)
+	if m == "" {
+		return false
+	}
+	switch {
+	case strings.Contains(lower, "local:"):
+		d.Local = m
+	case strings.Contains(lower, "network:"):
+		d.Network = m
+	default:
+		return false
+	}
+	return true
+}
+
+// PreferredURL returns the LAN-reachable URL for QR display, preferring
+// Network: (reachable from a physical device) over Local:.
+func (d *DevServerURLMatcher) PreferredURL() string {
+	if d.Network != "" {
+		return d.Network
+	}
+	return d.Local
+}
+
+// Parse classifies a single (already ANSI-stripped) log line.
+func Parse(line string) Entry {
+	e := Entry{Raw: line, Clean: line}
+
+	if m := timestampRegex.FindStringSubmatch(line); m != nil {
+		if ts, err := time.Parse("15:04:05.000", m[1]); err == nil {
+			e.Timestamp, e.HasTimestamp = ts, true
+		} else if ts, err := time.Parse("15:04:05", m[1]); err == nil {
+			e.Timestamp, e.HasTimestamp = ts, true
+		}
+	}
+
+	switch {
+	case errorRegex.MatchString(line):
+		e.Level = LevelError
+	case warnRegex.MatchString(line):
+		e.Level = LevelWarn
+	default:
+		e.Level = LevelInfo
+	}
+
+	if m := gradleRefRegex.FindStringSubmatch(line); m != nil {
+		ref := &FileRef{Path: m[1]}
+		ref.Line, _ = strconv.Atoi(m[2])
+		ref.Col, _ = strconv.Atoi(m[3])
+		e.FileRef = ref
+	} else if m := xcodeRefRegex.FindStringSubmatch(line); m != nil {
+		ref := &FileRef{Path: m[1]}
+		ref.Line, _ = strconv.Atoi(m[2])
+		ref.Col, _ = strconv.Atoi(m[3])
+		e.FileRef = ref
+	} else if m := webRefRegex.FindStringSubmatch(line); m != nil {
+		ref := &FileRef{Path: m[1]}
+		ref.Line, _ = strconv.Atoi(m[2])
+		if m[3] != "" {
+			ref.Col, _ = strconv.Atoi(m[3])
+		}
+		e.FileRef = ref
+	}
+
+	return e
+}
@@ -0,0 +1,183 @@
+// Package metrics collects the small set of gauges/counters/histograms
+// lazycap exposes about its own process lifecycle (running processes,
+// durations, failures, online devices, preflight errors) and renders them
+// in Prometheus text exposition format, either over HTTP or to stdout.
+package metrics
+
+import (
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// durationBuckets are the histogram bucket boundaries (in seconds) used
+// for lazycap_process_duration_seconds - covering quick syncs up through
+// long-running builds.
+var durationBuckets = []float64{0.5, 1, 2.5, 5, 10, 30, 60, 120, 300, 600}
+
+type histogram struct {
+	counts []uint64
+	sum    float64
+	count  uint64
+}
+
+// Registry is a minimal, dependency-free Prometheus collector: just
+// enough gauge/counter/histogram bookkeeping to back lazycap's
+// /metrics endpoint without pulling in the full client_golang stack for a
+// handful of series.
+type Registry struct {
+	mu         sync.Mutex
+	gauges     map[string]map[string]float64
+	counters   map[string]map[string]float64
+	histograms map[string]*histogram
+}
+
+// NewRegistry returns an empty Registry.
+func NewRegistry() *Registry {
+	return &Registry{
+		gauges:     make(map[string]map[string]float64),
+		counters:   make(map[string]map[string]float64),
+		histograms: make(map[string]*histogram),
+	}
+}
+
+func labelKey(labels map[string]string) string {
+	if len(labels) == 0 {
+		return ""
+	}
+	keys := make([]string, 0, len(labels))
+	for k := range labels {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	var b strings.Builder
+	b.WriteByte('{')
+	for i, k := range keys {
+		if i > 0 {
+			b.WriteByte(',')
+		}
+		fmt.Fprintf(&b, "%s=%q", k, labels[k])
+	}
+	b.WriteByte('}')
+	return b.String()
+}
+
+// SetGauge sets name{labels} to value, replacing whatever was there.
+func (r *Registry) SetGauge(name string, labels map[string]string, value float64) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.gauges[name] == nil {
+		r.gauges[name] = make(map[string]float64)
+	}
+	r.gauges[name][labelKey(labels)] = value
+}
+
+// IncCounter increments name{labels} by one.
+func (r *Registry) IncCounter(name string, labels map[string]string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.counters[name] == nil {
+		r.counters[name] = make(map[string]float64)
+	}
+	r.counters[name][labelKey(labels)]++
+}
+
+// ObserveHistogram records value (in the same unit as durationBuckets,
+// i.e. seconds) against name's histogram.
+func (r *Registry) ObserveHistogram(name string, value float64) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	h, ok := r.histograms[name]
+	if !ok {
+		h = &histogram{counts: make([]uint64, len(durationBuckets))}
+		r.histograms[name] = h
+	}
+	h.sum += value
+	h.count++
+	for i, bucket := range durationBuckets {
+		if value <= bucket {
+			h.counts[i]++
+		}
+	}
+}
+
+// Render writes every collected metric in Prometheus text exposition
+// format - what /metrics serves, and what --metrics-stdout prints.
+func (r *Registry) Render() string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var b strings.Builder
+
+	gaugeNames := make([]string, 0, len(r.gauges))
+	for name := range r.gauges {
+		gaugeNames = append(gaugeNames, name)
+	}
+	sort.Strings(gaugeNames)
+	for _, name := range gaugeNames {
+		series := r.gauges[name]
+		labelKeys := make([]string, 0, len(series))
+		for lk := range series {
+			labelKeys = append(labelKeys, lk)
+		}
+		sort.Strings(labelKeys)
+		for _, lk := range labelKeys {
+			fmt.Fprintf(&b, "%s%s %g\n", name, lk, series[lk])
+		}
+	}
+
+	counterNames := make([]string, 0, len(r.counters))
+	for name := range r.counters {
+		counterNames = append(counterNames, name)
+	}
+	sort.Strings(counterNames)
+	for _, name := range counterNames {
+		series := r.counters[name]
+		labelKeys := make([]string, 0, len(series))
+		for lk := range series {
+			labelKeys = append(labelKeys, lk)
+		}
+		sort.Strings(labelKeys)
+		for _, lk := range labelKeys {
+			fmt.Fprintf(&b, "%s_total%s %g\n", name, lk, series[lk])
+		}
+	}
+
+	histNames := make([]string, 0, len(r.histograms))
+	for name := range r.histograms {
+		histNames = append(histNames, name)
+	}
+	sort.Strings(histNames)
+	for _, name := range histNames {
+		h := r.histograms[name]
+		var cumulative uint64
+		for i, bucket := range durationBuckets {
+			cumulative += h.counts[i]
+			fmt.Fprintf(&b, "%s_bucket{le=\"%g\"} %d\n", name, bucket, cumulative)
+		}
+		fmt.Fprintf(&b, "%s_bucket{le=\"+Inf\"} %d\n", name, h.count)
+		fmt.Fprintf(&b, "%s_sum %g\n", name, h.sum)
+		fmt.Fprintf(&b, "%s_count %d\n", name, h.count)
+	}
+
+	return b.String()
+}
+
+// Handler serves Render() at the standard Prometheus content type.
+func (r *Registry) Handler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		w.Header().Set("Content-Type", "text/plain; version=0.0.4")
+		fmt.Fprint(w, r.Render())
+	})
+}
+
+// Serve starts an HTTP server exposing /metrics on addr (e.g. ":9090")
+// and blocks, matching http.ListenAndServe's semantics - callers run it
+// in its own goroutine.
+func (r *Registry) Serve(addr string) error {
+	mux := http.NewServeMux()
+	mux.Handle("/metrics", r.Handler())
+	return http.ListenAndServe(addr, mux)
+}
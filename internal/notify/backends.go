@@ -0,0 +1,135 @@
+package notify
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/gen2brain/beeep"
+)
+
+// webhookTimeout bounds how long a single backend HTTP POST can take, so
+// a hung endpoint can't wedge the goroutine Dispatcher.Send spawned for it.
+const webhookTimeout = 10 * time.Second
+
+// NoopNotifier discards every event - the default when no backends are
+// configured, and useful for tests.
+type NoopNotifier struct{}
+
+func (NoopNotifier) Name() string                      { return "noop" }
+func (NoopNotifier) Send(context.Context, Event) error { return nil }
+
+// DesktopNotifier shows a native OS notification via beeep.
+type DesktopNotifier struct{}
+
+func (DesktopNotifier) Name() string { return "desktop" }
+
+func (DesktopNotifier) Send(_ context.Context, event Event) error {
+	return beeep.Notify(event.Title, event.Message, "")
+}
+
+// WebhookNotifier POSTs a generic JSON body to URL. Used directly for
+// "webhook" backends, and reused by Slack/Discord/ntfy for the actual
+// HTTP delivery once they've built their own payload.
+type WebhookNotifier struct {
+	URL string
+}
+
+func (w *WebhookNotifier) Name() string { return "webhook:" + w.URL }
+
+func (w *WebhookNotifier) Send(ctx context.Context, event Event) error {
+	body, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+	return postJSON(ctx, w.URL, body)
+}
+
+// SlackNotifier posts to a Slack incoming webhook URL, using Slack's
+// {"text": "..."} payload shape.
+type SlackNotifier struct {
+	URL string
+}
+
+func (s *SlackNotifier) Name() string { return "slack" }
+
+func (s *SlackNotifier) Send(ctx context.Context, event Event) error {
+	body, err := json.Marshal(struct {
+		Text string `json:"text"`
+	}{fmt.Sprintf("*%s*\n%s", event.Title, event.Message)})
+	if err != nil {
+		return err
+	}
+	return postJSON(ctx, s.URL, body)
+}
+
+// DiscordNotifier posts to a Discord incoming webhook URL, using
+// Discord's {"content": "..."} payload shape.
+type DiscordNotifier struct {
+	URL string
+}
+
+func (d *DiscordNotifier) Name() string { return "discord" }
+
+func (d *DiscordNotifier) Send(ctx context.Context, event Event) error {
+	body, err := json.Marshal(struct {
+		Content string `json:"content"`
+	}{fmt.Sprintf("**%s**\n%s", event.Title, event.Message)})
+	if err != nil {
+		return err
+	}
+	return postJSON(ctx, d.URL, body)
+}
+
+// NtfyNotifier publishes to an ntfy.sh (or self-hosted ntfy) topic URL by
+// POSTing the message body directly, with Title/Priority as headers - per
+// ntfy's plain-text publish API, no JSON envelope.
+type NtfyNotifier struct {
+	URL string // full topic URL, e.g. https://ntfy.sh/my-topic
+}
+
+func (n *NtfyNotifier) Name() string { return "ntfy" }
+
+func (n *NtfyNotifier) Send(ctx context.Context, event Event) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, n.URL, bytes.NewReader([]byte(event.Message)))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Title", event.Title)
+	if event.Severity == SeverityError {
+		req.Header.Set("Priority", "high")
+	}
+
+	client := &http.Client{Timeout: webhookTimeout}
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("ntfy returned status %d", resp.StatusCode)
+	}
+	return nil
+}
+
+func postJSON(ctx context.Context, url string, body []byte) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	client := &http.Client{Timeout: webhookTimeout}
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("%s returned status %d", url, resp.StatusCode)
+	}
+	return nil
+}
@@ -0,0 +1,98 @@
+package notify
+
+import (
+	"fmt"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// BackendConfig is one entry of the "notifications" settings value: which
+// backend to use, its URL (for webhook/slack/discord/ntfy), and the
+// filter narrowing which events it receives.
+type BackendConfig struct {
+	Backend         string   `yaml:"backend"`
+	URL             string   `yaml:"url"`
+	Kinds           []string `yaml:"kinds"`
+	MinSeverity     string   `yaml:"minSeverity"`
+	DebounceSeconds int      `yaml:"debounceSeconds"`
+}
+
+// BuildDispatcher parses raw - the "notifications" settings value, as
+// decoded by settings.Settings from config.yml into interface{} - into a
+// Dispatcher with one backend registered per entry. An entry with an
+// unknown backend name or a missing required url is skipped and reported
+// in the returned errs rather than failing the whole config, so one bad
+// entry doesn't silence the rest.
+func BuildDispatcher(raw interface{}, onErr func(backend string, err error)) (*Dispatcher, []error) {
+	d := NewDispatcher(onErr)
+	if raw == nil {
+		return d, nil
+	}
+
+	// raw is already a map[string]interface{}/[]interface{} tree from
+	// settings' own YAML decode - round-trip it through yaml.Marshal and
+	// back into []BackendConfig instead of hand-walking interface{}.
+	data, err := yaml.Marshal(raw)
+	if err != nil {
+		return d, []error{fmt.Errorf("failed to parse notifications config: %w", err)}
+	}
+	var entries []BackendConfig
+	if err := yaml.Unmarshal(data, &entries); err != nil {
+		return d, []error{fmt.Errorf("failed to parse notifications config: %w", err)}
+	}
+
+	var errs []error
+	for i, entry := range entries {
+		notifier, err := entry.build()
+		if err != nil {
+			errs = append(errs, fmt.Errorf("notifications[%d]: %w", i, err))
+			continue
+		}
+		d.Register(notifier, entry.filter())
+	}
+	return d, errs
+}
+
+func (c BackendConfig) build() (Notifier, error) {
+	switch c.Backend {
+	case "desktop":
+		return DesktopNotifier{}, nil
+	case "webhook":
+		if c.URL == "" {
+			return nil, fmt.Errorf("webhook backend requires a url")
+		}
+		return &WebhookNotifier{URL: c.URL}, nil
+	case "slack":
+		if c.URL == "" {
+			return nil, fmt.Errorf("slack backend requires a url")
+		}
+		return &SlackNotifier{URL: c.URL}, nil
+	case "discord":
+		if c.URL == "" {
+			return nil, fmt.Errorf("discord backend requires a url")
+		}
+		return &DiscordNotifier{URL: c.URL}, nil
+	case "ntfy":
+		if c.URL == "" {
+			return nil, fmt.Errorf("ntfy backend requires a url")
+		}
+		return &NtfyNotifier{URL: c.URL}, nil
+	case "noop", "":
+		return NoopNotifier{}, nil
+	default:
+		return nil, fmt.Errorf("unknown backend %q", c.Backend)
+	}
+}
+
+func (c BackendConfig) filter() Filter {
+	kinds := make([]Kind, len(c.Kinds))
+	for i, k := range c.Kinds {
+		kinds[i] = Kind(k)
+	}
+	var debounce time.Duration
+	if c.DebounceSeconds > 0 {
+		debounce = time.Duration(c.DebounceSeconds) * time.Second
+	}
+	return Filter{Kinds: kinds, MinSeverity: Severity(c.MinSeverity), Debounce: debounce}
+}
@@ -0,0 +1,134 @@
+package notify
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// Filter narrows which Events a backend receives: only the listed Kinds
+// (any kind, if empty), only at or above MinSeverity, and at most once
+// per Debounce window per Kind.
+type Filter struct {
+	Kinds       []Kind
+	MinSeverity Severity
+	Debounce    time.Duration
+}
+
+func (f Filter) matches(e Event) bool {
+	if len(f.Kinds) > 0 {
+		found := false
+		for _, k := range f.Kinds {
+			if k == e.Kind {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	if f.MinSeverity != "" && severityRank[e.Severity] < severityRank[f.MinSeverity] {
+		return false
+	}
+	return true
+}
+
+// registration pairs a Notifier with its Filter and the per-Kind debounce
+// state needed to enforce Filter.Debounce.
+type registration struct {
+	notifier Notifier
+	filter   Filter
+
+	mu       sync.Mutex
+	lastSent map[Kind]time.Time
+}
+
+func (r *registration) debounced(kind Kind, now time.Time) bool {
+	if r.filter.Debounce <= 0 {
+		return false
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if last, ok := r.lastSent[kind]; ok && now.Sub(last) < r.filter.Debounce {
+		return true
+	}
+	r.lastSent[kind] = now
+	return false
+}
+
+// Dispatcher fans an Event out to every registered backend whose Filter
+// matches it, each delivery best-effort - one backend erroring never
+// blocks or fails delivery to the others.
+type Dispatcher struct {
+	mu    sync.Mutex
+	regs  []*registration
+	onErr func(backend string, err error)
+}
+
+// NewDispatcher returns an empty Dispatcher. onErr, if non-nil, is called
+// (from a goroutine, so it must be concurrency-safe) whenever a backend's
+// Send fails.
+func NewDispatcher(onErr func(backend string, err error)) *Dispatcher {
+	return &Dispatcher{onErr: onErr}
+}
+
+// Register adds a backend to the fan-out, gated by filter.
+func (d *Dispatcher) Register(n Notifier, filter Filter) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.regs = append(d.regs, &registration{notifier: n, filter: filter, lastSent: make(map[Kind]time.Time)})
+}
+
+// Send dispatches event to every registered backend whose filter matches
+// and isn't currently debounced, each in its own goroutine so a slow
+// webhook can't block the caller (typically the UI's update loop).
+func (d *Dispatcher) Send(ctx context.Context, event Event) {
+	if d == nil {
+		return
+	}
+	d.mu.Lock()
+	regs := append([]*registration(nil), d.regs...)
+	d.mu.Unlock()
+
+	now := time.Now()
+	for _, r := range regs {
+		if !r.filter.matches(event) || r.debounced(event.Kind, now) {
+			continue
+		}
+		go func(r *registration) {
+			if err := r.notifier.Send(ctx, event); err != nil && d.onErr != nil {
+				d.onErr(r.notifier.Name(), err)
+			}
+		}(r)
+	}
+}
+
+// SendSync dispatches event to every registered backend - ignoring
+// Filter and debounce, since the caller (lazycap notify test) wants to
+// validate every backend on demand - and waits for all of them, returning
+// each backend's error (nil on success) keyed by name.
+func (d *Dispatcher) SendSync(ctx context.Context, event Event) map[string]error {
+	if d == nil {
+		return nil
+	}
+	d.mu.Lock()
+	regs := append([]*registration(nil), d.regs...)
+	d.mu.Unlock()
+
+	results := make(map[string]error, len(regs))
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	for _, r := range regs {
+		wg.Add(1)
+		go func(r *registration) {
+			defer wg.Done()
+			err := r.notifier.Send(ctx, event)
+			mu.Lock()
+			results[r.notifier.Name()] = err
+			mu.Unlock()
+		}(r)
+	}
+	wg.Wait()
+	return results
+}
@@ -0,0 +1,54 @@
+// Package notify fans out long-running app events (a build finishing, a
+// live-reload process crashing, a device going offline) to whatever
+// external channels the user configured, modeled after Apprise: a single
+// Notifier interface with a handful of pluggable backends, and a
+// Dispatcher that sends to every matching one, best-effort.
+package notify
+
+import "context"
+
+// Severity ranks how urgent an Event is, used by Filter.MinSeverity to
+// decide whether a backend should receive it.
+type Severity string
+
+const (
+	SeverityInfo  Severity = "info"
+	SeverityWarn  Severity = "warn"
+	SeverityError Severity = "error"
+)
+
+// severityRank orders Severity for min-severity comparisons.
+var severityRank = map[Severity]int{
+	SeverityInfo:  0,
+	SeverityWarn:  1,
+	SeverityError: 2,
+}
+
+// Kind identifies what kind of event occurred, so a Filter can narrow a
+// backend to a subset (e.g. only build failures).
+type Kind string
+
+const (
+	KindBuildSucceeded     Kind = "build.succeeded"
+	KindBuildFailed        Kind = "build.failed"
+	KindProcessCrashed     Kind = "process.crashed"
+	KindFirebaseDown       Kind = "firebase.down"
+	KindDeviceDisconnected Kind = "device.disconnected"
+	KindTest               Kind = "test"
+)
+
+// Event is the payload delivered to every matching Notifier.
+type Event struct {
+	Kind     Kind     `json:"kind"`
+	Severity Severity `json:"severity"`
+	Title    string   `json:"title"`
+	Message  string   `json:"message"`
+}
+
+// Notifier delivers an Event to one external channel (desktop, a
+// webhook, Slack, ...). Send should block until delivery is attempted and
+// return the failure, if any - the Dispatcher decides what to do with it.
+type Notifier interface {
+	Name() string
+	Send(ctx context.Context, event Event) error
+}
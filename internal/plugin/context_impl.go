@@ -1,6 +1,7 @@
 package plugin
 
 import (
+	"context"
 	"fmt"
 	"sync"
 	"time"
@@ -8,16 +9,13 @@ import (
 	"github.com/icarus-itcs/lazycap/internal/cap"
 	"github.com/icarus-itcs/lazycap/internal/debug"
 	"github.com/icarus-itcs/lazycap/internal/device"
+	"github.com/icarus-itcs/lazycap/internal/download"
+	"github.com/icarus-itcs/lazycap/internal/notify"
+	"github.com/icarus-itcs/lazycap/internal/preflight"
 	"github.com/icarus-itcs/lazycap/internal/settings"
+	"github.com/icarus-itcs/lazycap/internal/update"
 )
 
-// PluginLogEntry represents a log entry from a plugin
-type PluginLogEntry struct {
-	PluginID string
-	Message  string
-	Time     time.Time
-}
-
 // AppContext implements Context interface for the main application
 // This bridges the plugin system with the UI model
 type AppContext struct {
@@ -27,6 +25,7 @@ type AppContext struct {
 	project  *cap.Project
 	settings *settings.Settings
 	manager  *Manager
+	notifier *notify.Dispatcher
 
 	// Callbacks to UI (set by the UI)
 	onGetDevices        func() []device.Device
@@ -45,24 +44,33 @@ type AppContext struct {
 	// Process logs cache
 	processLogs map[string][]string
 
-	// Plugin log channel for async log delivery to UI
-	logChan chan PluginLogEntry
+	// Structured plugin logging: per-plugin ring buffers plus the
+	// fan-out subscriptions SubscribeLogs registers against them (see
+	// logging.go).
+	logs *logRegistry
+
+	// preflightWatcher is the live fsnotify watch started by
+	// WatchPreflight, if any - stopped and replaced if WatchPreflight is
+	// called again (e.g. the project directory changed).
+	preflightWatcher *preflight.Watcher
+
+	// pluginSchemas holds each plugin's registered settings.Schema (see
+	// RegisterPluginSchema), keyed by plugin ID. GetPluginSetting/
+	// SetPluginSetting look it up before touching the namespaced
+	// "plugins.<pluginID>.<key>" setting.
+	pluginSchemas map[string]*settings.Schema
 }
 
 // NewAppContext creates a new application context
 func NewAppContext(manager *Manager) *AppContext {
 	return &AppContext{
-		manager:     manager,
-		processLogs: make(map[string][]string),
-		logChan:     make(chan PluginLogEntry, 100), // Buffered channel for logs
+		manager:       manager,
+		processLogs:   make(map[string][]string),
+		logs:          newLogRegistry(),
+		pluginSchemas: make(map[string]*settings.Schema),
 	}
 }
 
-// GetLogChannel returns the log channel for the UI to consume
-func (c *AppContext) GetLogChannel() <-chan PluginLogEntry {
-	return c.logChan
-}
-
 // SetProject sets the current project
 func (c *AppContext) SetProject(project *cap.Project) {
 	c.mu.Lock()
@@ -77,6 +85,26 @@ func (c *AppContext) SetSettings(s *settings.Settings) {
 	c.settings = s
 }
 
+// SetNotifier wires the notification dispatcher (see internal/notify)
+// into the context, so Notify reaches whatever desktop/webhook/Slack/
+// Discord/ntfy backends the user configured under the "notifications"
+// setting.
+func (c *AppContext) SetNotifier(n *notify.Dispatcher) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.notifier = n
+}
+
+// Notify dispatches event to every configured notification backend,
+// best-effort - a slow or failing backend never blocks whatever
+// triggered the event. A no-op until SetNotifier has been called.
+func (c *AppContext) Notify(event notify.Event) {
+	c.mu.RLock()
+	n := c.notifier
+	c.mu.RUnlock()
+	n.Send(context.Background(), event)
+}
+
 // SetCallbacks sets all the UI callback functions
 func (c *AppContext) SetCallbacks(
 	getDevices func() []device.Device,
@@ -318,18 +346,103 @@ func (c *AppContext) RunDebugAction(actionID string) debug.Result {
 	return debug.RunAction(actionID)
 }
 
+// RegisterPluginSchema declares pluginID's settings.Schema - called from
+// the plugin's own Register(ctx) at startup, before it reads or writes any
+// setting through GetPluginSetting/SetPluginSetting. Registering again
+// (e.g. across a hot-reload) replaces the previous Schema outright.
+func (c *AppContext) RegisterPluginSchema(pluginID string, schema *settings.Schema) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.pluginSchemas[pluginID] = schema
+}
+
+func pluginSettingKey(pluginID, key string) string {
+	return "plugins." + pluginID + "." + key
+}
+
+// GetPluginSetting returns pluginID's current value for key, coerced to
+// its Schema's declared type, falling back to the Schema's Default if
+// unset. Returns nil if pluginID has no registered Schema or key isn't
+// declared in it - this is what rejects unknown keys instead of silently
+// reading whatever happens to already be on disk.
 func (c *AppContext) GetPluginSetting(pluginID, key string) interface{} {
-	if c.manager == nil {
+	c.mu.RLock()
+	schema := c.pluginSchemas[pluginID]
+	s := c.settings
+	c.mu.RUnlock()
+
+	field, ok := schema.Field(key)
+	if !ok || s == nil {
 		return nil
 	}
-	return c.manager.GetPluginSetting(pluginID, key)
+
+	raw := s.GetRaw(pluginSettingKey(pluginID, key))
+	if raw == nil {
+		return field.Default
+	}
+	value, err := field.Coerce(raw)
+	if err != nil {
+		return field.Default
+	}
+	return value
 }
 
+// SetPluginSetting validates value against pluginID's declared Schema for
+// key, stores it under the namespaced "plugins.<pluginID>.<key>" setting
+// (preserving int/float precision instead of coercing everything through
+// float64), and emits EventSettingChanged so other plugins can hot-reload
+// without a restart.
 func (c *AppContext) SetPluginSetting(pluginID, key string, value interface{}) error {
+	c.mu.RLock()
+	schema := c.pluginSchemas[pluginID]
+	s := c.settings
+	c.mu.RUnlock()
+
+	if s == nil {
+		return fmt.Errorf("settings not available")
+	}
+	field, ok := schema.Field(key)
+	if !ok {
+		return fmt.Errorf("plugin %q has no setting %q registered", pluginID, key)
+	}
+	coerced, err := field.Coerce(value)
+	if err != nil {
+		return err
+	}
+
+	settingKey := pluginSettingKey(pluginID, key)
+	old := s.GetRaw(settingKey)
+	s.SetRaw(settingKey, coerced)
+
+	c.NotifyPluginSettingChanged(pluginID, key)
+
+	evt := SettingChangedEvent{Key: settingKey, Old: old, New: coerced}
+	if field.Sensitive {
+		evt.Old, evt.New = "<redacted>", "<redacted>"
+	}
+	c.Emit(EventSettingChanged, evt)
+	return nil
+}
+
+// SetPluginEnabled starts or stops the plugin identified by pluginID,
+// mirroring the Start/Stop toggle the plugins panel already exposes in
+// the UI (see model.go's pluginManager.SetEnabled calls) - used by the
+// control API's POST /plugins/{id}/{start|stop} endpoints.
+func (c *AppContext) SetPluginEnabled(pluginID string, enabled bool) error {
 	if c.manager == nil {
 		return fmt.Errorf("plugin manager not available")
 	}
-	return c.manager.SetPluginSetting(pluginID, key, value)
+	return c.manager.SetEnabled(pluginID, enabled)
+}
+
+// GetPlugins returns every plugin registered with the manager, so a plugin
+// can discover peers implementing an optional capability interface (see
+// mcp.MCPToolProvider) without the core depending on that interface itself.
+func (c *AppContext) GetPlugins() []Plugin {
+	if c.manager == nil {
+		return nil
+	}
+	return c.manager.GetPlugins()
 }
 
 func (c *AppContext) Subscribe(event EventType, handler EventHandler) UnsubscribeFunc {
@@ -345,21 +458,46 @@ func (c *AppContext) Emit(event EventType, data interface{}) {
 	}
 }
 
+// Log is a shim over LogWith for callers that don't need levels or
+// fields, kept for existing Plugin implementations.
 func (c *AppContext) Log(pluginID string, message string) {
-	// Send to log channel (non-blocking)
-	select {
-	case c.logChan <- PluginLogEntry{
+	c.LogWith(pluginID, LevelInfo, message)
+}
+
+// LogError is a shim over LogWith for callers that don't need levels or
+// fields, kept for existing Plugin implementations.
+func (c *AppContext) LogError(pluginID string, err error) {
+	c.LogWith(pluginID, LevelError, err.Error())
+}
+
+// LogWith records a structured LogEntry for pluginID: appended to its
+// ring buffer, fanned out to every matching SubscribeLogs subscription,
+// and - for LevelError - persisted to ~/.lazycap/logs/<pluginID>.log.
+func (c *AppContext) LogWith(pluginID string, level LogLevel, message string, fields ...Field) {
+	fieldMap := make(map[string]any, len(fields))
+	for _, f := range fields {
+		fieldMap[f.Key] = f.Value
+	}
+	c.logs.append(LogEntry{
+		Time:     time.Now(),
+		Level:    level,
 		PluginID: pluginID,
 		Message:  message,
-		Time:     time.Now(),
-	}:
-	default:
-		// Channel full, drop log to avoid blocking
-	}
+		Fields:   fieldMap,
+	})
 }
 
-func (c *AppContext) LogError(pluginID string, err error) {
-	c.Log(pluginID, fmt.Sprintf("ERROR: %v", err))
+// SubscribeLogs returns a channel delivering every future LogEntry
+// matching filter, and an UnsubscribeFunc that unregisters it once the
+// caller stops reading (e.g. when a log viewer panel closes).
+func (c *AppContext) SubscribeLogs(filter LogFilter) (<-chan LogEntry, UnsubscribeFunc) {
+	return c.logs.subscribe(filter)
+}
+
+// PluginLogs returns a copy of pluginID's current in-memory ring buffer,
+// oldest first.
+func (c *AppContext) PluginLogs(pluginID string) []LogEntry {
+	return c.logs.window(pluginID)
 }
 
 // AddProcessLog adds a log line for a process (called by UI)
@@ -416,6 +554,259 @@ func (c *AppContext) NotifyDeviceSelected(dev *device.Device) {
 	}
 }
 
+// NotifyDeviceAdded emits a device.added event, fired when a device shows
+// up in a fresh device list that wasn't present before - a simulator
+// created, or a physical device plugged in.
+func (c *AppContext) NotifyDeviceAdded(dev *device.Device) {
+	if c.manager != nil {
+		c.manager.GetEventBus().Emit(EventDeviceAdded, DeviceAddedEvent{
+			Device: dev,
+		})
+	}
+}
+
+// NotifyDeviceRemoved emits a device.removed event, fired when a
+// previously-known device drops out of the device list entirely.
+func (c *AppContext) NotifyDeviceRemoved(deviceID string) {
+	if c.manager != nil {
+		c.manager.GetEventBus().Emit(EventDeviceRemoved, DeviceRemovedEvent{
+			DeviceID: deviceID,
+		})
+	}
+}
+
+// NotifyDeviceOnline emits a device.online event, fired when a known
+// device transitions from offline to online (e.g. a simulator finishes
+// booting).
+func (c *AppContext) NotifyDeviceOnline(dev *device.Device) {
+	if c.manager != nil {
+		c.manager.GetEventBus().Emit(EventDeviceOnline, DeviceOnlineEvent{
+			Device: dev,
+		})
+	}
+}
+
+// NotifyDeviceOffline emits a device.offline event, fired when a known
+// device transitions from online to offline.
+func (c *AppContext) NotifyDeviceOffline(dev *device.Device) {
+	if c.manager != nil {
+		c.manager.GetEventBus().Emit(EventDeviceOffline, DeviceOfflineEvent{
+			Device: dev,
+		})
+	}
+
+	name := ""
+	if dev != nil {
+		name = dev.Name
+	}
+	c.Notify(notify.Event{
+		Kind:     notify.KindDeviceDisconnected,
+		Severity: notify.SeverityWarn,
+		Title:    "Device disconnected",
+		Message:  name,
+	})
+}
+
+// NotifyPreflightUpdated emits a preflight.updated event, fired whenever
+// preflight checks are (re-)run, so a plugin can react to a newly
+// discovered error/warning without polling.
+func (c *AppContext) NotifyPreflightUpdated(hasErrors, hasWarnings bool, summary string) {
+	if c.manager != nil {
+		c.manager.GetEventBus().Emit(EventPreflightUpdated, PreflightUpdatedEvent{
+			HasErrors:   hasErrors,
+			HasWarnings: hasWarnings,
+			Summary:     summary,
+		})
+	}
+}
+
+// NotifyPluginEnabled emits a plugin.enabled event, fired when a plugin is
+// turned on, so listeners like the MCP server can refresh anything derived
+// from the plugin set (e.g. tools/list_changed).
+func (c *AppContext) NotifyPluginEnabled(pluginID string) {
+	if c.manager != nil {
+		c.manager.GetEventBus().Emit(EventPluginEnabled, PluginEnabledEvent{
+			PluginID: pluginID,
+		})
+	}
+}
+
+// NotifyPluginDisabled emits a plugin.disabled event, fired when a plugin
+// is turned off.
+func (c *AppContext) NotifyPluginDisabled(pluginID string) {
+	if c.manager != nil {
+		c.manager.GetEventBus().Emit(EventPluginDisabled, PluginDisabledEvent{
+			PluginID: pluginID,
+		})
+	}
+}
+
+// NotifyPluginSettingChanged emits a plugin.setting_changed event, fired
+// whenever a plugin's setting is updated through SetPluginSetting.
+func (c *AppContext) NotifyPluginSettingChanged(pluginID, key string) {
+	if c.manager != nil {
+		c.manager.GetEventBus().Emit(EventPluginSettingChanged, PluginSettingChangedEvent{
+			PluginID: pluginID,
+			Key:      key,
+		})
+	}
+}
+
+// PullPlugin pulls the plugin described at ref into the content-
+// addressable blob store under alias (see Manager.Pull) and emits a
+// plugin.installed event so the marketplace UI can refresh its list.
+func (c *AppContext) PullPlugin(ref, alias string) error {
+	if c.manager == nil {
+		return fmt.Errorf("plugin manager not available")
+	}
+
+	progressCh := make(chan download.GenericProgress, 8)
+	go c.forwardDownloadProgress("plugin:"+alias, progressCh)
+	err := c.manager.PullWithProgress(ref, alias, progressCh)
+	close(progressCh)
+	if err != nil {
+		return err
+	}
+	c.NotifyPluginInstalled(alias)
+	return nil
+}
+
+// RemovePlugin uninstalls the plugin identified by pluginID (see
+// Manager.Remove) and emits a plugin.removed event.
+func (c *AppContext) RemovePlugin(pluginID string) error {
+	if c.manager == nil {
+		return fmt.Errorf("plugin manager not available")
+	}
+	if err := c.manager.Remove(pluginID); err != nil {
+		return err
+	}
+	c.NotifyPluginRemoved(pluginID)
+	return nil
+}
+
+// NotifyPluginInstalled emits a plugin.installed event, fired once
+// Manager.Install has successfully pulled and linked a plugin.
+func (c *AppContext) NotifyPluginInstalled(alias string) {
+	if c.manager != nil {
+		c.manager.GetEventBus().Emit(EventPluginInstalled, PluginInstalledEvent{
+			Alias: alias,
+		})
+	}
+}
+
+// NotifyPluginRemoved emits a plugin.removed event, fired once
+// Manager.Remove has dropped a plugin's ref.
+func (c *AppContext) NotifyPluginRemoved(pluginID string) {
+	if c.manager != nil {
+		c.manager.GetEventBus().Emit(EventPluginRemoved, PluginRemovedEvent{
+			PluginID: pluginID,
+		})
+	}
+}
+
+// ApplyUpdate runs the self-update pipeline for info (see update.Apply):
+// downloading the new version, verifying its checksum/signature, and
+// atomically swapping it in as "current" alongside a "previous" rollback
+// target. This is what the "press U to update" flow in
+// preflight.Results.VersionCheck triggers; the caller is responsible for
+// re-running preflight and prompting for a restart once this returns.
+func (c *AppContext) ApplyUpdate(info *update.Info) error {
+	progressCh := make(chan download.GenericProgress, 8)
+	go c.forwardDownloadProgress("update:"+info.LatestVersion, progressCh)
+	err := update.ApplyWithProgress(info, progressCh)
+	close(progressCh)
+	if err != nil {
+		return err
+	}
+	if c.manager != nil {
+		c.manager.GetEventBus().Emit(EventUpdateApplied, UpdateAppliedEvent{
+			Version: info.LatestVersion,
+		})
+	}
+	return nil
+}
+
+// RunPreflightFix runs the registered preflight.Fixer for checkName (see
+// preflight.RunFix), forwarding its progress through the same per-process
+// log pipeline AddProcessLog uses for regular process output - so plugins
+// watching EventProcessOutput see it the same way they'd see any other
+// process's - and returns the freshly re-checked CheckResult once the fix
+// has finished.
+func (c *AppContext) RunPreflightFix(checkName string) (preflight.CheckResult, error) {
+	progressCh := make(chan download.GenericProgress, 16)
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for p := range progressCh {
+			c.AddProcessLog(checkName, p.Message)
+		}
+	}()
+
+	result, err := preflight.RunFix(context.Background(), checkName, progressCh)
+	close(progressCh)
+	<-done
+	return result, err
+}
+
+// WatchPreflight starts watching baseDir for changes to the project files
+// discoverProjects scans (see preflight.WatchAt), emitting
+// EventPreflightChanged each time they change so the UI can refresh
+// discovery without polling. Calling it again (e.g. after switching
+// projects) stops any previous watch first.
+func (c *AppContext) WatchPreflight(baseDir string) error {
+	c.mu.Lock()
+	if c.preflightWatcher != nil {
+		c.preflightWatcher.Stop()
+		c.preflightWatcher = nil
+	}
+	c.mu.Unlock()
+
+	w, changed, err := preflight.WatchAt(baseDir)
+	if err != nil {
+		return err
+	}
+
+	c.mu.Lock()
+	c.preflightWatcher = w
+	c.mu.Unlock()
+
+	go func() {
+		for range changed {
+			c.Emit(EventPreflightChanged, nil)
+		}
+	}()
+	return nil
+}
+
+// SettingChangedEvent is emitted as EventSettingChanged whenever
+// SetPluginSetting writes a new value - Key is the fully namespaced
+// "plugins.<pluginID>.<key>" setting, and Old/New are redacted to
+// "<redacted>" if the Schema Field marked the setting Sensitive.
+type SettingChangedEvent struct {
+	Key string
+	Old any
+	New any
+}
+
+// DownloadProgressEvent is emitted for each download.GenericProgress
+// update reported while pulling a plugin (PullPlugin) or applying a
+// self-update (ApplyUpdate), keyed by the same cache key the underlying
+// download.Pool job used, so the TUI can tell concurrent downloads apart.
+type DownloadProgressEvent struct {
+	Key      string
+	Progress download.GenericProgress
+}
+
+// forwardDownloadProgress relays ch to an EventDownloadProgress emission
+// per update until ch is closed, so PullPlugin/ApplyUpdate's callers can
+// render a multi-bar download view without importing internal/download
+// themselves.
+func (c *AppContext) forwardDownloadProgress(key string, ch <-chan download.GenericProgress) {
+	for p := range ch {
+		c.Emit(EventDownloadProgress, DownloadProgressEvent{Key: key, Progress: p})
+	}
+}
+
 // GetStartTime helper for process info
 func GetStartTime(t time.Time) int64 {
 	return t.Unix()
@@ -0,0 +1,60 @@
+package plugin
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/icarus-itcs/lazycap/internal/notify"
+	"github.com/icarus-itcs/lazycap/internal/plugin/pluginproto"
+)
+
+// contextService implements pluginproto.LazycapContextServer, the reverse
+// RPC an external plugin subprocess dials back into. It only exposes the
+// handful of Context methods a remote plugin needs (Log, GetProject,
+// GetPluginSetting, Notify) - everything else on Context assumes an
+// in-process caller and stays off the wire.
+type contextService struct {
+	pluginproto.UnimplementedLazycapContextServer
+	ctx Context
+}
+
+func newContextService(ctx Context) *contextService {
+	return &contextService{ctx: ctx}
+}
+
+func (s *contextService) Log(_ context.Context, req *pluginproto.LogRequest) (*pluginproto.Empty, error) {
+	s.ctx.Log(req.PluginId, req.Message)
+	return &pluginproto.Empty{}, nil
+}
+
+func (s *contextService) GetProject(context.Context, *pluginproto.Empty) (*pluginproto.GetProjectResponse, error) {
+	project := s.ctx.GetProject()
+	if project == nil {
+		return &pluginproto.GetProjectResponse{}, nil
+	}
+	return &pluginproto.GetProjectResponse{
+		Name:       project.Name,
+		RootDir:    project.RootDir,
+		HasIos:     project.HasIOS,
+		HasAndroid: project.HasAndroid,
+	}, nil
+}
+
+func (s *contextService) GetPluginSetting(_ context.Context, req *pluginproto.GetPluginSettingRequest) (*pluginproto.GetPluginSettingResponse, error) {
+	value := s.ctx.GetPluginSetting(req.PluginId, req.Key)
+	data, err := json.Marshal(value)
+	if err != nil {
+		return nil, err
+	}
+	return &pluginproto.GetPluginSettingResponse{ValueJson: string(data)}, nil
+}
+
+func (s *contextService) Notify(_ context.Context, req *pluginproto.NotifyRequest) (*pluginproto.Empty, error) {
+	s.ctx.Notify(notify.Event{
+		Kind:     notify.Kind(req.Kind),
+		Severity: notify.Severity(req.Severity),
+		Title:    req.Title,
+		Message:  req.Message,
+	})
+	return &pluginproto.Empty{}, nil
+}
@@ -0,0 +1,491 @@
+package plugin
+
+import (
+	"context"
+	"crypto/ed25519"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/icarus-itcs/lazycap/internal/download"
+	"github.com/icarus-itcs/lazycap/internal/settings"
+)
+
+// Permission names a capability a plugin's manifest must declare before
+// Manager.Install will load it - modeled after container image
+// capabilities, not OS permission bits, so "network" covers outbound
+// HTTP/gRPC and "spawn-process" covers exec.Command use inside the
+// plugin's own sandboxed subprocess.
+type Permission string
+
+const (
+	PermissionNetwork      Permission = "network"
+	PermissionSpawnProcess Permission = "spawn-process"
+	PermissionReadFS       Permission = "read-fs"
+)
+
+// PluginConfig is the immutable, content-addressed description of one
+// plugin version: enough for Manager to decide whether it's safe to load
+// and, once loaded, to launch it (see LaunchExternalPlugin).
+type PluginConfig struct {
+	ID                     string       `json:"id"`
+	Version                string       `json:"version"`
+	Entrypoint             string       `json:"entrypoint"` // blob Path of the plugin executable
+	Capabilities           []Capability `json:"capabilities,omitempty"`
+	RequiredContextMethods []string     `json:"required_context_methods,omitempty"`
+	Permissions            []Permission `json:"permissions,omitempty"`
+}
+
+// Blob is one sha256-addressed piece of content a Manifest references -
+// the plugin binary itself, or a supporting asset.
+type Blob struct {
+	Digest string `json:"digest"` // sha256, hex-encoded
+	Path   string `json:"path"`   // logical path, e.g. "bin/plugin"
+	URL    string `json:"url"`    // where to fetch it from
+	Size   int64  `json:"size"`
+}
+
+// Manifest is the top-level, optionally-signed document a plugin ref
+// resolves to: a PluginConfig plus the blobs that make it up.
+type Manifest struct {
+	Config    PluginConfig `json:"config"`
+	Blobs     []Blob       `json:"blobs"`
+	Signature string       `json:"signature,omitempty"` // base64 ed25519 signature over the rest of the manifest
+}
+
+// InstalledPlugin is one entry of Manager.List: an installed alias plus
+// whatever enabled/disabled state the Manager already tracks for its
+// plugin ID.
+type InstalledPlugin struct {
+	ID      string
+	Alias   string
+	Version string
+	Enabled bool
+}
+
+var distributionHTTPClient = &http.Client{Timeout: 30 * time.Second}
+
+// lazycapHomeDir resolves ~/.lazycap, the root of the content-addressable
+// blob store. This is deliberately separate from settings.ConfigDir()
+// (~/.config/lazycap) - the blob store is bulk content, not
+// configuration, and the request specifies this exact layout.
+func lazycapHomeDir() (string, error) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", fmt.Errorf("failed to resolve user home directory: %w", err)
+	}
+	return filepath.Join(home, ".lazycap"), nil
+}
+
+// blobDir returns ~/.lazycap/plugins/blobs/sha256, creating it if needed.
+func blobDir() (string, error) {
+	home, err := lazycapHomeDir()
+	if err != nil {
+		return "", err
+	}
+	dir := filepath.Join(home, "plugins", "blobs", "sha256")
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return "", fmt.Errorf("failed to create blob store: %w", err)
+	}
+	return dir, nil
+}
+
+// refsDir returns ~/.lazycap/plugins/refs, creating it if needed. Each
+// file in it is named after an alias and holds the Manifest that alias
+// currently resolves to, so multiple aliases can share blobs without
+// duplicating them on disk.
+func refsDir() (string, error) {
+	home, err := lazycapHomeDir()
+	if err != nil {
+		return "", err
+	}
+	dir := filepath.Join(home, "plugins", "refs")
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return "", fmt.Errorf("failed to create refs directory: %w", err)
+	}
+	return dir, nil
+}
+
+// fetchManifest resolves ref to a Manifest: an http(s) URL is fetched,
+// anything else is read as a local file path. It returns the raw bytes
+// alongside the parsed manifest since signature verification runs over
+// the exact bytes that were signed.
+func fetchManifest(ref string) (*Manifest, []byte, error) {
+	var data []byte
+	if strings.HasPrefix(ref, "http://") || strings.HasPrefix(ref, "https://") {
+		resp, err := distributionHTTPClient.Get(ref)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to fetch manifest: %w", err)
+		}
+		defer resp.Body.Close()
+		if resp.StatusCode != http.StatusOK {
+			return nil, nil, fmt.Errorf("manifest request returned %s", resp.Status)
+		}
+		data, err = io.ReadAll(resp.Body)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to read manifest: %w", err)
+		}
+	} else {
+		var err error
+		data, err = os.ReadFile(ref)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to read manifest %s: %w", ref, err)
+		}
+	}
+
+	var manifest Manifest
+	if err := json.Unmarshal(data, &manifest); err != nil {
+		return nil, nil, fmt.Errorf("failed to parse manifest: %w", err)
+	}
+	return &manifest, data, nil
+}
+
+// verifyManifestSignature checks sig against the trusted public key in
+// the "pluginSignaturePublicKey" setting (hex-encoded ed25519, 32 bytes).
+// With no key configured, an unsigned or signed manifest is accepted as-is
+// - signing is opt-in until an operator pins a key. Once a key is
+// configured, every install must carry a valid signature.
+func verifyManifestSignature(data []byte, sig string) error {
+	userSettings, err := settings.Load()
+	if err != nil {
+		return fmt.Errorf("failed to load settings: %w", err)
+	}
+	keyHex := userSettings.GetString("pluginSignaturePublicKey")
+	if keyHex == "" {
+		return nil
+	}
+	if sig == "" {
+		return fmt.Errorf("manifest is unsigned but a trusted public key is configured")
+	}
+
+	key, err := hex.DecodeString(keyHex)
+	if err != nil || len(key) != ed25519.PublicKeySize {
+		return fmt.Errorf("pluginSignaturePublicKey is not a valid ed25519 public key")
+	}
+	sigBytes, err := decodeSignature(sig)
+	if err != nil {
+		return fmt.Errorf("invalid manifest signature encoding: %w", err)
+	}
+	if !ed25519.Verify(key, unsignedManifestBytes(data), sigBytes) {
+		return fmt.Errorf("manifest signature verification failed")
+	}
+	return nil
+}
+
+// unsignedManifestBytes strips the "signature" field from a manifest's
+// raw JSON, reproducing the payload the publisher signed - a manifest
+// obviously can't have its own signature inside the bytes it signs.
+func unsignedManifestBytes(data []byte) []byte {
+	var raw map[string]json.RawMessage
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return data
+	}
+	delete(raw, "signature")
+	out, err := json.Marshal(raw)
+	if err != nil {
+		return data
+	}
+	return out
+}
+
+func decodeSignature(sig string) ([]byte, error) {
+	return base64.StdEncoding.DecodeString(sig)
+}
+
+// checkGrantedPermissions refuses to proceed if config declares a
+// Permission not present in the "pluginPermissions" setting for its ID
+// (a map[string][]string the settings/plugins panel writes to when a
+// user approves a plugin's requested permissions).
+func checkGrantedPermissions(config PluginConfig) error {
+	userSettings, err := settings.Load()
+	if err != nil {
+		return fmt.Errorf("failed to load settings: %w", err)
+	}
+	granted := map[Permission]bool{}
+	if raw, ok := userSettings.GetRaw("pluginPermissions").(map[string]interface{}); ok {
+		if list, ok := raw[config.ID].([]interface{}); ok {
+			for _, v := range list {
+				if s, ok := v.(string); ok {
+					granted[Permission(s)] = true
+				}
+			}
+		}
+	}
+
+	var missing []string
+	for _, p := range config.Permissions {
+		if !granted[p] {
+			missing = append(missing, string(p))
+		}
+	}
+	if len(missing) > 0 {
+		return fmt.Errorf("plugin %s requires ungranted permissions: %s", config.ID, strings.Join(missing, ", "))
+	}
+	return nil
+}
+
+// fetchAndStoreBlob ensures blob's content exists under
+// blobDir()/<digest>, fetching it via the shared download.Pool (which
+// dedupes against any other caller fetching the same digest right now,
+// and caches it under ~/.lazycap/cache) if missing, and returns its
+// on-disk path in the blob store. An existing blob is trusted by digest
+// match on its filename - the store is content-addressed, so a present
+// file named <digest> either holds exactly that content or was never
+// written by us.
+func fetchAndStoreBlob(blob Blob, progressCh chan<- download.GenericProgress) (string, error) {
+	dir, err := blobDir()
+	if err != nil {
+		return "", err
+	}
+	dest := filepath.Join(dir, blob.Digest)
+	if _, err := os.Stat(dest); err == nil {
+		return dest, nil
+	}
+
+	f, _, err := download.DefaultPool().Get(context.Background(), "plugin-blob:"+blob.Digest, blob.URL, blob.Digest, progressCh)
+	if err != nil {
+		return "", fmt.Errorf("failed to fetch blob %s: %w", blob.Digest, err)
+	}
+	defer f.Close()
+
+	tmp := dest + ".tmp"
+	tmpFile, err := os.Create(tmp)
+	if err != nil {
+		return "", fmt.Errorf("failed to create %s: %w", tmp, err)
+	}
+	defer os.Remove(tmp)
+
+	if _, err := io.Copy(tmpFile, f); err != nil {
+		tmpFile.Close()
+		return "", fmt.Errorf("failed to write blob %s: %w", blob.Digest, err)
+	}
+	tmpFile.Close()
+
+	if err := os.Chmod(tmp, 0o755); err != nil {
+		return "", fmt.Errorf("failed to mark blob %s executable: %w", blob.Digest, err)
+	}
+	if err := os.Rename(tmp, dest); err != nil {
+		return "", fmt.Errorf("failed to finalize blob %s: %w", blob.Digest, err)
+	}
+	return dest, nil
+}
+
+func refFilePath(alias string) (string, error) {
+	dir, err := refsDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, alias), nil
+}
+
+func writeRef(alias string, manifest *Manifest) error {
+	path, err := refFilePath(alias)
+	if err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(manifest, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode ref %s: %w", alias, err)
+	}
+	if err := os.WriteFile(path, data, 0o644); err != nil {
+		return fmt.Errorf("failed to write ref %s: %w", alias, err)
+	}
+	return nil
+}
+
+func readRef(alias string) (*Manifest, error) {
+	path, err := refFilePath(alias)
+	if err != nil {
+		return nil, err
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read ref %s: %w", alias, err)
+	}
+	var manifest Manifest
+	if err := json.Unmarshal(data, &manifest); err != nil {
+		return nil, fmt.Errorf("failed to parse ref %s: %w", alias, err)
+	}
+	return &manifest, nil
+}
+
+// findRefByPluginID returns the alias (and its manifest) currently
+// installed for a plugin ID, used by Manager.Remove since callers think
+// in terms of plugin IDs but refs are keyed by alias.
+func findRefByPluginID(id string) (string, *Manifest, error) {
+	dir, err := refsDir()
+	if err != nil {
+		return "", nil, err
+	}
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return "", nil, fmt.Errorf("failed to list refs: %w", err)
+	}
+	for _, entry := range entries {
+		if entry.IsDir() {
+			continue
+		}
+		manifest, err := readRef(entry.Name())
+		if err != nil {
+			continue
+		}
+		if manifest.Config.ID == id {
+			return entry.Name(), manifest, nil
+		}
+	}
+	return "", nil, fmt.Errorf("plugin %s is not installed", id)
+}
+
+// Pull resolves ref (a manifest URL or local manifest path) to a signed
+// Manifest, verifies every blob's digest, refuses to proceed if the
+// manifest declares a Permission the user hasn't granted, then
+// materializes the entrypoint blob into PluginDir() under alias so the
+// next external-plugin scan (see external.go) picks it up like any other
+// out-of-process plugin.
+//
+// This is the content-addressable counterpart to Install: Install takes
+// a single binary the caller already downloaded (see installPluginCmd in
+// the marketplace panel), Pull takes a ref to a full manifest of
+// sha256-addressed blobs plus declared permissions and capabilities.
+// Equivalent to PullWithProgress with a nil progress channel.
+func (m *Manager) Pull(ref, alias string) error {
+	return m.PullWithProgress(ref, alias, nil)
+}
+
+// PullWithProgress is Pull, optionally reporting each blob's download
+// progress to progressCh (see internal/download.Pool.Get) - used by
+// AppContext.PullPlugin to drive the TUI's progress bar.
+func (m *Manager) PullWithProgress(ref, alias string, progressCh chan<- download.GenericProgress) error {
+	manifest, data, err := fetchManifest(ref)
+	if err != nil {
+		return err
+	}
+	if err := checkGrantedPermissions(manifest.Config); err != nil {
+		return err
+	}
+	if err := verifyManifestSignature(data, manifest.Signature); err != nil {
+		return err
+	}
+
+	var entrypointPath string
+	for _, blob := range manifest.Blobs {
+		path, err := fetchAndStoreBlob(blob, progressCh)
+		if err != nil {
+			return err
+		}
+		if blob.Path == manifest.Config.Entrypoint {
+			entrypointPath = path
+		}
+	}
+	if entrypointPath == "" {
+		return fmt.Errorf("manifest for %s has no blob matching entrypoint %q", manifest.Config.ID, manifest.Config.Entrypoint)
+	}
+
+	pluginDir, err := PluginDir()
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(pluginDir, 0o755); err != nil {
+		return fmt.Errorf("failed to create plugin directory: %w", err)
+	}
+	linkPath := filepath.Join(pluginDir, alias)
+	os.Remove(linkPath)
+	if err := os.Symlink(entrypointPath, linkPath); err != nil {
+		return fmt.Errorf("failed to link %s into plugin directory: %w", alias, err)
+	}
+
+	return writeRef(alias, manifest)
+}
+
+// Install registers an already-downloaded plugin binary at path under
+// id: it symlinks it into PluginDir() and records a minimal ref so List
+// and Remove see it alongside plugins pulled via Pull. This backs the
+// marketplace panel's single-file download flow (see installPluginCmd);
+// Pull is the richer path for registries that publish full
+// content-addressed, optionally-signed manifests.
+func (m *Manager) Install(id, path string) error {
+	pluginDir, err := PluginDir()
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(pluginDir, 0o755); err != nil {
+		return fmt.Errorf("failed to create plugin directory: %w", err)
+	}
+	linkPath := filepath.Join(pluginDir, id)
+	os.Remove(linkPath)
+	if err := os.Symlink(path, linkPath); err != nil {
+		return fmt.Errorf("failed to link %s into plugin directory: %w", id, err)
+	}
+	return writeRef(id, &Manifest{Config: PluginConfig{ID: id, Entrypoint: path}})
+}
+
+// Update re-links id to a freshly downloaded path. It's functionally
+// identical to Install - kept as its own method since the marketplace
+// panel's update flow (see updatePluginCmd) calls it to distinguish a
+// fresh install from an upgrade in its status messages.
+func (m *Manager) Update(id, path string) error {
+	return m.Install(id, path)
+}
+
+// Remove disables the plugin identified by id (if currently running),
+// deletes its entry from PluginDir(), and drops its ref - it does not
+// garbage-collect shared blobs, since another alias may still reference
+// them.
+func (m *Manager) Remove(id string) error {
+	alias, _, err := findRefByPluginID(id)
+	if err != nil {
+		return err
+	}
+	_ = m.SetEnabled(id, false)
+
+	if pluginDir, err := PluginDir(); err == nil {
+		os.Remove(filepath.Join(pluginDir, alias))
+	}
+
+	path, err := refFilePath(alias)
+	if err != nil {
+		return err
+	}
+	if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("failed to remove ref %s: %w", alias, err)
+	}
+	return nil
+}
+
+// List returns every installed alias alongside the enabled/disabled
+// state m already tracks for its plugin ID.
+func (m *Manager) List() []InstalledPlugin {
+	dir, err := refsDir()
+	if err != nil {
+		return nil
+	}
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil
+	}
+
+	var out []InstalledPlugin
+	for _, entry := range entries {
+		if entry.IsDir() {
+			continue
+		}
+		manifest, err := readRef(entry.Name())
+		if err != nil {
+			continue
+		}
+		out = append(out, InstalledPlugin{
+			ID:      manifest.Config.ID,
+			Alias:   entry.Name(),
+			Version: manifest.Config.Version,
+			Enabled: m.IsEnabled(manifest.Config.ID),
+		})
+	}
+	return out
+}
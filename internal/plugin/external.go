@@ -0,0 +1,77 @@
+package plugin
+
+import (
+	"os"
+	"path/filepath"
+)
+
+// ExternalProtocolVersion is the handshake version lazycap negotiates with
+// out-of-process plugins (see LaunchExternalPlugin). Bump it whenever the
+// Plugin/LazycapContext RPC surface in pluginproto changes incompatibly.
+const ExternalProtocolVersion = 1
+
+// externalMagicCookieKey/Value are go-plugin's handshake cookie: a plugin
+// binary checks for this env var and refuses to run standalone, so running
+// it by hand prints a clear error instead of hanging on stdin.
+const (
+	externalMagicCookieKey   = "LAZYCAP_PLUGIN"
+	externalMagicCookieValue = "7d2f9b6e-9c2a-4c1a-9b0e-lazycap-plugin"
+)
+
+// Capability names a feature gate an external plugin can declare support
+// for during the handshake, so lazycap only relies on behavior the plugin
+// actually implements instead of guessing from its version alone.
+type Capability string
+
+const (
+	// CapabilityCommands means GetCommands/RunCommand are implemented.
+	CapabilityCommands Capability = "commands"
+	// CapabilityNotify means the plugin expects LazycapContext.Notify to
+	// be wired up (see request icarus-itcs/lazycap#chunk6-3).
+	CapabilityNotify Capability = "notify"
+)
+
+// ExternalHandshake is what a plugin subprocess reports back during
+// Describe: its protocol version and the capabilities it supports.
+type ExternalHandshake struct {
+	ProtocolVersion int
+	Capabilities    []Capability
+}
+
+// ExternalPluginDirs returns the directories lazycap scans for
+// out-of-process plugin executables, in priority order: the user's global
+// plugin directory (shared with the marketplace installer, see
+// PluginDir) and a project-local ".lazycap/plugins" directory so a repo
+// can ship its own integrations alongside the code they talk to.
+func ExternalPluginDirs() []string {
+	var dirs []string
+	if dir, err := PluginDir(); err == nil {
+		dirs = append(dirs, dir)
+	}
+	dirs = append(dirs, filepath.Join(".lazycap", "plugins"))
+	return dirs
+}
+
+// DiscoverExternalPlugins scans ExternalPluginDirs for executable files and
+// returns their full paths. A directory that doesn't exist is skipped
+// rather than treated as an error - neither location is required to exist.
+func DiscoverExternalPlugins() []string {
+	var found []string
+	for _, dir := range ExternalPluginDirs() {
+		entries, err := os.ReadDir(dir)
+		if err != nil {
+			continue
+		}
+		for _, entry := range entries {
+			if entry.IsDir() {
+				continue
+			}
+			info, err := entry.Info()
+			if err != nil || info.Mode()&0o111 == 0 {
+				continue // not executable
+			}
+			found = append(found, filepath.Join(dir, entry.Name()))
+		}
+	}
+	return found
+}
@@ -0,0 +1,325 @@
+package plugin
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os/exec"
+	"time"
+
+	hcplugin "github.com/hashicorp/go-plugin"
+	"google.golang.org/grpc"
+
+	"github.com/icarus-itcs/lazycap/internal/plugin/pluginproto"
+)
+
+// externalHandshakeConfig is the go-plugin HandshakeConfig every external
+// plugin process must match to be dialed (see handshake fields in
+// external.go).
+var externalHandshakeConfig = hcplugin.HandshakeConfig{
+	ProtocolVersion:  uint(ExternalProtocolVersion),
+	MagicCookieKey:   externalMagicCookieKey,
+	MagicCookieValue: externalMagicCookieValue,
+}
+
+// externalRestartInitialBackoff/MaxBackoff bound how hard a crashing
+// external plugin is retried before LaunchExternalPlugin gives up,
+// mirroring firebase.go's hubPollInitialBackoff/hubPollMaxBackoff.
+const (
+	externalRestartInitialBackoff = 500 * time.Millisecond
+	externalRestartMaxBackoff     = 30 * time.Second
+	externalRestartMaxAttempts    = 5
+)
+
+// pluginGRPCPlugin adapts pluginproto's generated client/server stubs to
+// go-plugin's plugin.GRPCPlugin interface. lazycap only ever dials (it
+// never serves the Plugin side), but it always serves LazycapContext so
+// the subprocess can call back in.
+type pluginGRPCPlugin struct {
+	hcplugin.NetRPCUnsupportedPlugin
+	ctxService *contextService
+}
+
+func (p *pluginGRPCPlugin) GRPCServer(_ *hcplugin.GRPCBroker, s *grpc.Server) error {
+	pluginproto.RegisterLazycapContextServer(s, p.ctxService)
+	return nil
+}
+
+func (p *pluginGRPCPlugin) GRPCClient(_ context.Context, _ *hcplugin.GRPCBroker, conn *grpc.ClientConn) (interface{}, error) {
+	return pluginproto.NewPluginClient(conn), nil
+}
+
+// ExternalPlugin wraps one out-of-process plugin subprocess and implements
+// the same Plugin interface as a built-in by forwarding every call over
+// gRPC (see pluginproto/plugin.proto). A crashing subprocess is
+// transparently relaunched with backoff; callers only ever see a
+// transient error from whichever call raced the crash.
+type ExternalPlugin struct {
+	path string
+	ctx  Context
+
+	client *hcplugin.Client
+	rpc    pluginproto.PluginClient
+
+	id, name, version, author, description string
+	capabilities                           []Capability
+}
+
+// LaunchExternalPlugin starts the executable at path as a go-plugin
+// subprocess, performs the handshake, and returns an ExternalPlugin ready
+// to register with a Manager like any built-in plugin.
+func LaunchExternalPlugin(path string, ctx Context) (*ExternalPlugin, error) {
+	ep := &ExternalPlugin{path: path, ctx: ctx}
+	if err := ep.launch(); err != nil {
+		return nil, err
+	}
+	return ep, nil
+}
+
+func (e *ExternalPlugin) launch() error {
+	client := hcplugin.NewClient(&hcplugin.ClientConfig{
+		HandshakeConfig: externalHandshakeConfig,
+		Plugins: map[string]hcplugin.Plugin{
+			"plugin": &pluginGRPCPlugin{ctxService: newContextService(e.ctx)},
+		},
+		Cmd:              exec.Command(e.path),
+		AllowedProtocols: []hcplugin.Protocol{hcplugin.ProtocolGRPC},
+	})
+
+	rpcClient, err := client.Client()
+	if err != nil {
+		client.Kill()
+		return fmt.Errorf("failed to start plugin %s: %w", e.path, err)
+	}
+	raw, err := rpcClient.Dispense("plugin")
+	if err != nil {
+		client.Kill()
+		return fmt.Errorf("failed to dispense plugin %s: %w", e.path, err)
+	}
+	rpc, ok := raw.(pluginproto.PluginClient)
+	if !ok {
+		client.Kill()
+		return fmt.Errorf("plugin %s did not implement the expected service", e.path)
+	}
+
+	info, err := rpc.Describe(context.Background(), &pluginproto.DescribeRequest{ProtocolVersion: int32(ExternalProtocolVersion)})
+	if err != nil {
+		client.Kill()
+		return fmt.Errorf("plugin %s failed handshake: %w", e.path, err)
+	}
+	if int(info.ProtocolVersion) != ExternalProtocolVersion {
+		client.Kill()
+		return fmt.Errorf("plugin %s speaks protocol v%d, lazycap expects v%d", e.path, info.ProtocolVersion, ExternalProtocolVersion)
+	}
+
+	caps := make([]Capability, 0, len(info.Capabilities))
+	for _, c := range info.Capabilities {
+		caps = append(caps, Capability(c))
+	}
+
+	e.client = client
+	e.rpc = rpc
+	e.id, e.name, e.version, e.author, e.description = info.Id, info.Name, info.Version, info.Author, info.Description
+	e.capabilities = caps
+	return nil
+}
+
+// HasCapability reports whether the plugin declared support for cap
+// during its handshake.
+func (e *ExternalPlugin) HasCapability(cap Capability) bool {
+	for _, c := range e.capabilities {
+		if c == cap {
+			return true
+		}
+	}
+	return false
+}
+
+// restart relaunches a crashed subprocess with exponential backoff. It's
+// only ever called from withReconnect, never exposed directly, so a
+// caller mid-call just sees one failed RPC rather than having to know
+// about supervision.
+func (e *ExternalPlugin) restart() error {
+	backoff := externalRestartInitialBackoff
+	var lastErr error
+	for attempt := 0; attempt < externalRestartMaxAttempts; attempt++ {
+		if attempt > 0 {
+			time.Sleep(backoff)
+			if backoff *= 2; backoff > externalRestartMaxBackoff {
+				backoff = externalRestartMaxBackoff
+			}
+		}
+		if err := e.launch(); err == nil {
+			return nil
+		} else {
+			lastErr = err
+		}
+	}
+	return fmt.Errorf("plugin %s did not come back up after %d crashes: %w", e.path, externalRestartMaxAttempts, lastErr)
+}
+
+// withReconnect retries call once after relaunching the subprocess if it
+// appears to have exited, so a crash between two UI ticks doesn't
+// permanently wedge the plugin.
+func (e *ExternalPlugin) withReconnect(call func() error) error {
+	if e.client.Exited() {
+		if err := e.restart(); err != nil {
+			return err
+		}
+	}
+	if err := call(); err != nil {
+		if !e.client.Exited() {
+			return err
+		}
+		if err := e.restart(); err != nil {
+			return err
+		}
+		return call()
+	}
+	return nil
+}
+
+// Kill terminates the plugin subprocess outright, used by StopAll's
+// external-plugin cleanup pass - a plain Stop() leaves the go-plugin host
+// process (and its gRPC connection) running in case of a future restart.
+func (e *ExternalPlugin) Kill() {
+	if e.client != nil {
+		e.client.Kill()
+	}
+}
+
+// Plugin interface implementation - every method below forwards to the
+// subprocess over gRPC, reconnecting through a crash when needed.
+
+func (e *ExternalPlugin) ID() string          { return e.id }
+func (e *ExternalPlugin) Name() string        { return e.name }
+func (e *ExternalPlugin) Version() string     { return e.version }
+func (e *ExternalPlugin) Author() string      { return e.author }
+func (e *ExternalPlugin) Description() string { return e.description }
+
+func (e *ExternalPlugin) Init(ctx Context) error {
+	e.ctx = ctx
+	return e.withReconnect(func() error {
+		_, err := e.rpc.Init(context.Background(), &pluginproto.Empty{})
+		return err
+	})
+}
+
+func (e *ExternalPlugin) Start() error {
+	return e.withReconnect(func() error {
+		_, err := e.rpc.Start(context.Background(), &pluginproto.Empty{})
+		return err
+	})
+}
+
+func (e *ExternalPlugin) Stop() error {
+	err := e.withReconnect(func() error {
+		_, err := e.rpc.Stop(context.Background(), &pluginproto.Empty{})
+		return err
+	})
+	// Unlike a built-in, an external plugin has a whole subprocess to tear
+	// down - Stop() is the signal that lazycap is done with it for this
+	// run, so it's killed outright rather than left running for a restart
+	// that will never come.
+	e.Kill()
+	return err
+}
+
+func (e *ExternalPlugin) IsRunning() bool {
+	var running bool
+	err := e.withReconnect(func() error {
+		resp, err := e.rpc.IsRunning(context.Background(), &pluginproto.Empty{})
+		if err != nil {
+			return err
+		}
+		running = resp.Running
+		return nil
+	})
+	return err == nil && running
+}
+
+func (e *ExternalPlugin) GetSettings() []Setting {
+	var resp *pluginproto.GetSettingsResponse
+	err := e.withReconnect(func() error {
+		r, err := e.rpc.GetSettings(context.Background(), &pluginproto.Empty{})
+		if err != nil {
+			return err
+		}
+		resp = r
+		return nil
+	})
+	if err != nil {
+		return nil
+	}
+	settings := make([]Setting, 0, len(resp.Settings))
+	for _, s := range resp.Settings {
+		var def interface{}
+		_ = json.Unmarshal([]byte(s.DefaultJson), &def)
+		settings = append(settings, Setting{
+			Key:         s.Key,
+			Name:        s.Name,
+			Description: s.Description,
+			Type:        s.Type,
+			Default:     def,
+		})
+	}
+	return settings
+}
+
+func (e *ExternalPlugin) OnSettingChange(key string, value interface{}) {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return
+	}
+	_ = e.withReconnect(func() error {
+		_, err := e.rpc.OnSettingChange(context.Background(), &pluginproto.SettingChange{Key: key, ValueJson: string(data)})
+		return err
+	})
+}
+
+func (e *ExternalPlugin) GetCommands() []Command {
+	var resp *pluginproto.GetCommandsResponse
+	err := e.withReconnect(func() error {
+		r, err := e.rpc.GetCommands(context.Background(), &pluginproto.Empty{})
+		if err != nil {
+			return err
+		}
+		resp = r
+		return nil
+	})
+	if err != nil {
+		return nil
+	}
+	commands := make([]Command, 0, len(resp.Commands))
+	for _, c := range resp.Commands {
+		key := c.Key
+		commands = append(commands, Command{
+			Key:         key,
+			Name:        c.Name,
+			Description: c.Description,
+			Handler: func() error {
+				return e.withReconnect(func() error {
+					_, err := e.rpc.RunCommand(context.Background(), &pluginproto.RunCommandRequest{Key: key})
+					return err
+				})
+			},
+		})
+	}
+	return commands
+}
+
+func (e *ExternalPlugin) GetStatusLine() string {
+	var line string
+	err := e.withReconnect(func() error {
+		resp, err := e.rpc.GetStatusLine(context.Background(), &pluginproto.Empty{})
+		if err != nil {
+			return err
+		}
+		line = resp.Line
+		return nil
+	})
+	if err != nil {
+		return ""
+	}
+	return line
+}
@@ -0,0 +1,29 @@
+package plugin
+
+import "fmt"
+
+// LoadExternalPlugins discovers executables under ExternalPluginDirs,
+// launches each as a go-plugin subprocess (see LaunchExternalPlugin), and
+// registers the ones that complete the handshake with m - the
+// out-of-process equivalent of a built-in calling the package-level
+// Register during plugins.RegisterAll, except these are found at runtime
+// instead of compiled in.
+//
+// A plugin that fails to launch or handshake is skipped, with its error
+// returned alongside any others, rather than aborting the whole scan - one
+// broken integration shouldn't take lazycap's built-ins down with it.
+func (m *Manager) LoadExternalPlugins(ctx Context) []error {
+	var errs []error
+	for _, path := range DiscoverExternalPlugins() {
+		ep, err := LaunchExternalPlugin(path, ctx)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", path, err))
+			continue
+		}
+		if err := m.Register(ep); err != nil {
+			ep.Kill()
+			errs = append(errs, fmt.Errorf("%s: %w", path, err))
+		}
+	}
+	return errs
+}
@@ -0,0 +1,286 @@
+package plugin
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/icarus-itcs/lazycap/internal/settings"
+)
+
+// LogLevel orders plugin log entries the same way most structured-logging
+// libraries do, so a LogFilter's MinLevel can be compared directly.
+type LogLevel int
+
+const (
+	LevelDebug LogLevel = iota
+	LevelInfo
+	LevelWarn
+	LevelError
+)
+
+// String renders level the way it's written to
+// ~/.lazycap/logs/<pluginID>.log.
+func (l LogLevel) String() string {
+	switch l {
+	case LevelDebug:
+		return "DEBUG"
+	case LevelInfo:
+		return "INFO"
+	case LevelWarn:
+		return "WARN"
+	case LevelError:
+		return "ERROR"
+	default:
+		return "UNKNOWN"
+	}
+}
+
+// Field is one key/value pair attached to a LogEntry - plain structs
+// rather than a bare map so LogWith's call sites read like
+// c.LogWith(id, LevelWarn, "retrying", plugin.F("attempt", 3)).
+type Field struct {
+	Key   string
+	Value any
+}
+
+// F is a constructor for Field, short enough to read well at a LogWith
+// call site.
+func F(key string, value any) Field {
+	return Field{Key: key, Value: value}
+}
+
+// LogEntry is one structured log record from a plugin, replacing the
+// plain strings AppContext.Log used to hand the UI.
+type LogEntry struct {
+	Time     time.Time
+	Level    LogLevel
+	PluginID string
+	Message  string
+	Fields   map[string]any
+}
+
+// LogFilter selects a subset of LogEntry values for SubscribeLogs and
+// PluginLogs: PluginID and Fields are "must match exactly if set", while
+// MinLevel is a floor. The zero LogFilter matches everything.
+type LogFilter struct {
+	PluginID string
+	MinLevel LogLevel
+	Fields   map[string]any
+}
+
+// Matches reports whether e satisfies every condition f sets.
+func (f LogFilter) Matches(e LogEntry) bool {
+	if f.PluginID != "" && f.PluginID != e.PluginID {
+		return false
+	}
+	if e.Level < f.MinLevel {
+		return false
+	}
+	for k, v := range f.Fields {
+		if e.Fields == nil {
+			return false
+		}
+		if ev, ok := e.Fields[k]; !ok || ev != v {
+			return false
+		}
+	}
+	return true
+}
+
+const defaultLogWindowEntries = 1000
+
+// logRing is a fixed-size, oldest-overwritten ring of LogEntry values for
+// one plugin - the in-memory half of the per-plugin log buffer the
+// request describes; disk persistence of error-level entries is handled
+// separately by appendErrorLog.
+type logRing struct {
+	entries []LogEntry
+	cap     int
+	head    int
+	count   int
+}
+
+func newLogRing(cap int) *logRing {
+	if cap < 1 {
+		cap = defaultLogWindowEntries
+	}
+	return &logRing{entries: make([]LogEntry, cap), cap: cap}
+}
+
+func (r *logRing) push(e LogEntry) {
+	r.entries[(r.head+r.count)%r.cap] = e
+	if r.count < r.cap {
+		r.count++
+	} else {
+		r.head = (r.head + 1) % r.cap
+	}
+}
+
+// window returns a copy of r's current contents, oldest first.
+func (r *logRing) window() []LogEntry {
+	out := make([]LogEntry, r.count)
+	for i := 0; i < r.count; i++ {
+		out[i] = r.entries[(r.head+i)%r.cap]
+	}
+	return out
+}
+
+// logSub is one SubscribeLogs registration: every entry Matches accepts
+// is sent to ch, non-blockingly so one slow reader can't stall logging.
+type logSub struct {
+	filter LogFilter
+	ch     chan LogEntry
+}
+
+// logRegistry is the fan-in/fan-out hub behind
+// AppContext.LogWith/SubscribeLogs/PluginLogs: one bounded ring per
+// plugin ID, plus the set of live subscriptions every new entry is
+// broadcast to. It conceptually belongs to Manager (one per running
+// instance, same as the plugin processes Manager tracks), but lives on
+// AppContext alongside the logChan it replaces, since that's where this
+// kind of cross-cutting UI-facing state already lived.
+type logRegistry struct {
+	mu        sync.Mutex
+	windowCap int
+	rings     map[string]*logRing
+	subs      map[int]*logSub
+	nextSubID int
+}
+
+func newLogRegistry() *logRegistry {
+	windowCap := defaultLogWindowEntries
+	if s, err := settings.Load(); err == nil {
+		if n := s.GetInt("pluginLogWindowEntries"); n > 0 {
+			windowCap = n
+		}
+	}
+	return &logRegistry{
+		windowCap: windowCap,
+		rings:     make(map[string]*logRing),
+		subs:      make(map[int]*logSub),
+	}
+}
+
+// append records e in its plugin's ring buffer, broadcasts it to every
+// matching subscription, and (for LevelError entries) persists it to
+// ~/.lazycap/logs/<pluginID>.log.
+func (r *logRegistry) append(e LogEntry) {
+	r.mu.Lock()
+	ring, ok := r.rings[e.PluginID]
+	if !ok {
+		ring = newLogRing(r.windowCap)
+		r.rings[e.PluginID] = ring
+	}
+	ring.push(e)
+
+	var matched []chan LogEntry
+	for _, sub := range r.subs {
+		if sub.filter.Matches(e) {
+			matched = append(matched, sub.ch)
+		}
+	}
+	r.mu.Unlock()
+
+	for _, ch := range matched {
+		select {
+		case ch <- e:
+		default:
+		}
+	}
+
+	if e.Level == LevelError {
+		if err := appendErrorLog(e); err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: failed to persist plugin error log: %v\n", err)
+		}
+	}
+}
+
+// window returns a copy of pluginID's ring, oldest first, or nil if
+// nothing has been logged for it yet.
+func (r *logRegistry) window(pluginID string) []LogEntry {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	ring, ok := r.rings[pluginID]
+	if !ok {
+		return nil
+	}
+	return ring.window()
+}
+
+// subscribe registers filter and returns a channel fed every future entry
+// it matches, plus an UnsubscribeFunc that unregisters it. append never
+// closes the channel; callers must unsubscribe once they stop reading.
+func (r *logRegistry) subscribe(filter LogFilter) (<-chan LogEntry, UnsubscribeFunc) {
+	r.mu.Lock()
+	id := r.nextSubID
+	r.nextSubID++
+	ch := make(chan LogEntry, 256)
+	r.subs[id] = &logSub{filter: filter, ch: ch}
+	r.mu.Unlock()
+
+	return ch, func() {
+		r.mu.Lock()
+		delete(r.subs, id)
+		r.mu.Unlock()
+	}
+}
+
+// errorLogDir returns ~/.lazycap/logs, creating it if needed - bulk,
+// disposable data, so it lives alongside the download cache and plugin
+// blob store rather than under settings.ConfigDir().
+func errorLogDir() (string, error) {
+	home, err := lazycapHomeDir()
+	if err != nil {
+		return "", err
+	}
+	dir := filepath.Join(home, "logs")
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return "", fmt.Errorf("failed to create plugin log directory: %w", err)
+	}
+	return dir, nil
+}
+
+func errorLogPath(pluginID string) (string, error) {
+	dir, err := errorLogDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, pluginID+".log"), nil
+}
+
+// appendErrorLog appends e to its plugin's on-disk log file, rotating the
+// existing file to a ".1" backup once it exceeds the "pluginLogMaxMB"
+// setting - a single backup generation is enough for "attach this to a
+// bug report", which is what this file is for.
+func appendErrorLog(e LogEntry) error {
+	path, err := errorLogPath(e.PluginID)
+	if err != nil {
+		return err
+	}
+
+	maxMB := 10
+	if s, err := settings.Load(); err == nil {
+		if n := s.GetInt("pluginLogMaxMB"); n > 0 {
+			maxMB = n
+		}
+	}
+	if info, err := os.Stat(path); err == nil && info.Size() >= int64(maxMB)*1024*1024 {
+		os.Rename(path, path+".1")
+	}
+
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o644)
+	if err != nil {
+		return fmt.Errorf("failed to open %s: %w", path, err)
+	}
+	defer f.Close()
+
+	line := fmt.Sprintf("%s\t%s\t%s\t%s", e.Time.Format(time.RFC3339Nano), e.Level, e.PluginID, e.Message)
+	for k, v := range e.Fields {
+		line += fmt.Sprintf("\t%s=%v", k, v)
+	}
+	_, err = fmt.Fprintln(f, line)
+	return err
+}
@@ -0,0 +1,11 @@
+// Package pluginproto holds the gRPC contract between lazycap and an
+// out-of-process plugin (see plugin.proto) and the code generated from it.
+//
+// Regenerate after editing plugin.proto with:
+//
+//	protoc --go_out=. --go_opt=paths=source_relative \
+//	       --go-grpc_out=. --go-grpc_opt=paths=source_relative \
+//	       plugin.proto
+package pluginproto
+
+//go:generate protoc --go_out=. --go_opt=paths=source_relative --go-grpc_out=. --go-grpc_opt=paths=source_relative plugin.proto
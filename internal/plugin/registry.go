@@ -0,0 +1,172 @@
+package plugin
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/icarus-itcs/lazycap/internal/settings"
+)
+
+// RegistryEntry describes one plugin available from a Registry's
+// manifest: enough to list it in the marketplace and to install it.
+type RegistryEntry struct {
+	ID          string `json:"id"`
+	Version     string `json:"version"`
+	Description string `json:"description"`
+	Checksum    string `json:"checksum"` // sha256, hex-encoded
+	DownloadURL string `json:"download_url"`
+
+	// Requires declares this version's dependencies as plugin ID -> semver
+	// constraint (e.g. {"capacitor-core": "^1.2.0"}), resolved by Resolve.
+	Requires map[string]string `json:"requires,omitempty"`
+}
+
+// Registry fetches and caches the manifest of plugins available for
+// install from a remote marketplace - a JSON document served over HTTPS,
+// shaped as {"plugins": [RegistryEntry, ...]}.
+type Registry struct {
+	ManifestURL string
+
+	httpClient *http.Client
+
+	mu      sync.RWMutex
+	entries []RegistryEntry
+}
+
+// NewRegistry returns a Registry pointed at manifestURL, ready to have
+// Refresh called on it.
+func NewRegistry(manifestURL string) *Registry {
+	return &Registry{
+		ManifestURL: manifestURL,
+		httpClient:  &http.Client{Timeout: 15 * time.Second},
+	}
+}
+
+// Refresh re-fetches the manifest over HTTPS and replaces the cached
+// entry list.
+func (r *Registry) Refresh() error {
+	resp, err := r.httpClient.Get(r.ManifestURL)
+	if err != nil {
+		return fmt.Errorf("failed to fetch plugin manifest: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("plugin manifest request returned %s", resp.Status)
+	}
+
+	var manifest struct {
+		Plugins []RegistryEntry `json:"plugins"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&manifest); err != nil {
+		return fmt.Errorf("failed to parse plugin manifest: %w", err)
+	}
+
+	r.mu.Lock()
+	r.entries = manifest.Plugins
+	r.mu.Unlock()
+	return nil
+}
+
+// Entries returns a copy of the last-fetched manifest entries.
+func (r *Registry) Entries() []RegistryEntry {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	out := make([]RegistryEntry, len(r.entries))
+	copy(out, r.entries)
+	return out
+}
+
+// Search filters Entries() to those whose ID or Description contains
+// query (case-insensitive). An empty query returns every entry.
+func (r *Registry) Search(query string) []RegistryEntry {
+	all := r.Entries()
+	if query == "" {
+		return all
+	}
+	query = strings.ToLower(query)
+
+	var matched []RegistryEntry //nolint:prealloc // size unknown, grows dynamically
+	for _, e := range all {
+		if strings.Contains(strings.ToLower(e.ID), query) || strings.Contains(strings.ToLower(e.Description), query) {
+			matched = append(matched, e)
+		}
+	}
+	return matched
+}
+
+// Find returns the manifest entry for id, if present.
+func (r *Registry) Find(id string) (RegistryEntry, bool) {
+	for _, e := range r.Entries() {
+		if e.ID == id {
+			return e, true
+		}
+	}
+	return RegistryEntry{}, false
+}
+
+// Download fetches entry's DownloadURL into destDir, verifying its
+// SHA-256 checksum before returning the written path. Hot-registering
+// the result with plugin.All() (see Manager.Install/Update) is the
+// caller's responsibility - Download only handles fetch-and-verify.
+func (r *Registry) Download(entry RegistryEntry, destDir string) (string, error) {
+	resp, err := r.httpClient.Get(entry.DownloadURL)
+	if err != nil {
+		return "", fmt.Errorf("failed to download %s: %w", entry.ID, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("download of %s returned %s", entry.ID, resp.Status)
+	}
+
+	if err := os.MkdirAll(destDir, 0o755); err != nil {
+		return "", fmt.Errorf("failed to create plugin directory: %w", err)
+	}
+
+	ext := filepath.Ext(entry.DownloadURL)
+	if ext == "" {
+		ext = ".bin"
+	}
+	destPath := filepath.Join(destDir, fmt.Sprintf("%s-%s%s", entry.ID, entry.Version, ext))
+
+	f, err := os.Create(destPath)
+	if err != nil {
+		return "", fmt.Errorf("failed to create %s: %w", destPath, err)
+	}
+	defer f.Close()
+
+	hasher := sha256.New()
+	if _, err := io.Copy(io.MultiWriter(f, hasher), resp.Body); err != nil {
+		os.Remove(destPath)
+		return "", fmt.Errorf("failed to write %s: %w", destPath, err)
+	}
+
+	if entry.Checksum != "" {
+		if sum := hex.EncodeToString(hasher.Sum(nil)); !strings.EqualFold(sum, entry.Checksum) {
+			os.Remove(destPath)
+			return "", fmt.Errorf("checksum mismatch for %s: expected %s, got %s", entry.ID, entry.Checksum, sum)
+		}
+	}
+
+	return destPath, nil
+}
+
+// PluginDir returns the directory installed plugins are downloaded into,
+// alongside the rest of lazycap's config/state (see settings.ConfigDir).
+func PluginDir() (string, error) {
+	dir, err := settings.ConfigDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "plugins"), nil
+}
@@ -0,0 +1,174 @@
+package plugin
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/Masterminds/semver/v3"
+)
+
+// ResolvedPlugin is one plugin picked by Resolve, pinned to the specific
+// version that satisfied every constraint collected against it.
+type ResolvedPlugin struct {
+	ID      string
+	Version string
+}
+
+// Plan is the output of Resolve: every plugin that must be enabled - the
+// caller's original selection plus anything pulled in to satisfy
+// `requires` constraints - in dependency order. Manager.Start walks
+// StartOrder forward so each plugin's dependencies are already running by
+// the time it starts; Manager.Stop walks it in reverse.
+type Plan struct {
+	Enable      []ResolvedPlugin
+	StartOrder  []string
+	AutoEnabled []string // IDs pulled in that weren't part of the original selection
+}
+
+// Resolve expands enabledIDs (the plugins the caller wants on) into a full
+// Plan against available (normally Registry.Entries()), following each
+// candidate's Requires constraints iteratively - in the spirit of
+// ficsit-cli's resolvingInstance.Step: constraints on a plugin ID
+// accumulate as more of its dependents get picked, and its candidate
+// versions are re-filtered against everything collected so far until one
+// version satisfies the lot or none does.
+func Resolve(enabledIDs []string, available []RegistryEntry) (*Plan, error) {
+	candidatesByID := make(map[string][]RegistryEntry)
+	for _, e := range available {
+		candidatesByID[e.ID] = append(candidatesByID[e.ID], e)
+	}
+
+	requested := make(map[string]bool, len(enabledIDs))
+	for _, id := range enabledIDs {
+		requested[id] = true
+	}
+
+	constraintsByID := make(map[string][]string)
+	resolved := make(map[string]RegistryEntry)
+
+	pending := append([]string{}, enabledIDs...)
+	for len(pending) > 0 {
+		id := pending[0]
+		pending = pending[1:]
+
+		entry, err := pickVersion(id, candidatesByID[id], constraintsByID[id])
+		if err != nil {
+			return nil, err
+		}
+		if existing, ok := resolved[id]; ok && existing.Version == entry.Version {
+			continue // already settled on this exact version
+		}
+		resolved[id] = entry
+
+		for depID, constraint := range entry.Requires {
+			constraintsByID[depID] = append(constraintsByID[depID], constraint)
+			pending = append(pending, depID)
+		}
+	}
+
+	order, err := topoSort(resolved)
+	if err != nil {
+		return nil, err
+	}
+
+	plan := &Plan{StartOrder: order}
+	for _, id := range order {
+		plan.Enable = append(plan.Enable, ResolvedPlugin{ID: id, Version: resolved[id].Version})
+		if !requested[id] {
+			plan.AutoEnabled = append(plan.AutoEnabled, id)
+		}
+	}
+	return plan, nil
+}
+
+// pickVersion returns the highest-versioned candidate satisfying every
+// constraint string collected for id so far, or a clear error if id has
+// no candidates at all or none of them satisfy the combined constraint.
+func pickVersion(id string, candidates []RegistryEntry, constraintStrs []string) (RegistryEntry, error) {
+	if len(candidates) == 0 {
+		return RegistryEntry{}, fmt.Errorf("plugin %q is required but not available in the marketplace", id)
+	}
+
+	var constraint *semver.Constraints
+	if len(constraintStrs) > 0 {
+		c, err := semver.NewConstraint(strings.Join(constraintStrs, ", "))
+		if err != nil {
+			return RegistryEntry{}, fmt.Errorf("invalid version constraint for %q (%s): %w", id, strings.Join(constraintStrs, ", "), err)
+		}
+		constraint = c
+	}
+
+	var best RegistryEntry
+	var bestVer *semver.Version
+	for _, e := range candidates {
+		v, err := semver.NewVersion(e.Version)
+		if err != nil {
+			continue
+		}
+		if constraint != nil && !constraint.Check(v) {
+			continue
+		}
+		if bestVer == nil || v.GreaterThan(bestVer) {
+			bestVer, best = v, e
+		}
+	}
+	if bestVer == nil {
+		return RegistryEntry{}, fmt.Errorf("no available version of %q satisfies %s", id, strings.Join(constraintStrs, ", "))
+	}
+	return best, nil
+}
+
+// topoSort orders resolved so every plugin's Requires dependencies precede
+// it, erroring out on a dependency cycle rather than looping forever.
+func topoSort(resolved map[string]RegistryEntry) ([]string, error) {
+	const (
+		unvisited = 0
+		visiting  = 1
+		done      = 2
+	)
+
+	var order []string
+	state := make(map[string]int, len(resolved))
+
+	var visit func(id string) error
+	visit = func(id string) error {
+		switch state[id] {
+		case done:
+			return nil
+		case visiting:
+			return fmt.Errorf("circular plugin dependency detected at %q", id)
+		}
+		state[id] = visiting
+
+		entry := resolved[id]
+		deps := make([]string, 0, len(entry.Requires))
+		for depID := range entry.Requires {
+			deps = append(deps, depID)
+		}
+		sort.Strings(deps) // deterministic order regardless of map iteration
+
+		for _, depID := range deps {
+			if err := visit(depID); err != nil {
+				return err
+			}
+		}
+
+		state[id] = done
+		order = append(order, id)
+		return nil
+	}
+
+	ids := make([]string, 0, len(resolved))
+	for id := range resolved {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+
+	for _, id := range ids {
+		if err := visit(id); err != nil {
+			return nil, err
+		}
+	}
+	return order, nil
+}
@@ -4,13 +4,16 @@ import (
 	"bufio"
 	"encoding/json"
 	"fmt"
+	"net/http"
 	"os"
 	"os/exec"
 	"path/filepath"
-	"strings"
+	"sort"
 	"sync"
+	"time"
 
-	"lazycap/internal/plugin"
+	"github.com/icarus-itcs/lazycap/internal/notify"
+	"github.com/icarus-itcs/lazycap/internal/plugin"
 )
 
 const (
@@ -20,6 +23,19 @@ const (
 	PluginAuthor  = "lazycap"
 )
 
+// defaultHubPort is what Firebase uses for the Emulator Hub
+// (GET /emulators) when firebase.json doesn't set emulators.hub.port.
+const defaultHubPort = 4400
+
+// hubPollInitialBackoff/hubPollMaxBackoff bound how hard pollHub retries
+// while waiting for the hub to come up - fast enough to catch a quick
+// start, capped so a slow one doesn't spin.
+const (
+	hubPollInitialBackoff = 250 * time.Millisecond
+	hubPollMaxBackoff     = 5 * time.Second
+	hubPollTimeout        = 2 * time.Second
+)
+
 // EmulatorStatus represents the status of an emulator
 type EmulatorStatus struct {
 	Name    string `json:"name"`
@@ -42,6 +58,16 @@ type FirebasePlugin struct {
 	autoStart    bool
 	importPath   string
 	exportOnExit bool
+
+	// hubHost/hubPort locate the Emulator Hub (emulators.hub in
+	// firebase.json, defaulting to localhost:4400) that pollHub queries for
+	// real per-emulator host/port/pid instead of scraping stdout.
+	hubHost string
+	hubPort int
+
+	// triggersDisabled tracks whether the last disable/enable
+	// background-triggers call succeeded, for the GetCommands() toggle.
+	triggersDisabled bool
 }
 
 // New creates a new Firebase Emulator plugin instance
@@ -61,10 +87,10 @@ func Register() error {
 
 // Plugin interface implementation
 
-func (p *FirebasePlugin) ID() string          { return PluginID }
-func (p *FirebasePlugin) Name() string        { return PluginName }
-func (p *FirebasePlugin) Version() string     { return PluginVersion }
-func (p *FirebasePlugin) Author() string      { return PluginAuthor }
+func (p *FirebasePlugin) ID() string      { return PluginID }
+func (p *FirebasePlugin) Name() string    { return PluginName }
+func (p *FirebasePlugin) Version() string { return PluginVersion }
+func (p *FirebasePlugin) Author() string  { return PluginAuthor }
 func (p *FirebasePlugin) Description() string {
 	return "Integrates Firebase Emulator Suite for local development"
 }
@@ -169,6 +195,14 @@ func (p *FirebasePlugin) GetCommands() []plugin.Command {
 				return p.Start()
 			},
 		},
+		{
+			Key:         "B",
+			Name:        "Background Triggers",
+			Description: "Pause/resume Cloud Functions background triggers",
+			Handler: func() error {
+				return p.toggleBackgroundTriggers()
+			},
+		},
 	}
 }
 
@@ -290,9 +324,20 @@ func (p *FirebasePlugin) loadFirebaseConfig(path string) {
 		return
 	}
 
+	p.hubHost = "localhost"
+	p.hubPort = defaultHubPort
+	if hub, ok := config.Emulators["hub"]; ok {
+		if hub.Host != "" {
+			p.hubHost = hub.Host
+		}
+		if hub.Port != 0 {
+			p.hubPort = hub.Port
+		}
+	}
+
 	p.emulators = make([]EmulatorStatus, 0)
 	for name, emu := range config.Emulators {
-		if name == "ui" || name == "singleProjectMode" {
+		if name == "ui" || name == "singleProjectMode" || name == "hub" {
 			continue
 		}
 		host := emu.Host
@@ -355,15 +400,30 @@ func (p *FirebasePlugin) startEmulators() error {
 
 	p.mu.Lock()
 	p.cmd = cmd
+	hubHost := p.hubHost
+	hubPort := p.hubPort
 	p.mu.Unlock()
 
-	// Read output in goroutines
+	// Read output in goroutines - purely for log forwarding now; emulator
+	// status comes from the hub (see pollHub), not from scraping these
+	// lines.
 	go p.readOutput(stdout)
 	go p.readOutput(stderr)
 
+	go p.pollHub(hubHost, hubPort)
+
 	// Wait for process in goroutine
 	go func() {
 		cmd.Wait()
+
+		unexpected := false
+		select {
+		case <-p.stopCh:
+			// Stop() closed stopCh before the process exited - expected.
+		default:
+			unexpected = true
+		}
+
 		p.mu.Lock()
 		p.running = false
 		p.cmd = nil
@@ -372,6 +432,15 @@ func (p *FirebasePlugin) startEmulators() error {
 			p.emulators[i].Running = false
 		}
 		p.mu.Unlock()
+
+		if unexpected {
+			p.ctx.Notify(notify.Event{
+				Kind:     notify.KindFirebaseDown,
+				Severity: notify.SeverityError,
+				Title:    "Firebase emulators stopped unexpectedly",
+				Message:  "The firebase emulator process exited before Stop was called",
+			})
+		}
 	}()
 
 	return nil
@@ -386,30 +455,129 @@ func (p *FirebasePlugin) readOutput(reader interface{ Read([]byte) (int, error)
 		default:
 		}
 
-		line := scanner.Text()
+		p.ctx.Log(PluginID, scanner.Text())
+	}
+}
+
+// hubEmulatorInfo is one entry of the Emulator Hub's GET /emulators
+// response: the real host/port/pid the CLI picked for that emulator, which
+// can differ from firebase.json when the configured port was taken.
+type hubEmulatorInfo struct {
+	Name string `json:"name"`
+	Host string `json:"host"`
+	Port int    `json:"port"`
+	PID  int    `json:"pid"`
+}
+
+// pollHub polls the Emulator Hub at hubHost:hubPort for GET /emulators
+// until it responds (with exponential backoff) or the plugin is stopped,
+// then populates p.emulators from the response.
+func (p *FirebasePlugin) pollHub(hubHost string, hubPort int) {
+	url := fmt.Sprintf("http://%s:%d/emulators", hubHost, hubPort)
+	client := &http.Client{Timeout: hubPollTimeout}
+	backoff := hubPollInitialBackoff
 
-		// Parse emulator status from output
-		p.parseEmulatorStatus(line)
+	for {
+		select {
+		case <-p.stopCh:
+			return
+		default:
+		}
 
-		// Log to lazycap
-		p.ctx.Log(PluginID, line)
+		if info, ok := fetchHubStatus(client, url); ok {
+			p.applyHubStatus(info)
+			return
+		}
+
+		select {
+		case <-time.After(backoff):
+		case <-p.stopCh:
+			return
+		}
+		if backoff *= 2; backoff > hubPollMaxBackoff {
+			backoff = hubPollMaxBackoff
+		}
 	}
 }
 
-func (p *FirebasePlugin) parseEmulatorStatus(line string) {
+func fetchHubStatus(client *http.Client, url string) (map[string]hubEmulatorInfo, bool) {
+	resp, err := client.Get(url)
+	if err != nil {
+		return nil, false
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, false
+	}
+
+	var info map[string]hubEmulatorInfo
+	if err := json.NewDecoder(resp.Body).Decode(&info); err != nil {
+		return nil, false
+	}
+	return info, true
+}
+
+func (p *FirebasePlugin) applyHubStatus(info map[string]hubEmulatorInfo) {
 	p.mu.Lock()
 	defer p.mu.Unlock()
 
-	// Firebase emulator output contains lines like:
-	// "✔  firestore: Firestore Emulator UI at http://127.0.0.1:4000/firestore"
-	// "✔  All emulators ready! It is now safe to connect your app."
-
-	for i, emu := range p.emulators {
-		// Check if this emulator is mentioned as running
-		if strings.Contains(line, emu.Name+":") && strings.Contains(line, "Emulator") {
-			p.emulators[i].Running = true
+	p.emulators = p.emulators[:0]
+	for name, e := range info {
+		if name == "hub" {
+			continue
 		}
+		host := e.Host
+		if host == "" {
+			host = "localhost"
+		}
+		p.emulators = append(p.emulators, EmulatorStatus{
+			Name:    name,
+			Host:    host,
+			Port:    e.Port,
+			Running: true,
+		})
+	}
+	sort.Slice(p.emulators, func(i, j int) bool { return p.emulators[i].Name < p.emulators[j].Name })
+}
+
+// toggleBackgroundTriggers pauses or resumes Cloud Functions background
+// triggers via the functions emulator's disableBackgroundTriggers /
+// enableBackgroundTriggers endpoints - useful while seeding data so a
+// Firestore write doesn't fire a function before the seed finishes.
+func (p *FirebasePlugin) toggleBackgroundTriggers() error {
+	functionsURL := p.GetEmulatorURL("functions")
+	if functionsURL == "" {
+		return fmt.Errorf("functions emulator is not running")
+	}
+
+	p.mu.Lock()
+	disable := !p.triggersDisabled
+	p.mu.Unlock()
+
+	action := "enableBackgroundTriggers"
+	if disable {
+		action = "disableBackgroundTriggers"
+	}
+
+	resp, err := http.Post(fmt.Sprintf("%s/functions/%s", functionsURL, action), "application/json", nil)
+	if err != nil {
+		return fmt.Errorf("failed to %s: %w", action, err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("%s returned status %d", action, resp.StatusCode)
 	}
+
+	p.mu.Lock()
+	p.triggersDisabled = disable
+	p.mu.Unlock()
+
+	state := "enabled"
+	if disable {
+		state = "disabled"
+	}
+	p.ctx.Log(PluginID, fmt.Sprintf("Cloud Functions background triggers %s", state))
+	return nil
 }
 
 // GetEmulatorStatus returns the current status of all emulators
@@ -441,19 +609,3 @@ func (p *FirebasePlugin) IsFirebaseProject() bool {
 	defer p.mu.RUnlock()
 	return p.configPath != ""
 }
-
-// readOutput helper for *os.File (fix the type)
-func (p *FirebasePlugin) readOutputPipe(pipe interface{ Read([]byte) (int, error) }) {
-	scanner := bufio.NewScanner(pipe)
-	for scanner.Scan() {
-		select {
-		case <-p.stopCh:
-			return
-		default:
-		}
-
-		line := scanner.Text()
-		p.parseEmulatorStatus(line)
-		p.ctx.Log(PluginID, line)
-	}
-}
@@ -0,0 +1,224 @@
+package mcp
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+)
+
+// newAuthToken returns a random 32-byte hex bearer token for authenticating
+// tcp/http MCP clients. stdio is trusted implicitly - it's only reachable
+// by the process that spawned lazycap.
+func newAuthToken() string {
+	buf := make([]byte, 32)
+	if _, err := rand.Read(buf); err != nil {
+		return fmt.Sprintf("token-%d", time.Now().UnixNano())
+	}
+	return hex.EncodeToString(buf)
+}
+
+// ensureAuthToken returns the plugin's bearer token, loading it from
+// plugin settings or generating and persisting a new one on first use so
+// it survives restarts.
+func (p *MCPPlugin) ensureAuthToken() string {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.authToken != "" {
+		return p.authToken
+	}
+	if stored := p.ctx.GetPluginSetting(PluginID, "authToken"); stored != nil {
+		if s, ok := stored.(string); ok && s != "" {
+			p.authToken = s
+			return p.authToken
+		}
+	}
+	p.authToken = newAuthToken()
+	_ = p.ctx.SetPluginSetting(PluginID, "authToken", p.authToken)
+	return p.authToken
+}
+
+func (p *MCPPlugin) authTokenValue() string {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	return p.authToken
+}
+
+func (p *MCPPlugin) rateLimitSnapshot() int {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	return p.rateLimit
+}
+
+// checkBearer reports whether an HTTP Authorization header value matches
+// "Bearer <token>". Always false for an empty token, so auth can never be
+// silently bypassed by a client racing Start().
+func checkBearer(header, token string) bool {
+	return token != "" && header == "Bearer "+token
+}
+
+// checkTCPAuthLine validates the first line a TCP client sends, which must
+// be "Authorization: Bearer <token>" - there's no real HTTP header to carry
+// this over a raw socket, so the header text itself is the line.
+func (p *MCPPlugin) checkTCPAuthLine(line string) bool {
+	const prefix = "Authorization: "
+	if !strings.HasPrefix(line, prefix) {
+		return false
+	}
+	return checkBearer(strings.TrimPrefix(line, prefix), p.authTokenValue())
+}
+
+// parseToolList parses a comma-separated "disabledTools" setting into a
+// lookup set, trimming whitespace and dropping empty entries.
+func parseToolList(csv string) map[string]struct{} {
+	set := make(map[string]struct{})
+	for _, name := range strings.Split(csv, ",") {
+		name = strings.TrimSpace(name)
+		if name != "" {
+			set[name] = struct{}{}
+		}
+	}
+	return set
+}
+
+func (p *MCPPlugin) isToolDisabled(name string) bool {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	_, disabled := p.disabledTools[name]
+	return disabled
+}
+
+// dangerousTools are the MCP tools requireConfirmation gates when the
+// Confirm Dangerous Actions setting is on - mirrored by ToolInfo.Dangerous
+// in mcp.go so clients can warn the user up front too.
+var dangerousTools = map[string]bool{
+	"kill_process":     true,
+	"run_debug_action": true,
+}
+
+func isDangerousTool(name string) bool { return dangerousTools[name] }
+
+// pendingConfirmation is an issued-but-not-yet-confirmed nonce for one
+// dangerous tool call, expiring if the client doesn't repeat the call in
+// time.
+type pendingConfirmation struct {
+	tool    string
+	expires time.Time
+}
+
+const confirmationTTL = 2 * time.Minute
+
+func (p *MCPPlugin) issueConfirmation(tool string) string {
+	nonce := newSessionID()
+	p.confirmMu.Lock()
+	p.pendingConfirm[nonce] = pendingConfirmation{tool: tool, expires: time.Now().Add(confirmationTTL)}
+	p.confirmMu.Unlock()
+	return nonce
+}
+
+func (p *MCPPlugin) consumeConfirmation(nonce, tool string) bool {
+	p.confirmMu.Lock()
+	defer p.confirmMu.Unlock()
+	pc, ok := p.pendingConfirm[nonce]
+	if !ok || pc.tool != tool || !time.Now().Before(pc.expires) {
+		return false
+	}
+	delete(p.pendingConfirm, nonce)
+	return true
+}
+
+// requireConfirmation implements the "confirm dangerous actions" mode: the
+// first call to a dangerous tool returns a nonce instead of running it, and
+// the caller must repeat the call with arguments.confirm set to that nonce
+// to actually execute it. handled reports whether the caller should return
+// result/mcpErr immediately instead of dispatching the tool.
+func (p *MCPPlugin) requireConfirmation(tool string, args map[string]interface{}) (result interface{}, mcpErr *MCPError, handled bool) {
+	p.mu.RLock()
+	confirmDangerous := p.confirmDangerous
+	p.mu.RUnlock()
+	if !confirmDangerous || !isDangerousTool(tool) {
+		return nil, nil, false
+	}
+
+	if nonce, _ := args["confirm"].(string); nonce != "" {
+		if p.consumeConfirmation(nonce, tool) {
+			return nil, nil, false
+		}
+		return nil, &MCPError{Code: -32000, Message: "invalid or expired confirmation nonce"}, true
+	}
+
+	nonce := p.issueConfirmation(tool)
+	return structuredToolResult(map[string]interface{}{
+		"confirmationRequired": true,
+		"nonce":                nonce,
+		"message":              fmt.Sprintf("%s is a dangerous action - call it again with arguments.confirm = %q within %s to proceed", tool, nonce, confirmationTTL),
+	}), nil, true
+}
+
+// tokenBucket is a simple per-connection/session rate limiter: capacity
+// tokens refilling at ratePerMinute/60 tokens per second, one token spent
+// per tools/call.
+type tokenBucket struct {
+	mu           sync.Mutex
+	tokens       float64
+	capacity     float64
+	refillPerSec float64
+	last         time.Time
+}
+
+func newTokenBucket(ratePerMinute int) *tokenBucket {
+	if ratePerMinute <= 0 {
+		ratePerMinute = 60
+	}
+	capacity := float64(ratePerMinute)
+	return &tokenBucket{tokens: capacity, capacity: capacity, refillPerSec: capacity / 60, last: time.Now()}
+}
+
+func (b *tokenBucket) Allow() bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	now := time.Now()
+	b.tokens += now.Sub(b.last).Seconds() * b.refillPerSec
+	if b.tokens > b.capacity {
+		b.tokens = b.capacity
+	}
+	b.last = now
+
+	if b.tokens < 1 {
+		return false
+	}
+	b.tokens--
+	return true
+}
+
+// bucketFor returns the http session sessionID's rate limiter, creating one
+// on first use.
+func (p *MCPPlugin) bucketFor(sessionID string) *tokenBucket {
+	p.bucketsMu.Lock()
+	defer p.bucketsMu.Unlock()
+	if b, ok := p.buckets[sessionID]; ok {
+		return b
+	}
+	b := newTokenBucket(p.rateLimitSnapshot())
+	p.buckets[sessionID] = b
+	return b
+}
+
+// auditArgsMaxLen bounds how much of a tool call's arguments land in the
+// audit log - enough to see what was requested, not enough for a giant
+// payload (e.g. a settings dump) to flood the plugin log.
+const auditArgsMaxLen = 200
+
+// auditLog records every tools/call through the plugin log (visible in the
+// debug log / log viewer) with the caller's address, the tool name, and
+// truncated arguments.
+func (p *MCPPlugin) auditLog(clientAddr, tool string, args map[string]interface{}) {
+	argsJSON := toJSON(args)
+	if len(argsJSON) > auditArgsMaxLen {
+		argsJSON = argsJSON[:auditArgsMaxLen] + "...(truncated)"
+	}
+	p.ctx.Log(PluginID, fmt.Sprintf("tools/call %s from %s args=%s", tool, clientAddr, argsJSON))
+}
@@ -0,0 +1,130 @@
+package mcp
+
+import (
+	"testing"
+	"time"
+)
+
+func TestCheckBearer(t *testing.T) {
+	cases := []struct {
+		name   string
+		header string
+		token  string
+		want   bool
+	}{
+		{"matching token", "Bearer secret", "secret", true},
+		{"wrong token", "Bearer wrong", "secret", false},
+		{"missing prefix", "secret", "secret", false},
+		{"empty token never matches", "Bearer ", "", false},
+		{"empty header", "", "secret", false},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			if got := checkBearer(c.header, c.token); got != c.want {
+				t.Errorf("checkBearer(%q, %q) = %v, want %v", c.header, c.token, got, c.want)
+			}
+		})
+	}
+}
+
+func TestCheckTCPAuthLine(t *testing.T) {
+	p := New()
+	p.authToken = "secret"
+
+	if !p.checkTCPAuthLine("Authorization: Bearer secret") {
+		t.Error("expected matching auth line to pass")
+	}
+	if p.checkTCPAuthLine("Authorization: Bearer wrong") {
+		t.Error("expected wrong token to fail")
+	}
+	if p.checkTCPAuthLine("Bearer secret") {
+		t.Error("expected a line without the Authorization: prefix to fail")
+	}
+}
+
+func TestParseToolList(t *testing.T) {
+	set := parseToolList(" kill_process, run_debug_action ,, ")
+	if len(set) != 2 {
+		t.Fatalf("expected 2 entries, got %d: %v", len(set), set)
+	}
+	if _, ok := set["kill_process"]; !ok {
+		t.Error("expected kill_process in set")
+	}
+	if _, ok := set["run_debug_action"]; !ok {
+		t.Error("expected run_debug_action in set")
+	}
+}
+
+func TestConfirmationNonceFlow(t *testing.T) {
+	p := New()
+
+	nonce := p.issueConfirmation("kill_process")
+	if nonce == "" {
+		t.Fatal("expected a non-empty nonce")
+	}
+
+	if p.consumeConfirmation(nonce, "run_debug_action") {
+		t.Error("expected consumeConfirmation to reject a mismatched tool")
+	}
+	// The mismatched attempt above must not have consumed the nonce.
+	if !p.consumeConfirmation(nonce, "kill_process") {
+		t.Error("expected consumeConfirmation to accept the matching tool")
+	}
+	// A nonce can only be consumed once.
+	if p.consumeConfirmation(nonce, "kill_process") {
+		t.Error("expected a consumed nonce to be rejected on reuse")
+	}
+}
+
+func TestConfirmationNonceExpires(t *testing.T) {
+	p := New()
+
+	nonce := p.issueConfirmation("kill_process")
+	p.confirmMu.Lock()
+	pc := p.pendingConfirm[nonce]
+	pc.expires = time.Now().Add(-time.Second)
+	p.pendingConfirm[nonce] = pc
+	p.confirmMu.Unlock()
+
+	if p.consumeConfirmation(nonce, "kill_process") {
+		t.Error("expected an expired nonce to be rejected")
+	}
+}
+
+func TestTokenBucketAllowsUpToCapacity(t *testing.T) {
+	b := newTokenBucket(60)
+	for i := 0; i < 60; i++ {
+		if !b.Allow() {
+			t.Fatalf("expected request %d to be allowed within capacity", i)
+		}
+	}
+	if b.Allow() {
+		t.Error("expected the 61st request to be rejected once the bucket is empty")
+	}
+}
+
+func TestTokenBucketRefills(t *testing.T) {
+	b := newTokenBucket(60)
+	for i := 0; i < 60; i++ {
+		b.Allow()
+	}
+	if b.Allow() {
+		t.Fatal("expected bucket to be empty")
+	}
+
+	// 60/minute = 1/second; rewind last so a refill looks like 1s elapsed.
+	b.mu.Lock()
+	b.last = b.last.Add(-time.Second)
+	b.mu.Unlock()
+
+	if !b.Allow() {
+		t.Error("expected one token to have refilled after 1s at 60/minute")
+	}
+}
+
+func TestTokenBucketDefaultsNonPositiveRate(t *testing.T) {
+	b := newTokenBucket(0)
+	if b.capacity != 60 {
+		t.Errorf("expected a non-positive rate to default to 60, got %v", b.capacity)
+	}
+}
@@ -0,0 +1,117 @@
+package mcp
+
+import (
+	"sync"
+
+	"github.com/icarus-itcs/lazycap/internal/plugin"
+)
+
+// MCPToolProvider is implemented by any plugin - built-in or third-party -
+// that wants to expose its own MCP tools without editing this package.
+// MCPPlugin discovers providers by type-asserting every plugin returned by
+// plugin.Context.GetPlugins() (see externalTools/dispatchExternalTool), so
+// a future Firebase, iOS provisioning, or custom user plugin just needs to
+// implement these two methods to show up in tools/list and tools/call.
+type MCPToolProvider interface {
+	GetMCPTools() []ToolInfo
+	HandleMCPCall(name string, args map[string]interface{}) (interface{}, *MCPError)
+}
+
+// externalTools collects the ToolInfo entries every registered
+// MCPToolProvider contributes, in addition to the built-ins in
+// handleToolsList.
+func (p *MCPPlugin) externalTools() []ToolInfo {
+	var tools []ToolInfo
+	for _, plug := range p.ctx.GetPlugins() {
+		if provider, ok := plug.(MCPToolProvider); ok {
+			tools = append(tools, provider.GetMCPTools()...)
+		}
+	}
+	return tools
+}
+
+// dispatchExternalTool routes a tools/call for a tool name not handled by
+// this package's own switch to whichever registered plugin advertised it.
+// ok is false if no provider claims the name, so the caller can fall back
+// to its usual "unknown tool" error.
+func (p *MCPPlugin) dispatchExternalTool(name string, args map[string]interface{}) (result interface{}, mcpErr *MCPError, ok bool) {
+	for _, plug := range p.ctx.GetPlugins() {
+		provider, isProvider := plug.(MCPToolProvider)
+		if !isProvider {
+			continue
+		}
+		for _, t := range provider.GetMCPTools() {
+			if t.Name == name {
+				result, mcpErr = provider.HandleMCPCall(name, args)
+				return result, mcpErr, true
+			}
+		}
+	}
+	return nil, nil, false
+}
+
+// subscribeToolChangeEvents pushes notifications/tools/list_changed to
+// every connected client whenever something that could change
+// handleToolsList's output happens: a plugin (including an MCPToolProvider)
+// being enabled/disabled, or having its settings changed.
+func (p *MCPPlugin) subscribeToolChangeEvents() {
+	onChange := func(interface{}) { p.broadcastToolsChanged() }
+	unsubEnabled := p.ctx.Subscribe(plugin.EventPluginEnabled, onChange)
+	unsubDisabled := p.ctx.Subscribe(plugin.EventPluginDisabled, onChange)
+	unsubSettings := p.ctx.Subscribe(plugin.EventPluginSettingChanged, onChange)
+
+	p.mu.Lock()
+	p.unsubEvents = append(p.unsubEvents, unsubEnabled, unsubDisabled, unsubSettings)
+	p.mu.Unlock()
+}
+
+// broadcastToolsChanged fans notifications/tools/list_changed out over
+// whichever transport is active: http sessions via the existing SSE
+// broadcast, tcp/stdio via clients (see clientBroadcast).
+func (p *MCPPlugin) broadcastToolsChanged() {
+	p.broadcast("notifications/tools/list_changed", nil)
+	p.clients.push("notifications/tools/list_changed", nil)
+}
+
+// clientBroadcast is a registry of "push this notification to one
+// currently-connected client" callbacks, one per live tcp connection or
+// stdio session (http uses its own sseSession-based broadcast in http.go),
+// used to fan out server-initiated notifications uniformly.
+type clientBroadcast struct {
+	mu   sync.Mutex
+	next int
+	fns  map[int]func(method string, params interface{})
+}
+
+func newClientBroadcast() *clientBroadcast {
+	return &clientBroadcast{fns: make(map[int]func(method string, params interface{}))}
+}
+
+// register adds fn to the registry and returns a function that removes it
+// again - call it (typically via defer) when the connection/session closes.
+func (b *clientBroadcast) register(fn func(method string, params interface{})) func() {
+	b.mu.Lock()
+	id := b.next
+	b.next++
+	b.fns[id] = fn
+	b.mu.Unlock()
+
+	return func() {
+		b.mu.Lock()
+		delete(b.fns, id)
+		b.mu.Unlock()
+	}
+}
+
+func (b *clientBroadcast) push(method string, params interface{}) {
+	b.mu.Lock()
+	fns := make([]func(string, interface{}), 0, len(b.fns))
+	for _, fn := range b.fns {
+		fns = append(fns, fn)
+	}
+	b.mu.Unlock()
+
+	for _, fn := range fns {
+		fn(method, params)
+	}
+}
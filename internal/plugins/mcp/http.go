@@ -0,0 +1,323 @@
+package mcp
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/icarus-itcs/lazycap/internal/plugin"
+)
+
+// MCP Streamable HTTP transport (2025-03-26 spec): a single endpoint
+// accepting JSON-RPC POSTs, plus a GET that upgrades to an SSE stream of
+// server-initiated notifications. Every POST/GET carries an
+// Mcp-Session-Id header so several AI clients (Claude Desktop, VSCode,
+// Cursor, ...) can be connected at once without their notification
+// streams crossing.
+
+const mcpSessionHeader = "Mcp-Session-Id"
+
+// sseEventHistoryLimit bounds how many past notifications a session keeps
+// for Last-Event-ID resume - enough to ride out a brief reconnect, not a
+// full replay log.
+const sseEventHistoryLimit = 256
+
+// sseEvent is one frame on a session's notification stream.
+type sseEvent struct {
+	id   int
+	data []byte
+}
+
+// sseSession is one connected MCP client's server-initiated notification
+// stream: a resumable (via Last-Event-ID), monotonically-numbered queue of
+// JSON-RPC notifications, with at most one live SSE connection attached at
+// a time.
+type sseSession struct {
+	mu      sync.Mutex
+	nextID  int
+	history []sseEvent
+	live    chan sseEvent // non-nil while a GET /mcp request is attached
+}
+
+func newSSESession() *sseSession {
+	return &sseSession{}
+}
+
+// push appends a JSON-RPC notification to the session's history and, if a
+// client is currently attached, forwards it immediately. Safe to call from
+// any goroutine (it's invoked directly from plugin event handlers).
+func (s *sseSession) push(method string, params interface{}) {
+	notification := struct {
+		JSONRPC string      `json:"jsonrpc"`
+		Method  string      `json:"method"`
+		Params  interface{} `json:"params,omitempty"`
+	}{JSONRPC: "2.0", Method: method, Params: params}
+
+	data, err := json.Marshal(notification)
+	if err != nil {
+		return
+	}
+
+	s.mu.Lock()
+	s.nextID++
+	event := sseEvent{id: s.nextID, data: data}
+	s.history = append(s.history, event)
+	if len(s.history) > sseEventHistoryLimit {
+		s.history = s.history[len(s.history)-sseEventHistoryLimit:]
+	}
+	live := s.live
+	s.mu.Unlock()
+
+	if live != nil {
+		select {
+		case live <- event:
+		default:
+			// Slow/stuck client - it'll catch up via Last-Event-ID on
+			// reconnect instead of blocking the event handler.
+		}
+	}
+}
+
+// attach registers ch as this session's live connection and returns the
+// buffered events after lastEventID (0 for "no resume point", i.e. every
+// event still in history) so the caller can replay them before switching
+// to live delivery. Replaces any previously attached connection.
+func (s *sseSession) attach(ch chan sseEvent, lastEventID int) []sseEvent {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.live = ch
+
+	if lastEventID <= 0 {
+		return nil
+	}
+	var backlog []sseEvent
+	for _, e := range s.history {
+		if e.id > lastEventID {
+			backlog = append(backlog, e)
+		}
+	}
+	return backlog
+}
+
+func (s *sseSession) detach(ch chan sseEvent) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.live == ch {
+		s.live = nil
+	}
+}
+
+// newSessionID returns a random 16-byte hex session identifier.
+func newSessionID() string {
+	buf := make([]byte, 16)
+	if _, err := rand.Read(buf); err != nil {
+		return fmt.Sprintf("sess-%d", time.Now().UnixNano())
+	}
+	return hex.EncodeToString(buf)
+}
+
+// sessionFor returns the sseSession for id, creating one (and subscribing
+// it to process events the first time any session exists) if necessary.
+func (p *MCPPlugin) sessionFor(id string) *sseSession {
+	p.sessionsMu.Lock()
+	defer p.sessionsMu.Unlock()
+	if sess, ok := p.sessions[id]; ok {
+		return sess
+	}
+	sess := newSSESession()
+	p.sessions[id] = sess
+	return sess
+}
+
+// broadcast pushes a notification to every connected session - used for
+// events that aren't scoped to one client, e.g. a build finishing.
+func (p *MCPPlugin) broadcast(method string, params interface{}) {
+	p.sessionsMu.Lock()
+	sessions := make([]*sseSession, 0, len(p.sessions))
+	for _, s := range p.sessions {
+		sessions = append(sessions, s)
+	}
+	p.sessionsMu.Unlock()
+
+	for _, s := range sessions {
+		s.push(method, params)
+	}
+}
+
+func (p *MCPPlugin) closeAllSessions() {
+	p.sessionsMu.Lock()
+	p.sessions = make(map[string]*sseSession)
+	p.sessionsMu.Unlock()
+}
+
+// startHTTP starts the Streamable HTTP transport: JSON-RPC over POST and
+// an SSE notification stream over GET, both on /mcp. Matches
+// metrics.Registry.Serve's fire-and-forget style - bind errors are logged
+// rather than returned, since the listen happens in the background
+// goroutine.
+func (p *MCPPlugin) startHTTP(bindAddress string, port int) error {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/mcp", p.handleHTTP)
+
+	server := &http.Server{Addr: fmt.Sprintf("%s:%d", bindAddress, port), Handler: mux}
+	p.mu.Lock()
+	p.httpServer = server
+	p.mu.Unlock()
+
+	go func() {
+		if err := server.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+			p.ctx.LogError(PluginID, fmt.Errorf("MCP HTTP server: %w", err))
+		}
+	}()
+
+	p.subscribeProcessEvents()
+	return nil
+}
+
+// subscribeProcessEvents wires build/sync/run process lifecycle events
+// through to every connected MCP client as notifications/message pushes,
+// per the request's "notifications/message push from build/sync/run
+// process events surfaced through plugin.Context".
+func (p *MCPPlugin) subscribeProcessEvents() {
+	unsubStarted := p.ctx.Subscribe(plugin.EventProcessStarted, func(data interface{}) {
+		p.broadcast("notifications/message", map[string]interface{}{
+			"level": "info",
+			"data":  data,
+		})
+	})
+	unsubOutput := p.ctx.Subscribe(plugin.EventProcessOutput, func(data interface{}) {
+		p.broadcast("notifications/message", map[string]interface{}{
+			"level": "info",
+			"data":  data,
+		})
+		if e, ok := data.(plugin.ProcessOutputEvent); ok {
+			p.notifyResourceUpdated(processLogURI(e.ProcessID))
+		}
+	})
+	unsubFinished := p.ctx.Subscribe(plugin.EventProcessFinished, func(data interface{}) {
+		p.broadcast("notifications/message", map[string]interface{}{
+			"level": "info",
+			"data":  data,
+		})
+		if e, ok := data.(plugin.ProcessFinishedEvent); ok {
+			p.notifyResourceUpdated(processLogURI(e.ProcessID))
+		}
+	})
+
+	p.mu.Lock()
+	p.unsubEvents = append(p.unsubEvents, unsubStarted, unsubOutput, unsubFinished)
+	p.mu.Unlock()
+}
+
+// handleHTTP dispatches to the JSON-RPC POST handler or the SSE GET
+// handler depending on method, assigning a fresh Mcp-Session-Id if the
+// client didn't send one. Every request must carry "Authorization: Bearer
+// <token>" - checked here so a missing/wrong token never reaches either
+// handler.
+func (p *MCPPlugin) handleHTTP(w http.ResponseWriter, r *http.Request) {
+	if !checkBearer(r.Header.Get("Authorization"), p.authTokenValue()) {
+		http.Error(w, "unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	sessionID := r.Header.Get(mcpSessionHeader)
+	if sessionID == "" {
+		sessionID = newSessionID()
+	}
+	w.Header().Set(mcpSessionHeader, sessionID)
+
+	switch r.Method {
+	case http.MethodPost:
+		p.handleHTTPPost(w, r, sessionID)
+	case http.MethodGet:
+		p.handleHTTPSSE(w, r, sessionID)
+	default:
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+	}
+}
+
+// handleHTTPPost reads one JSON-RPC request from the body and answers it
+// synchronously, exactly like the tcp/stdio transports but over HTTP.
+func (p *MCPPlugin) handleHTTPPost(w http.ResponseWriter, r *http.Request, sessionID string) {
+	defer r.Body.Close()
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		http.Error(w, "failed to read request body", http.StatusBadRequest)
+		return
+	}
+
+	rc := reqContext{
+		sessionID:  sessionID,
+		clientAddr: r.RemoteAddr,
+		bucket:     p.bucketFor(sessionID),
+		notify: func(method string, params interface{}) {
+			p.sessionFor(sessionID).push(method, params)
+		},
+	}
+	response := p.handleRequest(string(body), rc)
+
+	// Creating (or re-confirming) the session here means a client that
+	// never opens the SSE stream still has somewhere for broadcast() to
+	// land once it does.
+	p.sessionFor(sessionID)
+
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(response)
+}
+
+// handleHTTPSSE upgrades the connection to an SSE notification stream,
+// replaying anything buffered since Last-Event-ID before switching to
+// live delivery.
+func (p *MCPPlugin) handleHTTPSSE(w http.ResponseWriter, r *http.Request, sessionID string) {
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		http.Error(w, "streaming unsupported", http.StatusInternalServerError)
+		return
+	}
+
+	lastEventID, _ := strconv.Atoi(r.Header.Get("Last-Event-ID"))
+	sess := p.sessionFor(sessionID)
+
+	ch := make(chan sseEvent, 64)
+	backlog := sess.attach(ch, lastEventID)
+	defer sess.detach(ch)
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	w.WriteHeader(http.StatusOK)
+
+	writeFrame := func(e sseEvent) bool {
+		if _, err := fmt.Fprintf(w, "id: %d\nevent: message\ndata: %s\n\n", e.id, e.data); err != nil {
+			return false
+		}
+		flusher.Flush()
+		return true
+	}
+
+	for _, e := range backlog {
+		if !writeFrame(e) {
+			return
+		}
+	}
+
+	ctx := r.Context()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-p.stopCh:
+			return
+		case e := <-ch:
+			if !writeFrame(e) {
+				return
+			}
+		}
+	}
+}
@@ -5,6 +5,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"net"
+	"net/http"
 	"os"
 	"strings"
 	"sync"
@@ -21,21 +22,67 @@ const (
 
 // MCPPlugin implements the MCP (Model Context Protocol) server
 type MCPPlugin struct {
-	mu       sync.RWMutex
-	ctx      plugin.Context
-	running  bool
-	listener net.Listener
-	mode     string // "stdio" or "tcp"
-	port     int
-	stopCh   chan struct{}
+	mu          sync.RWMutex
+	ctx         plugin.Context
+	running     bool
+	listener    net.Listener
+	mode        string // "stdio", "tcp", or "http"
+	bindAddress string
+	port        int
+	stopCh      chan struct{}
+
+	// HTTP (Streamable HTTP transport, see http.go) state.
+	httpPort    int
+	httpServer  *http.Server
+	sessions    map[string]*sseSession
+	sessionsMu  sync.Mutex
+	unsubEvents []plugin.UnsubscribeFunc
+
+	// resourceSubs tracks resources/subscribe interest (see resources.go).
+	resourceSubs *resourceSubscriptions
+
+	// Auth/authorization (see auth.go). authToken is generated on first
+	// Start() and required as a bearer token on tcp/http; stdio is trusted
+	// implicitly since only the process that spawned lazycap can reach it.
+	authToken        string
+	disabledTools    map[string]struct{}
+	confirmDangerous bool
+	rateLimit        int // requests per minute, per connection/session
+
+	confirmMu      sync.Mutex
+	pendingConfirm map[string]pendingConfirmation
+
+	bucketsMu sync.Mutex
+	buckets   map[string]*tokenBucket // http sessionID -> limiter
+
+	// tracked maps a progress-tracked tools/call's request ID (see
+	// progress.go) to the process it spawned, so a notifications/cancelled
+	// for that request can kill the right process.
+	trackedMu sync.Mutex
+	tracked   map[string]*trackedRequest
+
+	// clients fans server-initiated notifications (see extensions.go) out
+	// to every connected tcp/stdio client; http uses sessions instead.
+	clients *clientBroadcast
 }
 
 // New creates a new MCP plugin instance
 func New() *MCPPlugin {
 	return &MCPPlugin{
-		mode:   "tcp",
-		port:   9315,
-		stopCh: make(chan struct{}),
+		mode:             "tcp",
+		bindAddress:      "127.0.0.1",
+		port:             9315,
+		httpPort:         9316,
+		stopCh:           make(chan struct{}),
+		sessions:         make(map[string]*sseSession),
+		resourceSubs:     newResourceSubscriptions(),
+		disabledTools:    make(map[string]struct{}),
+		confirmDangerous: true,
+		rateLimit:        60,
+		pendingConfirm:   make(map[string]pendingConfirmation),
+		buckets:          make(map[string]*tokenBucket),
+		tracked:          make(map[string]*trackedRequest),
+		clients:          newClientBroadcast(),
 	}
 }
 
@@ -68,7 +115,7 @@ func (p *MCPPlugin) GetSettings() []plugin.Setting {
 			Description: "How to expose the MCP server",
 			Type:        "choice",
 			Default:     "tcp",
-			Choices:     []string{"tcp", "stdio"},
+			Choices:     []string{"tcp", "stdio", "http"},
 		},
 		{
 			Key:         "port",
@@ -77,6 +124,13 @@ func (p *MCPPlugin) GetSettings() []plugin.Setting {
 			Type:        "int",
 			Default:     9315,
 		},
+		{
+			Key:         "httpPort",
+			Name:        "HTTP Port",
+			Description: "Port for the Streamable HTTP transport (http mode) - serves JSON-RPC POSTs and an SSE notification stream on the same port",
+			Type:        "int",
+			Default:     9316,
+		},
 		{
 			Key:         "autoStart",
 			Name:        "Auto Start",
@@ -84,6 +138,34 @@ func (p *MCPPlugin) GetSettings() []plugin.Setting {
 			Type:        "bool",
 			Default:     true,
 		},
+		{
+			Key:         "bindAddress",
+			Name:        "Bind Address",
+			Description: "Interface the tcp/http transports listen on. Leave at 127.0.0.1 unless other machines need access - the server only checks a bearer token, not TLS.",
+			Type:        "string",
+			Default:     "127.0.0.1",
+		},
+		{
+			Key:         "disabledTools",
+			Name:        "Disabled Tools",
+			Description: "Comma-separated tool names to hide from tools/list and reject on tools/call, e.g. \"kill_process,run_debug_action\"",
+			Type:        "string",
+			Default:     "",
+		},
+		{
+			Key:         "confirmDangerous",
+			Name:        "Confirm Dangerous Actions",
+			Description: "Require a second tools/call with arguments.confirm set to a nonce before running tools that can destroy local state (kill_process, run_debug_action)",
+			Type:        "bool",
+			Default:     true,
+		},
+		{
+			Key:         "rateLimit",
+			Name:        "Rate Limit (calls/min)",
+			Description: "Maximum tools/call requests per minute, per tcp connection or http session",
+			Type:        "int",
+			Default:     60,
+		},
 	}
 }
 
@@ -102,6 +184,30 @@ func (p *MCPPlugin) OnSettingChange(key string, value interface{}) {
 		} else if n, ok := value.(int); ok {
 			p.port = n
 		}
+	case "httpPort":
+		if n, ok := value.(float64); ok {
+			p.httpPort = int(n)
+		} else if n, ok := value.(int); ok {
+			p.httpPort = n
+		}
+	case "bindAddress":
+		if s, ok := value.(string); ok && s != "" {
+			p.bindAddress = s
+		}
+	case "disabledTools":
+		if s, ok := value.(string); ok {
+			p.disabledTools = parseToolList(s)
+		}
+	case "confirmDangerous":
+		if b, ok := value.(bool); ok {
+			p.confirmDangerous = b
+		}
+	case "rateLimit":
+		if n, ok := value.(float64); ok {
+			p.rateLimit = int(n)
+		} else if n, ok := value.(int); ok {
+			p.rateLimit = n
+		}
 	}
 }
 
@@ -113,10 +219,17 @@ func (p *MCPPlugin) GetStatusLine() string {
 		return ""
 	}
 
-	if p.mode == "tcp" {
-		return fmt.Sprintf("MCP :%d", p.port)
+	switch p.mode {
+	case "tcp":
+		return fmt.Sprintf("MCP %s:%d (auth)", p.bindAddress, p.port)
+	case "http":
+		p.sessionsMu.Lock()
+		clients := len(p.sessions)
+		p.sessionsMu.Unlock()
+		return fmt.Sprintf("MCP http %s:%d (%d clients, auth)", p.bindAddress, p.httpPort, clients)
+	default:
+		return "MCP stdio"
 	}
-	return "MCP stdio"
 }
 
 func (p *MCPPlugin) GetCommands() []plugin.Command {
@@ -144,6 +257,31 @@ func (p *MCPPlugin) Init(ctx plugin.Context) error {
 			p.port = int(n)
 		}
 	}
+	if httpPort := ctx.GetPluginSetting(PluginID, "httpPort"); httpPort != nil {
+		if n, ok := httpPort.(float64); ok {
+			p.httpPort = int(n)
+		}
+	}
+	if bindAddress := ctx.GetPluginSetting(PluginID, "bindAddress"); bindAddress != nil {
+		if s, ok := bindAddress.(string); ok && s != "" {
+			p.bindAddress = s
+		}
+	}
+	if disabledTools := ctx.GetPluginSetting(PluginID, "disabledTools"); disabledTools != nil {
+		if s, ok := disabledTools.(string); ok {
+			p.disabledTools = parseToolList(s)
+		}
+	}
+	if confirmDangerous := ctx.GetPluginSetting(PluginID, "confirmDangerous"); confirmDangerous != nil {
+		if b, ok := confirmDangerous.(bool); ok {
+			p.confirmDangerous = b
+		}
+	}
+	if rateLimit := ctx.GetPluginSetting(PluginID, "rateLimit"); rateLimit != nil {
+		if n, ok := rateLimit.(float64); ok {
+			p.rateLimit = int(n)
+		}
+	}
 
 	return nil
 }
@@ -157,20 +295,32 @@ func (p *MCPPlugin) Start() error {
 	p.running = true
 	p.stopCh = make(chan struct{})
 	mode := p.mode
+	bindAddress := p.bindAddress
 	port := p.port
+	httpPort := p.httpPort
 	p.mu.Unlock()
 
-	if mode == "stdio" {
+	if mode != "stdio" {
+		p.ensureAuthToken()
+	}
+
+	var err error
+	switch mode {
+	case "stdio":
 		go p.runStdio()
-	} else {
-		if err := p.startTCP(port); err != nil {
-			p.mu.Lock()
-			p.running = false
-			p.mu.Unlock()
-			return err
-		}
+	case "http":
+		err = p.startHTTP(bindAddress, httpPort)
+	default:
+		err = p.startTCP(bindAddress, port)
+	}
+	if err != nil {
+		p.mu.Lock()
+		p.running = false
+		p.mu.Unlock()
+		return err
 	}
 
+	p.subscribeToolChangeEvents()
 	p.ctx.Log(PluginID, fmt.Sprintf("MCP server started (mode: %s)", mode))
 	return nil
 }
@@ -191,16 +341,29 @@ func (p *MCPPlugin) Stop() error {
 		_ = p.listener.Close()
 		p.listener = nil
 	}
+
+	httpServer := p.httpServer
+	p.httpServer = nil
+	unsubs := p.unsubEvents
+	p.unsubEvents = nil
 	p.mu.Unlock()
 
+	if httpServer != nil {
+		_ = httpServer.Close()
+	}
+	p.closeAllSessions()
+	for _, unsub := range unsubs {
+		unsub()
+	}
+
 	p.ctx.Log(PluginID, "MCP server stopped")
 	return nil
 }
 
 // TCP server implementation
 
-func (p *MCPPlugin) startTCP(port int) error {
-	listener, err := net.Listen("tcp", fmt.Sprintf(":%d", port))
+func (p *MCPPlugin) startTCP(bindAddress string, port int) error {
+	listener, err := net.Listen("tcp", fmt.Sprintf("%s:%d", bindAddress, port))
 	if err != nil {
 		return fmt.Errorf("failed to start MCP server: %w", err)
 	}
@@ -228,11 +391,40 @@ func (p *MCPPlugin) acceptConnections(listener net.Listener) {
 	}
 }
 
+// handleConnection serves one TCP client. The first line on the wire must
+// be "Authorization: Bearer <token>" (there's no HTTP to carry a real
+// header over raw TCP) before any JSON-RPC request is accepted.
+//
+// Each request runs in its own goroutine so a progress-tracked tools/call
+// (see progress.go) can block this client's requests in flight while the
+// scanner keeps reading - otherwise a notifications/cancelled for it could
+// never arrive. Writes share one encoder, so they're serialized by writeMu.
 func (p *MCPPlugin) handleConnection(conn net.Conn) {
 	defer func() { _ = conn.Close() }()
 
+	clientAddr := conn.RemoteAddr().String()
 	scanner := bufio.NewScanner(conn)
 	encoder := json.NewEncoder(conn)
+	var writeMu sync.Mutex
+
+	if !scanner.Scan() {
+		return
+	}
+	if !p.checkTCPAuthLine(scanner.Text()) {
+		_ = encoder.Encode(MCPResponse{JSONRPC: "2.0", Error: &MCPError{Code: -32000, Message: "unauthorized: expected 'Authorization: Bearer <token>' as the first line"}})
+		return
+	}
+
+	bucket := newTokenBucket(p.rateLimitSnapshot())
+	notify := func(method string, params interface{}) {
+		writeMu.Lock()
+		defer writeMu.Unlock()
+		_ = encoder.Encode(mcpNotification{JSONRPC: "2.0", Method: method, Params: params})
+	}
+	defer p.clients.register(notify)()
+
+	var wg sync.WaitGroup
+	defer wg.Wait()
 
 	for scanner.Scan() {
 		select {
@@ -242,8 +434,16 @@ func (p *MCPPlugin) handleConnection(conn net.Conn) {
 		}
 
 		line := scanner.Text()
-		response := p.handleRequest(line)
-		_ = encoder.Encode(response)
+		rc := reqContext{clientAddr: clientAddr, bucket: bucket, notify: notify}
+
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			response := p.handleRequest(line, rc)
+			writeMu.Lock()
+			defer writeMu.Unlock()
+			_ = encoder.Encode(response)
+		}()
 	}
 }
 
@@ -252,6 +452,21 @@ func (p *MCPPlugin) handleConnection(conn net.Conn) {
 func (p *MCPPlugin) runStdio() {
 	scanner := bufio.NewScanner(os.Stdin)
 	encoder := json.NewEncoder(os.Stdout)
+	var writeMu sync.Mutex
+
+	// stdio is only reachable by the process that spawned lazycap, so it
+	// skips both the bearer token check and the rate limiter. Requests
+	// still run one per goroutine (see handleConnection) so a
+	// notifications/cancelled for a progress-tracked call can get through.
+	notify := func(method string, params interface{}) {
+		writeMu.Lock()
+		defer writeMu.Unlock()
+		_ = encoder.Encode(mcpNotification{JSONRPC: "2.0", Method: method, Params: params})
+	}
+	defer p.clients.register(notify)()
+
+	var wg sync.WaitGroup
+	defer wg.Wait()
 
 	for scanner.Scan() {
 		select {
@@ -261,8 +476,16 @@ func (p *MCPPlugin) runStdio() {
 		}
 
 		line := scanner.Text()
-		response := p.handleRequest(line)
-		_ = encoder.Encode(response)
+		rc := reqContext{clientAddr: "stdio", notify: notify}
+
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			response := p.handleRequest(line, rc)
+			writeMu.Lock()
+			defer writeMu.Unlock()
+			_ = encoder.Encode(response)
+		}()
 	}
 }
 
@@ -291,10 +514,43 @@ type ToolInfo struct {
 	Name        string                 `json:"name"`
 	Description string                 `json:"description"`
 	InputSchema map[string]interface{} `json:"inputSchema"`
+	// OutputSchema mirrors the JSON Schema of this tool's structuredContent
+	// (see structuredToolResult), so a client can validate/type the result
+	// instead of re-parsing the fallback text blob. Omitted for tools whose
+	// result is just a status message.
+	OutputSchema map[string]interface{} `json:"outputSchema,omitempty"`
+	// Dangerous marks tools that can destroy local state (killing a build,
+	// wiping node_modules via run_debug_action). When confirmDangerous is
+	// on, the first call to one of these returns a confirmation nonce
+	// instead of running - see requireConfirmation in auth.go.
+	Dangerous bool `json:"dangerous,omitempty"`
+}
+
+// mcpNotification is a server-initiated JSON-RPC notification - no ID, no
+// response expected. Used for notifications/progress pushed mid tools/call
+// (see progress.go) over tcp/stdio; the http transport pushes the same
+// shape through its SSE session (see sseSession.push in http.go).
+type mcpNotification struct {
+	JSONRPC string      `json:"jsonrpc"`
+	Method  string      `json:"method"`
+	Params  interface{} `json:"params,omitempty"`
+}
+
+// reqContext carries the per-request, transport-specific state handleRequest
+// and handleToolsCall need: who's calling (for the audit log and resource
+// subscriptions), how fast they're allowed to call tools/call, this
+// request's ID (for progress tracking/cancellation), and how to push a
+// notifications/progress frame back to this client mid-call.
+type reqContext struct {
+	sessionID  string       // Streamable HTTP session (see http.go); "" for tcp/stdio
+	clientAddr string       // for the tools/call audit log
+	bucket     *tokenBucket // nil for stdio, which isn't rate limited
+	reqID      interface{}
+	notify     func(method string, params interface{})
 }
 
-// handleRequest processes an MCP request and returns a response
-func (p *MCPPlugin) handleRequest(line string) MCPResponse {
+// handleRequest processes an MCP request and returns a response.
+func (p *MCPPlugin) handleRequest(line string, rc reqContext) MCPResponse {
 	var req MCPRequest
 	if err := json.Unmarshal([]byte(line), &req); err != nil {
 		return MCPResponse{
@@ -302,6 +558,7 @@ func (p *MCPPlugin) handleRequest(line string) MCPResponse {
 			Error:   &MCPError{Code: -32700, Message: "Parse error"},
 		}
 	}
+	rc.reqID = req.ID
 
 	response := MCPResponse{
 		JSONRPC: "2.0",
@@ -314,7 +571,44 @@ func (p *MCPPlugin) handleRequest(line string) MCPResponse {
 	case "tools/list":
 		response.Result = p.handleToolsList()
 	case "tools/call":
-		response.Result, response.Error = p.handleToolsCall(req.Params)
+		if rc.bucket != nil && !rc.bucket.Allow() {
+			response.Error = &MCPError{Code: -32000, Message: "rate limit exceeded"}
+			break
+		}
+		response.Result, response.Error = p.handleToolsCall(req.Params, rc)
+	case "notifications/cancelled":
+		var params struct {
+			RequestID interface{} `json:"requestId"`
+		}
+		_ = json.Unmarshal(req.Params, &params)
+		p.cancelTrackedRequest(requestKey(params.RequestID))
+	case "resources/list":
+		response.Result = p.handleResourcesList()
+	case "resources/read":
+		var params resourcesReadParams
+		if err := json.Unmarshal(req.Params, &params); err != nil {
+			response.Error = &MCPError{Code: -32602, Message: "Invalid params"}
+		} else {
+			response.Result, response.Error = p.handleResourcesRead(params)
+		}
+	case "resources/subscribe":
+		var params resourcesReadParams
+		if err := json.Unmarshal(req.Params, &params); err != nil {
+			response.Error = &MCPError{Code: -32602, Message: "Invalid params"}
+		} else {
+			response.Result, response.Error = p.handleResourcesSubscribe(params, rc.sessionID)
+		}
+	case "resources/unsubscribe":
+		var params resourcesReadParams
+		if err := json.Unmarshal(req.Params, &params); err != nil {
+			response.Error = &MCPError{Code: -32602, Message: "Invalid params"}
+		} else {
+			response.Result, response.Error = p.handleResourcesUnsubscribe(params, rc.sessionID)
+		}
+	case "prompts/list":
+		response.Result = p.handlePromptsList()
+	case "prompts/get":
+		response.Result, response.Error = p.handlePromptsGet(req.Params)
 	default:
 		response.Error = &MCPError{Code: -32601, Message: "Method not found"}
 	}
@@ -331,7 +625,9 @@ func (p *MCPPlugin) handleInitialize() map[string]interface{} {
 			"description": "Capacitor/Ionic mobile app development tools - controls native builds, device deployment, emulators, and Firebase services",
 		},
 		"capabilities": map[string]interface{}{
-			"tools": map[string]interface{}{},
+			"tools":     map[string]interface{}{},
+			"resources": map[string]interface{}{"subscribe": true},
+			"prompts":   map[string]interface{}{},
 		},
 	}
 }
@@ -345,6 +641,20 @@ func (p *MCPPlugin) handleToolsList() map[string]interface{} {
 				"type":       "object",
 				"properties": map[string]interface{}{},
 			},
+			OutputSchema: map[string]interface{}{
+				"type": "array",
+				"items": map[string]interface{}{
+					"type": "object",
+					"properties": map[string]interface{}{
+						"id":         map[string]interface{}{"type": "string"},
+						"name":       map[string]interface{}{"type": "string"},
+						"platform":   map[string]interface{}{"type": "string"},
+						"online":     map[string]interface{}{"type": "boolean"},
+						"isEmulator": map[string]interface{}{"type": "boolean"},
+						"isWeb":      map[string]interface{}{"type": "boolean"},
+					},
+				},
+			},
 		},
 		{
 			Name:        "run_on_device",
@@ -414,6 +724,18 @@ func (p *MCPPlugin) handleToolsList() map[string]interface{} {
 				"type":       "object",
 				"properties": map[string]interface{}{},
 			},
+			OutputSchema: map[string]interface{}{
+				"type": "array",
+				"items": map[string]interface{}{
+					"type": "object",
+					"properties": map[string]interface{}{
+						"id":      map[string]interface{}{"type": "string"},
+						"name":    map[string]interface{}{"type": "string"},
+						"command": map[string]interface{}{"type": "string"},
+						"status":  map[string]interface{}{"type": "string"},
+					},
+				},
+			},
 		},
 		{
 			Name:        "get_logs",
@@ -428,6 +750,13 @@ func (p *MCPPlugin) handleToolsList() map[string]interface{} {
 				},
 				"required": []string{"processId"},
 			},
+			OutputSchema: map[string]interface{}{
+				"type": "object",
+				"properties": map[string]interface{}{
+					"processId": map[string]interface{}{"type": "string"},
+					"lines":     map[string]interface{}{"type": "array", "items": map[string]interface{}{"type": "string"}},
+				},
+			},
 		},
 		{
 			Name:        "get_all_logs",
@@ -458,6 +787,18 @@ func (p *MCPPlugin) handleToolsList() map[string]interface{} {
 					},
 				},
 			},
+			OutputSchema: map[string]interface{}{
+				"type": "object",
+				"additionalProperties": map[string]interface{}{
+					"type": "object",
+					"properties": map[string]interface{}{
+						"name":    map[string]interface{}{"type": "string"},
+						"status":  map[string]interface{}{"type": "string"},
+						"command": map[string]interface{}{"type": "string"},
+						"logs":    map[string]interface{}{"type": "array", "items": map[string]interface{}{"type": "string"}},
+					},
+				},
+			},
 		},
 		{
 			Name:        "kill_process",
@@ -469,9 +810,14 @@ func (p *MCPPlugin) handleToolsList() map[string]interface{} {
 						"type":        "string",
 						"description": "Process ID from get_processes",
 					},
+					"confirm": map[string]interface{}{
+						"type":        "string",
+						"description": "Confirmation nonce from a prior call that returned confirmationRequired (only needed when the server's Confirm Dangerous Actions setting is on)",
+					},
 				},
 				"required": []string{"processId"},
 			},
+			Dangerous: true,
 		},
 		{
 			Name:        "get_debug_actions",
@@ -480,6 +826,19 @@ func (p *MCPPlugin) handleToolsList() map[string]interface{} {
 				"type":       "object",
 				"properties": map[string]interface{}{},
 			},
+			OutputSchema: map[string]interface{}{
+				"type": "array",
+				"items": map[string]interface{}{
+					"type": "object",
+					"properties": map[string]interface{}{
+						"id":          map[string]interface{}{"type": "string"},
+						"name":        map[string]interface{}{"type": "string"},
+						"description": map[string]interface{}{"type": "string"},
+						"category":    map[string]interface{}{"type": "string"},
+						"dangerous":   map[string]interface{}{"type": "boolean"},
+					},
+				},
+			},
 		},
 		{
 			Name:        "run_debug_action",
@@ -491,9 +850,14 @@ func (p *MCPPlugin) handleToolsList() map[string]interface{} {
 						"type":        "string",
 						"description": "Action ID from get_debug_actions",
 					},
+					"confirm": map[string]interface{}{
+						"type":        "string",
+						"description": "Confirmation nonce from a prior call that returned confirmationRequired (only needed when the server's Confirm Dangerous Actions setting is on)",
+					},
 				},
 				"required": []string{"actionId"},
 			},
+			Dangerous: true,
 		},
 		{
 			Name:        "get_settings",
@@ -502,6 +866,9 @@ func (p *MCPPlugin) handleToolsList() map[string]interface{} {
 				"type":       "object",
 				"properties": map[string]interface{}{},
 			},
+			OutputSchema: map[string]interface{}{
+				"type": "object",
+			},
 		},
 		{
 			Name:        "set_setting",
@@ -527,23 +894,59 @@ func (p *MCPPlugin) handleToolsList() map[string]interface{} {
 				"type":       "object",
 				"properties": map[string]interface{}{},
 			},
+			OutputSchema: map[string]interface{}{
+				"type": "object",
+				"properties": map[string]interface{}{
+					"name":       map[string]interface{}{"type": "string"},
+					"appId":      map[string]interface{}{"type": "string"},
+					"webDir":     map[string]interface{}{"type": "string"},
+					"hasAndroid": map[string]interface{}{"type": "boolean"},
+					"hasIOS":     map[string]interface{}{"type": "boolean"},
+					"rootDir":    map[string]interface{}{"type": "string"},
+				},
+			},
 		},
 	}
 
+	tools = append(tools, p.externalTools()...)
+
+	visible := tools[:0]
+	for _, t := range tools {
+		if !p.isToolDisabled(t.Name) {
+			visible = append(visible, t)
+		}
+	}
+
 	return map[string]interface{}{
-		"tools": tools,
+		"tools": visible,
 	}
 }
 
-func (p *MCPPlugin) handleToolsCall(params json.RawMessage) (interface{}, *MCPError) {
+func (p *MCPPlugin) handleToolsCall(params json.RawMessage, rc reqContext) (interface{}, *MCPError) {
 	var call struct {
 		Name      string                 `json:"name"`
 		Arguments map[string]interface{} `json:"arguments"`
+		Meta      struct {
+			ProgressToken interface{} `json:"progressToken"`
+		} `json:"_meta"`
 	}
 	if err := json.Unmarshal(params, &call); err != nil {
 		return nil, &MCPError{Code: -32602, Message: "Invalid params"}
 	}
 
+	if p.isToolDisabled(call.Name) {
+		return nil, &MCPError{Code: -32601, Message: "tool disabled: " + call.Name}
+	}
+	p.auditLog(rc.clientAddr, call.Name, call.Arguments)
+
+	if result, mcpErr, handled := p.requireConfirmation(call.Name, call.Arguments); handled {
+		return result, mcpErr
+	}
+
+	if call.Meta.ProgressToken != nil && trackableTools[call.Name] && rc.notify != nil {
+		return p.runTrackedTool(call.Name, call.Arguments, call.Meta.ProgressToken, requestKey(rc.reqID), rc.notify)
+	}
+
 	switch call.Name {
 	case "list_devices":
 		return p.toolListDevices()
@@ -576,12 +979,26 @@ func (p *MCPPlugin) handleToolsCall(params json.RawMessage) (interface{}, *MCPEr
 	case "get_project":
 		return p.toolGetProject()
 	default:
+		if result, mcpErr, ok := p.dispatchExternalTool(call.Name, call.Arguments); ok {
+			return result, mcpErr
+		}
 		return nil, &MCPError{Code: -32601, Message: "Unknown tool: " + call.Name}
 	}
 }
 
 // Tool implementations
 
+// structuredToolResult builds a tools/call result carrying both the
+// structured data a client that understands structuredContent can use
+// directly, and the original single text blob (toJSON'd structured) as a
+// fallback for clients that only look at content.
+func structuredToolResult(structured interface{}) map[string]interface{} {
+	return map[string]interface{}{
+		"content":           []map[string]interface{}{{"type": "text", "text": toJSON(structured)}},
+		"structuredContent": structured,
+	}
+}
+
 func (p *MCPPlugin) toolListDevices() (interface{}, *MCPError) {
 	devices := p.ctx.GetDevices()
 	result := make([]map[string]interface{}, len(devices))
@@ -595,7 +1012,7 @@ func (p *MCPPlugin) toolListDevices() (interface{}, *MCPError) {
 			"isWeb":      d.IsWeb,
 		}
 	}
-	return map[string]interface{}{"content": []map[string]interface{}{{"type": "text", "text": toJSON(result)}}}, nil
+	return structuredToolResult(result), nil
 }
 
 func (p *MCPPlugin) toolRunOnDevice(args map[string]interface{}) (interface{}, *MCPError) {
@@ -610,14 +1027,14 @@ func (p *MCPPlugin) toolRunOnDevice(args map[string]interface{}) (interface{}, *
 		return nil, &MCPError{Code: -32000, Message: err.Error()}
 	}
 
-	return map[string]interface{}{"content": []map[string]interface{}{{"type": "text", "text": "Started run on " + deviceID}}}, nil
+	return structuredToolResult(map[string]interface{}{"status": "started", "deviceId": deviceID}), nil
 }
 
 func (p *MCPPlugin) toolRunWeb() (interface{}, *MCPError) {
 	if err := p.ctx.RunWeb(); err != nil {
 		return nil, &MCPError{Code: -32000, Message: err.Error()}
 	}
-	return map[string]interface{}{"content": []map[string]interface{}{{"type": "text", "text": "Web dev server started"}}}, nil
+	return structuredToolResult(map[string]interface{}{"status": "started"}), nil
 }
 
 func (p *MCPPlugin) toolSync(args map[string]interface{}) (interface{}, *MCPError) {
@@ -625,18 +1042,14 @@ func (p *MCPPlugin) toolSync(args map[string]interface{}) (interface{}, *MCPErro
 	if err := p.ctx.Sync(platform); err != nil {
 		return nil, &MCPError{Code: -32000, Message: err.Error()}
 	}
-	msg := "Sync started"
-	if platform != "" {
-		msg = "Sync started for " + platform
-	}
-	return map[string]interface{}{"content": []map[string]interface{}{{"type": "text", "text": msg}}}, nil
+	return structuredToolResult(map[string]interface{}{"status": "started", "platform": platform}), nil
 }
 
 func (p *MCPPlugin) toolBuild() (interface{}, *MCPError) {
 	if err := p.ctx.Build(); err != nil {
 		return nil, &MCPError{Code: -32000, Message: err.Error()}
 	}
-	return map[string]interface{}{"content": []map[string]interface{}{{"type": "text", "text": "Build started"}}}, nil
+	return structuredToolResult(map[string]interface{}{"status": "started"}), nil
 }
 
 func (p *MCPPlugin) toolOpenIDE(args map[string]interface{}) (interface{}, *MCPError) {
@@ -647,21 +1060,41 @@ func (p *MCPPlugin) toolOpenIDE(args map[string]interface{}) (interface{}, *MCPE
 	if err := p.ctx.OpenIDE(platform); err != nil {
 		return nil, &MCPError{Code: -32000, Message: err.Error()}
 	}
-	return map[string]interface{}{"content": []map[string]interface{}{{"type": "text", "text": "Opening " + platform + " IDE"}}}, nil
+	return structuredToolResult(map[string]interface{}{"status": "opened", "platform": platform}), nil
 }
 
 func (p *MCPPlugin) toolGetProcesses() (interface{}, *MCPError) {
 	processes := p.ctx.GetProcesses()
-	return map[string]interface{}{"content": []map[string]interface{}{{"type": "text", "text": toJSON(processes)}}}, nil
+	return structuredToolResult(processes), nil
 }
 
+// toolGetLogs emits one typed content part per log line (plus a combined
+// "resource" part carrying the whole tail) instead of a single JSON
+// string, alongside the usual structuredContent fallback.
 func (p *MCPPlugin) toolGetLogs(args map[string]interface{}) (interface{}, *MCPError) {
 	processID, _ := args["processId"].(string)
 	if processID == "" {
 		return nil, &MCPError{Code: -32602, Message: "processId required"}
 	}
 	logs := p.ctx.GetProcessLogs(processID)
-	return map[string]interface{}{"content": []map[string]interface{}{{"type": "text", "text": toJSON(logs)}}}, nil
+
+	content := make([]map[string]interface{}, 0, len(logs)+1)
+	content = append(content, map[string]interface{}{
+		"type": "resource",
+		"resource": map[string]interface{}{
+			"uri":      processLogURI(processID),
+			"mimeType": "text/plain",
+			"text":     strings.Join(logs, "\n"),
+		},
+	})
+	for _, line := range logs {
+		content = append(content, map[string]interface{}{"type": "text", "text": line})
+	}
+
+	return map[string]interface{}{
+		"content":           content,
+		"structuredContent": map[string]interface{}{"processId": processID, "lines": logs},
+	}, nil
 }
 
 func (p *MCPPlugin) toolGetAllLogs(args map[string]interface{}) (interface{}, *MCPError) {
@@ -745,7 +1178,7 @@ func (p *MCPPlugin) toolGetAllLogs(args map[string]interface{}) (interface{}, *M
 		}
 	}
 
-	return map[string]interface{}{"content": []map[string]interface{}{{"type": "text", "text": toJSON(result)}}}, nil
+	return structuredToolResult(map[string]interface{}{"processes": result}), nil
 }
 
 // containsIgnoreCase checks if s contains substr (case-insensitive)
@@ -761,7 +1194,7 @@ func (p *MCPPlugin) toolKillProcess(args map[string]interface{}) (interface{}, *
 	if err := p.ctx.KillProcess(processID); err != nil {
 		return nil, &MCPError{Code: -32000, Message: err.Error()}
 	}
-	return map[string]interface{}{"content": []map[string]interface{}{{"type": "text", "text": "Process killed"}}}, nil
+	return structuredToolResult(map[string]interface{}{"status": "killed", "processId": processID}), nil
 }
 
 func (p *MCPPlugin) toolGetDebugActions() (interface{}, *MCPError) {
@@ -776,7 +1209,7 @@ func (p *MCPPlugin) toolGetDebugActions() (interface{}, *MCPError) {
 			"dangerous":   a.Dangerous,
 		}
 	}
-	return map[string]interface{}{"content": []map[string]interface{}{{"type": "text", "text": toJSON(result)}}}, nil
+	return structuredToolResult(result), nil
 }
 
 func (p *MCPPlugin) toolRunDebugAction(args map[string]interface{}) (interface{}, *MCPError) {
@@ -785,12 +1218,12 @@ func (p *MCPPlugin) toolRunDebugAction(args map[string]interface{}) (interface{}
 		return nil, &MCPError{Code: -32602, Message: "actionId required"}
 	}
 	result := p.ctx.RunDebugAction(actionID)
-	return map[string]interface{}{"content": []map[string]interface{}{{"type": "text", "text": toJSON(result)}}}, nil
+	return structuredToolResult(result), nil
 }
 
 func (p *MCPPlugin) toolGetSettings() (interface{}, *MCPError) {
 	settings := p.ctx.GetSettings()
-	return map[string]interface{}{"content": []map[string]interface{}{{"type": "text", "text": toJSON(settings)}}}, nil
+	return structuredToolResult(settings), nil
 }
 
 func (p *MCPPlugin) toolSetSetting(args map[string]interface{}) (interface{}, *MCPError) {
@@ -802,7 +1235,7 @@ func (p *MCPPlugin) toolSetSetting(args map[string]interface{}) (interface{}, *M
 	if err := p.ctx.SetSetting(key, value); err != nil {
 		return nil, &MCPError{Code: -32000, Message: err.Error()}
 	}
-	return map[string]interface{}{"content": []map[string]interface{}{{"type": "text", "text": "Setting updated"}}}, nil
+	return structuredToolResult(map[string]interface{}{"status": "updated", "key": key}), nil
 }
 
 func (p *MCPPlugin) toolGetProject() (interface{}, *MCPError) {
@@ -818,7 +1251,7 @@ func (p *MCPPlugin) toolGetProject() (interface{}, *MCPError) {
 		"hasIOS":     project.HasIOS,
 		"rootDir":    project.RootDir,
 	}
-	return map[string]interface{}{"content": []map[string]interface{}{{"type": "text", "text": toJSON(result)}}}, nil
+	return structuredToolResult(result), nil
 }
 
 func toJSON(v interface{}) string {
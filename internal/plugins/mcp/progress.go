@@ -0,0 +1,196 @@
+package mcp
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strconv"
+	"time"
+
+	"github.com/icarus-itcs/lazycap/internal/plugin"
+)
+
+// trackableTools are the tools runTrackedTool knows how to start and
+// follow to completion - the ones the request calls out as "return
+// immediately with 'Started' text": build, sync, run_on_device.
+var trackableTools = map[string]bool{
+	"build":         true,
+	"sync":          true,
+	"run_on_device": true,
+}
+
+// trackedRequest links an in-flight progress-tracked tools/call to the
+// process it spawned, so notifications/cancelled (looked up by request ID,
+// see requestKey) can kill the right one and unblock runTrackedTool.
+type trackedRequest struct {
+	processID string
+	cancel    context.CancelFunc
+}
+
+// requestKey canonicalizes a JSON-RPC request ID (string, number, or null)
+// into a map key.
+func requestKey(id interface{}) string {
+	return fmt.Sprint(id)
+}
+
+func (p *MCPPlugin) registerTracked(key string, tr *trackedRequest) {
+	p.trackedMu.Lock()
+	p.tracked[key] = tr
+	p.trackedMu.Unlock()
+}
+
+func (p *MCPPlugin) unregisterTracked(key string) {
+	p.trackedMu.Lock()
+	delete(p.tracked, key)
+	p.trackedMu.Unlock()
+}
+
+// cancelTrackedRequest handles a notifications/cancelled for key: kills the
+// process it spawned (if one has started yet) and cancels its context so
+// runTrackedTool returns even if the Finished event never arrives.
+func (p *MCPPlugin) cancelTrackedRequest(key string) {
+	p.trackedMu.Lock()
+	tr, ok := p.tracked[key]
+	p.trackedMu.Unlock()
+	if !ok {
+		return
+	}
+	if tr.processID != "" {
+		_ = p.ctx.KillProcess(tr.processID)
+	}
+	tr.cancel()
+}
+
+// startTrackable kicks off the process runTrackedTool will follow.
+func (p *MCPPlugin) startTrackable(tool string, args map[string]interface{}) error {
+	switch tool {
+	case "build":
+		return p.ctx.Build()
+	case "sync":
+		platform, _ := args["platform"].(string)
+		return p.ctx.Sync(platform)
+	case "run_on_device":
+		deviceID, _ := args["deviceId"].(string)
+		if deviceID == "" {
+			return fmt.Errorf("deviceId required")
+		}
+		liveReload, _ := args["liveReload"].(bool)
+		return p.ctx.RunOnDevice(deviceID, liveReload)
+	default:
+		return fmt.Errorf("tool %s does not support progress tracking", tool)
+	}
+}
+
+// startWait bounds how long runTrackedTool waits for the process it just
+// started to actually show up as an EventProcessStarted.
+const startWait = 10 * time.Second
+
+// tailLines bounds how many of the finished process's log lines come back
+// in the terminal tools/call result.
+const tailLines = 50
+
+// progressFractionRe pulls an "N/M" counter out of a build log line, e.g.
+// Xcode's "CompileC 42/198" or a Gradle task count - present on some
+// lines, absent on others (like a bare "> Task :app:mergeReleaseResources",
+// which still gets pushed as a progress message with progress/total 0).
+var progressFractionRe = regexp.MustCompile(`(\d+)/(\d+)`)
+
+func parseProgressLine(line string) (progress, total int) {
+	m := progressFractionRe.FindStringSubmatch(line)
+	if m == nil {
+		return 0, 0
+	}
+	progress, _ = strconv.Atoi(m[1])
+	total, _ = strconv.Atoi(m[2])
+	return progress, total
+}
+
+// runTrackedTool implements the MCP-spec progress flow for build/sync/
+// run_on_device: start the process, subscribe to its output and lifecycle
+// events, push a notifications/progress frame per log line (with a parsed
+// percentage where the line has one), and resolve once it finishes - or
+// once notifications/cancelled kills it via reqKey (see
+// cancelTrackedRequest).
+func (p *MCPPlugin) runTrackedTool(tool string, args map[string]interface{}, progressToken interface{}, reqKey string, notify func(method string, params interface{})) (interface{}, *MCPError) {
+	ctx, cancel := context.WithCancel(context.Background())
+	tr := &trackedRequest{cancel: cancel}
+	p.registerTracked(reqKey, tr)
+	defer p.unregisterTracked(reqKey)
+
+	started := make(chan plugin.ProcessStartedEvent, 1)
+	unsubStarted := p.ctx.Subscribe(plugin.EventProcessStarted, func(data interface{}) {
+		if e, ok := data.(plugin.ProcessStartedEvent); ok {
+			select {
+			case started <- e:
+			default:
+			}
+		}
+	})
+	defer unsubStarted()
+
+	if err := p.startTrackable(tool, args); err != nil {
+		return nil, &MCPError{Code: -32000, Message: err.Error()}
+	}
+
+	var processID string
+	select {
+	case e := <-started:
+		processID = e.ProcessID
+	case <-time.After(startWait):
+		return nil, &MCPError{Code: -32000, Message: "timed out waiting for " + tool + " to start"}
+	case <-ctx.Done():
+		return nil, &MCPError{Code: -32000, Message: "cancelled"}
+	}
+
+	p.trackedMu.Lock()
+	tr.processID = processID
+	p.trackedMu.Unlock()
+
+	finished := make(chan plugin.ProcessFinishedEvent, 1)
+	unsubOutput := p.ctx.Subscribe(plugin.EventProcessOutput, func(data interface{}) {
+		e, ok := data.(plugin.ProcessOutputEvent)
+		if !ok || e.ProcessID != processID {
+			return
+		}
+		progress, total := parseProgressLine(e.Line)
+		notify("notifications/progress", map[string]interface{}{
+			"progressToken": progressToken,
+			"progress":      progress,
+			"total":         total,
+			"message":       e.Line,
+		})
+	})
+	defer unsubOutput()
+	unsubFinished := p.ctx.Subscribe(plugin.EventProcessFinished, func(data interface{}) {
+		if e, ok := data.(plugin.ProcessFinishedEvent); ok && e.ProcessID == processID {
+			select {
+			case finished <- e:
+			default:
+			}
+		}
+	})
+	defer unsubFinished()
+
+	select {
+	case e := <-finished:
+		return structuredToolResult(trackedToolResult(processID, e.Success, e.Error, p.ctx.GetProcessLogs(processID), false)), nil
+	case <-ctx.Done():
+		return structuredToolResult(trackedToolResult(processID, false, nil, p.ctx.GetProcessLogs(processID), true)), nil
+	}
+}
+
+func trackedToolResult(processID string, success bool, procErr error, logs []string, cancelled bool) map[string]interface{} {
+	if len(logs) > tailLines {
+		logs = logs[len(logs)-tailLines:]
+	}
+	result := map[string]interface{}{
+		"processId": processID,
+		"success":   success,
+		"cancelled": cancelled,
+		"logs":      logs,
+	}
+	if procErr != nil {
+		result["error"] = procErr.Error()
+	}
+	return result
+}
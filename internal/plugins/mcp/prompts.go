@@ -0,0 +1,158 @@
+package mcp
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/icarus-itcs/lazycap/internal/plugin"
+)
+
+// PromptArgument describes one named input a prompts/get call accepts.
+type PromptArgument struct {
+	Name        string `json:"name"`
+	Description string `json:"description,omitempty"`
+	Required    bool   `json:"required,omitempty"`
+}
+
+// PromptInfo describes one parameterized prompt template returned by
+// prompts/list.
+type PromptInfo struct {
+	Name        string           `json:"name"`
+	Description string           `json:"description"`
+	Arguments   []PromptArgument `json:"arguments,omitempty"`
+}
+
+// promptsListResult is the fixed set of prompt templates lazycap exposes.
+// Each pre-fills itself with the relevant process's logs (see
+// handlePromptsGet) so the assistant doesn't have to chase the process ID
+// down through get_processes/get_logs first.
+func promptsListResult() []PromptInfo {
+	return []PromptInfo{
+		{
+			Name:        "diagnose_ios_build_failure",
+			Description: "Diagnose why the most recent iOS build/run failed, with the relevant Xcode output inlined",
+			Arguments: []PromptArgument{
+				{Name: "processId", Description: "iOS build/run process ID (defaults to the most recent failed iOS process)"},
+			},
+		},
+		{
+			Name:        "explain_last_gradle_error",
+			Description: "Explain the most recent Android/Gradle build error, with the relevant Gradle output inlined",
+			Arguments: []PromptArgument{
+				{Name: "processId", Description: "Android build/run process ID (defaults to the most recent failed Android process)"},
+			},
+		},
+		{
+			Name:        "generate_cap_sync_checklist",
+			Description: "Generate a checklist for running 'npx cap sync' safely given the current project and any in-flight processes",
+		},
+	}
+}
+
+func (p *MCPPlugin) handlePromptsList() map[string]interface{} {
+	return map[string]interface{}{"prompts": promptsListResult()}
+}
+
+func (p *MCPPlugin) handlePromptsGet(params json.RawMessage) (interface{}, *MCPError) {
+	var req struct {
+		Name      string            `json:"name"`
+		Arguments map[string]string `json:"arguments"`
+	}
+	if err := json.Unmarshal(params, &req); err != nil {
+		return nil, &MCPError{Code: -32602, Message: "Invalid params"}
+	}
+
+	switch req.Name {
+	case "diagnose_ios_build_failure":
+		return p.promptDiagnoseFailure(req.Arguments["processId"], "ios", []string{"ios", "xcode", "cap run ios"})
+	case "explain_last_gradle_error":
+		return p.promptDiagnoseFailure(req.Arguments["processId"], "android", []string{"android", "gradle", "cap run android"})
+	case "generate_cap_sync_checklist":
+		return p.promptSyncChecklist()
+	default:
+		return nil, &MCPError{Code: -32602, Message: "unknown prompt: " + req.Name}
+	}
+}
+
+// promptDiagnoseFailure builds a "diagnose this failure" prompt message
+// pre-filled with the named process's logs, or (if processID is empty)
+// the most recent failed process whose name/command matches one of
+// keywords.
+func (p *MCPPlugin) promptDiagnoseFailure(processID, platform string, keywords []string) (interface{}, *MCPError) {
+	proc := p.findProcess(processID, keywords)
+	if proc == nil {
+		return nil, &MCPError{Code: -32000, Message: fmt.Sprintf("no %s process found", platform)}
+	}
+
+	logs := p.ctx.GetProcessLogs(proc.ID)
+	text := fmt.Sprintf(
+		"Diagnose why the %s process %q (%s) failed. Here is its full output:\n\n%s",
+		platform, proc.Name, proc.Command, strings.Join(logs, "\n"),
+	)
+
+	return map[string]interface{}{
+		"description": fmt.Sprintf("Diagnose %s failure for process %s", platform, proc.ID),
+		"messages": []map[string]interface{}{
+			{"role": "user", "content": map[string]interface{}{"type": "text", "text": text}},
+		},
+	}, nil
+}
+
+func (p *MCPPlugin) promptSyncChecklist() (interface{}, *MCPError) {
+	project := p.ctx.GetProject()
+	if project == nil {
+		return nil, &MCPError{Code: -32000, Message: "no project loaded"}
+	}
+
+	var running []string
+	for _, proc := range p.ctx.GetProcesses() {
+		if proc.Status == "running" {
+			running = append(running, fmt.Sprintf("%s (%s)", proc.Name, proc.Command))
+		}
+	}
+
+	text := fmt.Sprintf(
+		"Generate a checklist for safely running 'npx cap sync' on the project %q (app ID %s, web dir %s, iOS: %v, Android: %v).",
+		project.Name, project.AppID, project.WebDir, project.HasIOS, project.HasAndroid,
+	)
+	if len(running) > 0 {
+		text += fmt.Sprintf(" Note these processes are currently running and may need to finish or be stopped first: %s.", strings.Join(running, ", "))
+	}
+
+	return map[string]interface{}{
+		"description": "Generate a cap sync checklist",
+		"messages": []map[string]interface{}{
+			{"role": "user", "content": map[string]interface{}{"type": "text", "text": text}},
+		},
+	}, nil
+}
+
+// findProcess looks up a process by exact ID, or - if processID is empty -
+// the most recent failed process whose Name or Command mentions one of
+// keywords (case-insensitive).
+func (p *MCPPlugin) findProcess(processID string, keywords []string) *plugin.ProcessInfo {
+	processes := p.ctx.GetProcesses()
+
+	if processID != "" {
+		for i := range processes {
+			if processes[i].ID == processID {
+				return &processes[i]
+			}
+		}
+		return nil
+	}
+
+	for i := len(processes) - 1; i >= 0; i-- {
+		proc := processes[i]
+		if proc.Status != "failed" {
+			continue
+		}
+		for _, kw := range keywords {
+			if containsIgnoreCase(proc.Name, kw) || containsIgnoreCase(proc.Command, kw) {
+				return &processes[i]
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,200 @@
+package mcp
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+)
+
+// Resource describes one MCP resource: a URI an AI assistant can read on
+// demand (resources/read) instead of pulling the same content through a
+// tool call and spending its tool budget on it.
+type Resource struct {
+	URI         string `json:"uri"`
+	Name        string `json:"name"`
+	Description string `json:"description,omitempty"`
+	MimeType    string `json:"mimeType,omitempty"`
+}
+
+// projectResourceFiles are paths (relative to the project root) worth
+// exposing as resources when they exist - the files an AI assistant most
+// often needs to read to diagnose a Capacitor build.
+var projectResourceFiles = []struct {
+	path        string
+	name        string
+	description string
+	mimeType    string
+}{
+	{"capacitor.config.ts", "Capacitor config", "Capacitor project configuration (app ID, web dir, plugin config)", "text/typescript"},
+	{"package.json", "package.json", "Node package manifest: scripts, dependencies, Capacitor/Ionic versions", "application/json"},
+	{"ios/App/App/Info.plist", "iOS Info.plist", "iOS app's Info.plist: bundle identifier, permissions, URL schemes", "application/xml"},
+	{"android/app/build.gradle", "Android build.gradle", "Android app module's Gradle build file: SDK versions, dependencies, signing config", "text/x-gradle"},
+}
+
+// resourceSubscriptions tracks which MCP sessions (see http.go) asked for
+// notifications/resources/updated on a given URI, so a process log
+// changing only wakes the clients that actually subscribed to it.
+type resourceSubscriptions struct {
+	mu   sync.Mutex
+	subs map[string]map[string]struct{} // uri -> set of session IDs
+}
+
+func newResourceSubscriptions() *resourceSubscriptions {
+	return &resourceSubscriptions{subs: make(map[string]map[string]struct{})}
+}
+
+func (r *resourceSubscriptions) add(uri, sessionID string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.subs[uri] == nil {
+		r.subs[uri] = make(map[string]struct{})
+	}
+	r.subs[uri][sessionID] = struct{}{}
+}
+
+func (r *resourceSubscriptions) remove(uri, sessionID string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	delete(r.subs[uri], sessionID)
+}
+
+// subscribers returns the session IDs subscribed to uri.
+func (r *resourceSubscriptions) subscribers(uri string) []string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	ids := make([]string, 0, len(r.subs[uri]))
+	for id := range r.subs[uri] {
+		ids = append(ids, id)
+	}
+	return ids
+}
+
+// processLogURI returns the resources/ URI that identifies processID's
+// live log tail.
+func processLogURI(processID string) string {
+	return fmt.Sprintf("process://%s/log", processID)
+}
+
+// handleResourcesList returns every resource currently available: the
+// fixed set of project files that exist on disk, plus one live log
+// resource per running/finished process.
+func (p *MCPPlugin) handleResourcesList() map[string]interface{} {
+	var resources []Resource
+
+	if project := p.ctx.GetProject(); project != nil {
+		for _, f := range projectResourceFiles {
+			full := filepath.Join(project.RootDir, f.path)
+			if _, err := os.Stat(full); err != nil {
+				continue
+			}
+			resources = append(resources, Resource{
+				URI:         "file://" + full,
+				Name:        f.name,
+				Description: f.description,
+				MimeType:    f.mimeType,
+			})
+		}
+	}
+
+	for _, proc := range p.ctx.GetProcesses() {
+		resources = append(resources, Resource{
+			URI:         processLogURI(proc.ID),
+			Name:        fmt.Sprintf("%s log", proc.Name),
+			Description: fmt.Sprintf("Live log tail for %s (%s)", proc.Name, proc.Status),
+			MimeType:    "text/plain",
+		})
+	}
+
+	return map[string]interface{}{"resources": resources}
+}
+
+// handleResourcesRead resolves a resources/list URI back to its content:
+// the file on disk for a file:// URI, or the process's current logs for a
+// process:// URI.
+func (p *MCPPlugin) handleResourcesRead(params resourcesReadParams) (interface{}, *MCPError) {
+	if params.URI == "" {
+		return nil, &MCPError{Code: -32602, Message: "uri required"}
+	}
+
+	switch {
+	case strings.HasPrefix(params.URI, "file://"):
+		path := strings.TrimPrefix(params.URI, "file://")
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, &MCPError{Code: -32000, Message: fmt.Sprintf("failed to read %s: %v", path, err)}
+		}
+		return map[string]interface{}{
+			"contents": []map[string]interface{}{
+				{"uri": params.URI, "mimeType": mimeTypeForPath(path), "text": string(data)},
+			},
+		}, nil
+
+	case strings.HasPrefix(params.URI, "process://"):
+		processID := strings.TrimSuffix(strings.TrimPrefix(params.URI, "process://"), "/log")
+		logs := p.ctx.GetProcessLogs(processID)
+		if logs == nil {
+			return nil, &MCPError{Code: -32000, Message: "unknown process: " + processID}
+		}
+		return map[string]interface{}{
+			"contents": []map[string]interface{}{
+				{"uri": params.URI, "mimeType": "text/plain", "text": strings.Join(logs, "\n")},
+			},
+		}, nil
+
+	default:
+		return nil, &MCPError{Code: -32602, Message: "unsupported uri scheme: " + params.URI}
+	}
+}
+
+func mimeTypeForPath(path string) string {
+	for _, f := range projectResourceFiles {
+		if strings.HasSuffix(path, f.path) {
+			return f.mimeType
+		}
+	}
+	return "text/plain"
+}
+
+// handleResourcesSubscribe registers sessionID's interest in params.URI;
+// it'll receive a notifications/resources/updated push the next time
+// that resource changes (see notifyResourceUpdated).
+func (p *MCPPlugin) handleResourcesSubscribe(params resourcesReadParams, sessionID string) (interface{}, *MCPError) {
+	if params.URI == "" {
+		return nil, &MCPError{Code: -32602, Message: "uri required"}
+	}
+	if sessionID == "" {
+		return nil, &MCPError{Code: -32000, Message: "resources/subscribe requires the http transport (no session to notify)"}
+	}
+	p.resourceSubs.add(params.URI, sessionID)
+	return map[string]interface{}{}, nil
+}
+
+func (p *MCPPlugin) handleResourcesUnsubscribe(params resourcesReadParams, sessionID string) (interface{}, *MCPError) {
+	if params.URI == "" {
+		return nil, &MCPError{Code: -32602, Message: "uri required"}
+	}
+	p.resourceSubs.remove(params.URI, sessionID)
+	return map[string]interface{}{}, nil
+}
+
+// notifyResourceUpdated pushes notifications/resources/updated to every
+// session subscribed to uri.
+func (p *MCPPlugin) notifyResourceUpdated(uri string) {
+	ids := p.resourceSubs.subscribers(uri)
+	if len(ids) == 0 {
+		return
+	}
+	p.sessionsMu.Lock()
+	defer p.sessionsMu.Unlock()
+	for _, id := range ids {
+		if sess, ok := p.sessions[id]; ok {
+			sess.push("notifications/resources/updated", map[string]interface{}{"uri": uri})
+		}
+	}
+}
+
+type resourcesReadParams struct {
+	URI string `json:"uri"`
+}
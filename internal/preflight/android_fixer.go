@@ -0,0 +1,46 @@
+package preflight
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/icarus-itcs/lazycap/internal/download"
+)
+
+func init() {
+	RegisterFixer(androidPlatformToolsFixer{})
+}
+
+// androidPlatformToolsFixer installs the "platform-tools" package (which
+// provides adb) via sdkmanager, resolved from ANDROID_HOME/ANDROID_SDK_ROOT
+// the same way Capacitor's own Android tooling does.
+type androidPlatformToolsFixer struct{}
+
+func (androidPlatformToolsFixer) CanFix(result CheckResult) bool {
+	return result.Name == "Android ADB" && result.Status != StatusOK
+}
+
+func (androidPlatformToolsFixer) Fix(ctx context.Context, progress chan<- download.GenericProgress) error {
+	sdkmanager, ok := sdkmanagerPath()
+	if !ok {
+		return fmt.Errorf("sdkmanager not found - set ANDROID_HOME or ANDROID_SDK_ROOT to a valid Android SDK")
+	}
+	return runAndStream(ctx, progress, sdkmanager, "--install", "platform-tools")
+}
+
+func sdkmanagerPath() (string, bool) {
+	root := os.Getenv("ANDROID_HOME")
+	if root == "" {
+		root = os.Getenv("ANDROID_SDK_ROOT")
+	}
+	if root == "" {
+		return "", false
+	}
+	path := filepath.Join(root, "cmdline-tools", "latest", "bin", "sdkmanager")
+	if _, err := os.Stat(path); err != nil {
+		return "", false
+	}
+	return path, true
+}
@@ -0,0 +1,24 @@
+package preflight
+
+import (
+	"context"
+
+	"github.com/icarus-itcs/lazycap/internal/download"
+)
+
+func init() {
+	RegisterFixer(capacitorCLIFixer{})
+}
+
+// capacitorCLIFixer installs @capacitor/cli globally via npm, matching the
+// "npm install @capacitor/cli" instruction checkCapacitorCLI's own message
+// already points users at.
+type capacitorCLIFixer struct{}
+
+func (capacitorCLIFixer) CanFix(result CheckResult) bool {
+	return result.Name == "Capacitor CLI" && result.Status != StatusOK
+}
+
+func (capacitorCLIFixer) Fix(ctx context.Context, progress chan<- download.GenericProgress) error {
+	return runAndStream(ctx, progress, "npm", "install", "-g", "@capacitor/cli")
+}
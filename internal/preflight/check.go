@@ -13,10 +13,17 @@ import (
 
 // CheckResult represents the result of a single check
 type CheckResult struct {
-	Name    string
-	Status  Status
-	Message string
-	Path    string
+	Name         string
+	Status       Status
+	Message      string
+	Path         string
+	Remediations []Remediation
+
+	// Fix is set when a registered Fixer (see RegisterFixer) can
+	// automatically resolve this check - distinct from Remediations, which
+	// are canned shell commands offered for specific known failure modes.
+	// Nil if nothing's registered for this check, or it's already OK.
+	Fix *Remediation
 }
 
 // Status represents the status of a check
@@ -132,7 +139,7 @@ func checkTool(tool RequiredTool) CheckResult {
 			result.Status = StatusWarning
 			result.Message = "Not found - optional"
 		}
-		return result
+		return attachFix(result)
 	}
 
 	result.Path = path
@@ -143,7 +150,10 @@ func checkTool(tool RequiredTool) CheckResult {
 		if err := cmd.Run(); err != nil {
 			result.Status = StatusWarning
 			result.Message = fmt.Sprintf("Found but may not work: %v", err)
-			return result
+			if tool.Name == "CocoaPods" {
+				result.Remediations = cocoaPodsRemediations()
+			}
+			return attachFix(result)
 		}
 	}
 
@@ -156,7 +166,7 @@ func checkTool(tool RequiredTool) CheckResult {
 	}
 	result.Status = StatusOK
 
-	return result
+	return attachFix(result)
 }
 
 func checkCapacitorCLI() CheckResult {
@@ -170,15 +180,43 @@ func checkCapacitorCLI() CheckResult {
 	if err != nil {
 		result.Status = StatusError
 		result.Message = "Not installed - run: npm install @capacitor/cli"
-		return result
+		return attachFix(result)
 	}
 
 	result.Status = StatusOK
 	result.Message = "v" + strings.TrimSpace(string(output))
 	result.Path = "npx cap"
+	return attachFix(result)
+}
+
+// attachFix sets result.Fix to the first registered Fixer willing to
+// CanFix it, for the preflight panel's "press f to fix" prompt - left nil
+// if nothing's registered for this check.
+func attachFix(result CheckResult) CheckResult {
+	if _, ok := FindFixer(result); ok {
+		result.Fix = &Remediation{
+			Name:        result.Name + " (automated fix)",
+			Description: "Run the built-in fixer for this check and re-check it",
+		}
+	}
 	return result
 }
 
+// CheckByName re-runs just the named check (one of requiredTools, or the
+// Capacitor CLI check) - used by RunFix to refresh a single check after
+// its Fixer runs, instead of a full Run.
+func CheckByName(name string) (CheckResult, bool) {
+	if name == "Capacitor CLI" {
+		return checkCapacitorCLI(), true
+	}
+	for _, tool := range requiredTools {
+		if tool.Name == name {
+			return checkTool(tool), true
+		}
+	}
+	return CheckResult{}, false
+}
+
 func getToolVersion(cmd string) string {
 	var versionArgs []string
 
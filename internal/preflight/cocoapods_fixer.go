@@ -0,0 +1,25 @@
+package preflight
+
+import (
+	"context"
+
+	"github.com/icarus-itcs/lazycap/internal/download"
+)
+
+func init() {
+	RegisterFixer(cocoaPodsFixer{})
+}
+
+// cocoaPodsFixer installs or upgrades CocoaPods itself via gem, the same
+// way its own install docs recommend - distinct from cocoaPodsRemediations,
+// which fixes project-level Podfile/spec-repo problems once `pod` already
+// works.
+type cocoaPodsFixer struct{}
+
+func (cocoaPodsFixer) CanFix(result CheckResult) bool {
+	return result.Name == "CocoaPods" && result.Status != StatusOK
+}
+
+func (cocoaPodsFixer) Fix(ctx context.Context, progress chan<- download.GenericProgress) error {
+	return runAndStream(ctx, progress, "gem", "install", "cocoapods")
+}
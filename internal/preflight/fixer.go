@@ -0,0 +1,105 @@
+package preflight
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"os/exec"
+	"strings"
+
+	"github.com/icarus-itcs/lazycap/internal/download"
+)
+
+// Fixer is a pluggable remediation for a failing or degraded preflight
+// check - one per tool, registered from that tool's own file (see
+// node_fixer.go, cocoapods_fixer.go, android_fixer.go, capacitor_fixer.go)
+// instead of a giant switch here.
+type Fixer interface {
+	// CanFix reports whether this Fixer knows how to resolve result.
+	CanFix(result CheckResult) bool
+
+	// Fix runs the remediation, reporting progress the same way
+	// internal/download.Pool.Get does (Message only - fixers don't have a
+	// meaningful Current/Total).
+	Fix(ctx context.Context, progress chan<- download.GenericProgress) error
+}
+
+var fixers []Fixer
+
+// RegisterFixer adds f to the package-level registry FindFixer and RunFix
+// search, in registration order. Built-in fixers register themselves from
+// an init() in their own file.
+func RegisterFixer(f Fixer) {
+	fixers = append(fixers, f)
+}
+
+// FindFixer returns the first registered Fixer willing to CanFix result.
+func FindFixer(result CheckResult) (Fixer, bool) {
+	for _, f := range fixers {
+		if f.CanFix(result) {
+			return f, true
+		}
+	}
+	return nil, false
+}
+
+// RunFix re-runs checkName (see CheckByName) and, if a registered Fixer
+// can handle its current state, runs that fixer - returning the freshly
+// re-checked CheckResult once the fix has finished.
+func RunFix(ctx context.Context, checkName string, progress chan<- download.GenericProgress) (CheckResult, error) {
+	result, ok := CheckByName(checkName)
+	if !ok {
+		return CheckResult{}, fmt.Errorf("unknown preflight check %q", checkName)
+	}
+
+	fixer, ok := FindFixer(result)
+	if !ok {
+		return CheckResult{}, fmt.Errorf("no fixer available for %q", checkName)
+	}
+
+	if err := fixer.Fix(ctx, progress); err != nil {
+		return CheckResult{}, err
+	}
+
+	fixed, _ := CheckByName(checkName)
+	return fixed, nil
+}
+
+// runAndStream runs name with args to completion, forwarding each line of
+// its combined stdout/stderr to progress as it's produced (dropping lines
+// a slow reader hasn't kept up with, same as download.Pool's broadcast),
+// and returns a descriptive error if it exits non-zero.
+func runAndStream(ctx context.Context, progress chan<- download.GenericProgress, name string, args ...string) error {
+	cmd := exec.CommandContext(ctx, name, args...)
+	pr, pw := io.Pipe()
+	cmd.Stdout = pw
+	cmd.Stderr = pw
+
+	scanDone := make(chan struct{})
+	go func() {
+		defer close(scanDone)
+		scanner := bufio.NewScanner(pr)
+		for scanner.Scan() {
+			if progress == nil {
+				continue
+			}
+			select {
+			case progress <- download.GenericProgress{Message: scanner.Text()}:
+			default:
+			}
+		}
+	}()
+
+	err := cmd.Start()
+	if err == nil {
+		err = cmd.Wait()
+	}
+	pw.Close()
+	<-scanDone
+
+	if err != nil {
+		return fmt.Errorf("%s %s: %w", name, strings.Join(args, " "), err)
+	}
+	return nil
+}
@@ -0,0 +1,47 @@
+package preflight
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+
+	"github.com/icarus-itcs/lazycap/internal/download"
+)
+
+func init() {
+	RegisterFixer(nodeFixer{})
+}
+
+// nodeFixer installs Node.js via whichever version manager is already on
+// the machine - nvm if ~/.nvm/nvm.sh exists, else asdf if it's on PATH -
+// rather than assuming a system package manager the user may not want
+// touching their shell's Node setup.
+type nodeFixer struct{}
+
+func (nodeFixer) CanFix(result CheckResult) bool {
+	return result.Name == "Node.js" && result.Status != StatusOK
+}
+
+func (nodeFixer) Fix(ctx context.Context, progress chan<- download.GenericProgress) error {
+	if nvmScript, ok := nvmScriptPath(); ok {
+		return runAndStream(ctx, progress, "bash", "-lc", "source "+nvmScript+" && nvm install --lts")
+	}
+	if _, err := exec.LookPath("asdf"); err == nil {
+		return runAndStream(ctx, progress, "asdf", "install", "nodejs", "latest")
+	}
+	return fmt.Errorf("no supported Node.js version manager found (looked for nvm and asdf)")
+}
+
+func nvmScriptPath() (string, bool) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", false
+	}
+	script := filepath.Join(home, ".nvm", "nvm.sh")
+	if _, err := os.Stat(script); err != nil {
+		return "", false
+	}
+	return script, true
+}
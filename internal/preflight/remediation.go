@@ -0,0 +1,94 @@
+package preflight
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// Remediation is a one-shot fix offered for a failing or warning preflight
+// check, runnable from the preflight panel as a Process tab. Most
+// remediations are a plain shell command (Command/Args); ones that need to
+// edit project files directly (like bumping a deployment target) set Run
+// instead.
+type Remediation struct {
+	Name            string
+	Description     string
+	Command         string
+	Args            []string
+	RequiresConfirm bool
+	Run             func(baseDir string) (string, error)
+}
+
+// cocoaPodsRemediations returns the canned fixes offered for a failing or
+// degraded CocoaPods check: a spec-repo refresh (fixes "None of your spec
+// sources contain a spec satisfying the dependency" errors), a full
+// reinstall (fixes a corrupted Pods/ directory), and a deployment-target
+// bump (fixes specs that require a newer minimum iOS version).
+func cocoaPodsRemediations() []Remediation {
+	return []Remediation{
+		{
+			Name:        "Update CocoaPods spec repo",
+			Description: "pod repo update — fixes \"None of your spec sources contain a spec satisfying the dependency\" errors",
+			Command:     "pod",
+			Args:        []string{"repo", "update"},
+		},
+		{
+			Name:            "Reinstall Pods",
+			Description:     "rm -rf ios/App/Pods && pod install — fixes a corrupted Pods/ directory",
+			Command:         "sh",
+			Args:            []string{"-c", "rm -rf ios/App/Pods ios/App/Podfile.lock && cd ios/App && pod install"},
+			RequiresConfirm: true,
+		},
+		{
+			Name:        "Bump iOS deployment target",
+			Description: "Raise the Podfile's platform :ios version to satisfy specs that require a newer minimum",
+			Run:         BumpIOSDeploymentTarget,
+		},
+	}
+}
+
+var podfilePlatformRegex = regexp.MustCompile(`(platform\s+:ios,\s*)'([\d.]+)'`)
+
+// BumpIOSDeploymentTarget raises the `platform :ios, 'X.Y'` line in
+// ios/App/Podfile by one minor version, returning a human-readable summary
+// of the change. It's a Remediation.Run implementation.
+func BumpIOSDeploymentTarget(baseDir string) (string, error) {
+	path := filepath.Join(baseDir, "ios", "App", "Podfile")
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return "", fmt.Errorf("failed to read %s: %w", path, err)
+	}
+
+	match := podfilePlatformRegex.FindStringSubmatch(string(data))
+	if match == nil {
+		return "", fmt.Errorf("no `platform :ios, 'X.Y'` line found in %s", path)
+	}
+
+	current := match[2]
+	next := bumpMinorVersion(current)
+	updated := strings.Replace(string(data), match[0], match[1]+"'"+next+"'", 1)
+
+	if err := os.WriteFile(path, []byte(updated), 0o644); err != nil {
+		return "", fmt.Errorf("failed to write %s: %w", path, err)
+	}
+
+	return fmt.Sprintf("Bumped iOS deployment target %s -> %s in %s", current, next, path), nil
+}
+
+func bumpMinorVersion(v string) string {
+	parts := strings.SplitN(v, ".", 2)
+	major, err := strconv.Atoi(parts[0])
+	if err != nil {
+		return v
+	}
+	minor := 0
+	if len(parts) > 1 {
+		minor, _ = strconv.Atoi(parts[1])
+	}
+	minor++
+	return fmt.Sprintf("%d.%d", major, minor)
+}
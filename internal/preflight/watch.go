@@ -0,0 +1,100 @@
+package preflight
+
+import (
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+)
+
+// watchedNames are the plain filenames discoverProjects itself already
+// scans for that, on their own, indicate a project's shape changed.
+var watchedNames = []string{"package.json", "firebase.json"}
+
+// watchedCapacitorConfigs are discoverProjects' capacitor.config.* names.
+var watchedCapacitorConfigs = []string{"capacitor.config.ts", "capacitor.config.js", "capacitor.config.json"}
+
+// Watcher is a live fsnotify watch started by WatchAt.
+type Watcher struct {
+	fsw    *fsnotify.Watcher
+	stopCh chan struct{}
+}
+
+// WatchAt watches baseDir for changes to capacitor.config.*, package.json,
+// and firebase.json, returning a Watcher plus a channel that fires once
+// per batch of changes - bursts from a single save are collapsed into one
+// fire via a 500ms debounce, the same way settings.Watch debounces
+// config.yml saves. This package has no event bus of its own to emit
+// through, so turning a fire into a user-facing event (EventPreflightChanged)
+// is left to the caller - see AppContext.WatchPreflight.
+func WatchAt(baseDir string) (*Watcher, <-chan struct{}, error) {
+	fsw, err := fsnotify.NewWatcher()
+	if err != nil {
+		return nil, nil, err
+	}
+	if err := fsw.Add(baseDir); err != nil {
+		fsw.Close()
+		return nil, nil, err
+	}
+
+	changed := make(chan struct{}, 1)
+	stopCh := make(chan struct{})
+	w := &Watcher{fsw: fsw, stopCh: stopCh}
+
+	go func() {
+		var mu sync.Mutex
+		var debounce *time.Timer
+		fire := func() {
+			select {
+			case changed <- struct{}{}:
+			default:
+			}
+		}
+		for {
+			select {
+			case event, ok := <-fsw.Events:
+				if !ok {
+					return
+				}
+				if !isWatchedPath(event.Name) {
+					continue
+				}
+				mu.Lock()
+				if debounce != nil {
+					debounce.Stop()
+				}
+				debounce = time.AfterFunc(500*time.Millisecond, fire)
+				mu.Unlock()
+			case <-fsw.Errors:
+				// A failed stat on a transient rename-on-save temp file
+				// isn't worth surfacing.
+			case <-stopCh:
+				fsw.Close()
+				return
+			}
+		}
+	}()
+
+	return w, changed, nil
+}
+
+// Stop tears down the underlying fsnotify watcher and its goroutine.
+func (w *Watcher) Stop() {
+	close(w.stopCh)
+}
+
+func isWatchedPath(path string) bool {
+	base := filepath.Base(path)
+	for _, name := range watchedNames {
+		if base == name {
+			return true
+		}
+	}
+	for _, cfg := range watchedCapacitorConfigs {
+		if base == cfg {
+			return true
+		}
+	}
+	return false
+}
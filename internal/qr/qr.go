@@ -0,0 +1,396 @@
+// Package qr is a small, dependency-free QR code encoder. It only supports
+// what lazycap needs: encoding a short URL (byte mode, error-correction
+// level L, versions 1-5) into a boolean module matrix that the live-reload
+// overlay renders as block characters. It does not aim to be a general
+// purpose QR library - no kanji/alphanumeric modes, no versions beyond 5,
+// and a fixed mask pattern rather than penalty-scored mask selection.
+package qr
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Code is an encoded QR symbol: a square matrix of modules, true = dark.
+type Code struct {
+	Size    int
+	Modules [][]bool
+}
+
+// maxDataCodewords is the EC-level-L single-block data capacity for
+// versions 1-5, indexed by version-1. Versions beyond 5 split into
+// multiple Reed-Solomon blocks, which this encoder doesn't implement.
+var maxDataCodewords = []int{19, 34, 55, 80, 108}
+
+// ecCodewords is the number of EC-level-L error correction codewords per
+// block for versions 1-5.
+var ecCodewords = []int{7, 10, 15, 20, 26}
+
+// alignmentPositions gives the center coordinates of the (single)
+// alignment pattern for versions 2-5; version 1 has none.
+var alignmentPositions = map[int]int{2: 18, 3: 22, 4: 26, 5: 30}
+
+// Encode builds a QR code for data using the smallest version (1-5) that
+// fits it in byte mode at EC level L. Returns an error if data is too long
+// for version 5 at that level.
+func Encode(data string) (*Code, error) {
+	raw := []byte(data)
+
+	version := 0
+	for v := 1; v <= 5; v++ {
+		if capacity(v) >= len(raw) {
+			version = v
+			break
+		}
+	}
+	if version == 0 {
+		return nil, fmt.Errorf("qr: %d bytes is too long to encode (max %d)", len(raw), capacity(5))
+	}
+
+	dataCodewords := buildDataCodewords(raw, version)
+	ecwLen := ecCodewords[version-1]
+	ecw := reedSolomonECC(dataCodewords, ecwLen)
+
+	final := append(append([]byte{}, dataCodewords...), ecw...)
+
+	size := 4*version + 17
+	c := &Code{Size: size, Modules: make([][]bool, size)}
+	for i := range c.Modules {
+		c.Modules[i] = make([]bool, size)
+	}
+	reserved := make([][]bool, size)
+	for i := range reserved {
+		reserved[i] = make([]bool, size)
+	}
+
+	placeFunctionPatterns(c, reserved, version)
+	placeData(c, reserved, final)
+	placeFormatInfo(c, reserved)
+
+	return c, nil
+}
+
+// Render draws the code as half-block Unicode characters (two module rows
+// per text line), with a 2-module quiet zone, suitable for printing
+// straight into a terminal overlay.
+func (c *Code) Render() string {
+	const quiet = 2
+	size := c.Size + quiet*2
+
+	at := func(row, col int) bool {
+		r, cc := row-quiet, col-quiet
+		if r < 0 || r >= c.Size || cc < 0 || cc >= c.Size {
+			return false
+		}
+		return c.Modules[r][cc]
+	}
+
+	var sb strings.Builder
+	for row := 0; row < size; row += 2 {
+		for col := 0; col < size; col++ {
+			top, bottom := at(row, col), at(row+1, col)
+			switch {
+			case top && bottom:
+				sb.WriteRune('█')
+			case top:
+				sb.WriteRune('▀')
+			case bottom:
+				sb.WriteRune('▄')
+			default:
+				sb.WriteRune(' ')
+			}
+		}
+		sb.WriteByte('\n')
+	}
+	return sb.String()
+}
+
+// capacity returns the usable byte-mode capacity (accounting for the mode
+// indicator, character count indicator, and terminator) for a version at
+// EC level L.
+func capacity(version int) int {
+	// 4 bits mode + 8 bits character count (versions 1-9) + 4 bit terminator,
+	// rounded up to a whole byte.
+	overheadBits := 4 + 8 + 4
+	dataBits := maxDataCodewords[version-1]*8 - overheadBits
+	if dataBits < 0 {
+		return 0
+	}
+	return dataBits / 8
+}
+
+// buildDataCodewords packs the byte-mode header, payload, terminator, and
+// padding into a full set of data codewords for the given version.
+func buildDataCodewords(data []byte, version int) []byte {
+	bits := newBitWriter()
+	bits.write(0b0100, 4) // byte mode indicator
+	bits.write(len(data), 8)
+	for _, b := range data {
+		bits.write(int(b), 8)
+	}
+
+	totalBits := maxDataCodewords[version-1] * 8
+	// Terminator, up to 4 bits, only as many as fit.
+	term := 4
+	if totalBits-bits.len() < term {
+		term = totalBits - bits.len()
+	}
+	if term > 0 {
+		bits.write(0, term)
+	}
+	bits.padToByte()
+
+	pad := []byte{0xEC, 0x11}
+	for i := 0; bits.len() < totalBits; i++ {
+		bits.write(int(pad[i%2]), 8)
+	}
+
+	return bits.bytes()
+}
+
+// --- bit writer ---
+
+type bitWriter struct {
+	bitsWritten int
+	buf         []byte
+}
+
+func newBitWriter() *bitWriter { return &bitWriter{} }
+
+func (w *bitWriter) write(value, numBits int) {
+	for i := numBits - 1; i >= 0; i-- {
+		bit := (value >> uint(i)) & 1
+		byteIdx := w.bitsWritten / 8
+		for len(w.buf) <= byteIdx {
+			w.buf = append(w.buf, 0)
+		}
+		if bit == 1 {
+			w.buf[byteIdx] |= 1 << uint(7-(w.bitsWritten%8))
+		}
+		w.bitsWritten++
+	}
+}
+
+func (w *bitWriter) len() int { return w.bitsWritten }
+
+func (w *bitWriter) padToByte() {
+	for w.bitsWritten%8 != 0 {
+		w.write(0, 1)
+	}
+}
+
+func (w *bitWriter) bytes() []byte { return w.buf }
+
+// --- GF(256) Reed-Solomon error correction ---
+
+var gfExp [512]int
+var gfLog [256]int
+
+func init() {
+	x := 1
+	for i := 0; i < 255; i++ {
+		gfExp[i] = x
+		gfLog[x] = i
+		x <<= 1
+		if x&0x100 != 0 {
+			x ^= 0x11D
+		}
+	}
+	for i := 255; i < 512; i++ {
+		gfExp[i] = gfExp[i-255]
+	}
+}
+
+func gfMul(a, b int) int {
+	if a == 0 || b == 0 {
+		return 0
+	}
+	return gfExp[gfLog[a]+gfLog[b]]
+}
+
+// rsGeneratorPoly computes the Reed-Solomon generator polynomial of the
+// given degree (highest-order coefficient first).
+func rsGeneratorPoly(degree int) []int {
+	poly := []int{1}
+	for i := 0; i < degree; i++ {
+		next := make([]int, len(poly)+1)
+		for j, coef := range poly {
+			next[j] ^= gfMul(coef, gfExp[i])
+			next[j+1] ^= coef
+		}
+		poly = next
+	}
+	return poly
+}
+
+// reedSolomonECC computes the error correction codewords for data using
+// polynomial division by the generator polynomial, in GF(256).
+func reedSolomonECC(data []byte, ecLen int) []byte {
+	gen := rsGeneratorPoly(ecLen)
+
+	remainder := make([]int, len(data)+ecLen)
+	for i, b := range data {
+		remainder[i] = int(b)
+	}
+
+	for i := 0; i < len(data); i++ {
+		coef := remainder[i]
+		if coef == 0 {
+			continue
+		}
+		for j, g := range gen {
+			remainder[i+j] ^= gfMul(g, coef)
+		}
+	}
+
+	out := make([]byte, ecLen)
+	for i := 0; i < ecLen; i++ {
+		out[i] = byte(remainder[len(data)+i])
+	}
+	return out
+}
+
+// --- module placement ---
+
+func placeFunctionPatterns(c *Code, reserved [][]bool, version int) {
+	size := c.Size
+
+	placeFinder := func(top, left int) {
+		for r := -1; r <= 7; r++ {
+			for col := -1; col <= 7; col++ {
+				rr, cc := top+r, left+col
+				if rr < 0 || rr >= size || cc < 0 || cc >= size {
+					continue
+				}
+				reserved[rr][cc] = true
+				dark := r >= 0 && r <= 6 && col >= 0 && col <= 6 &&
+					(r == 0 || r == 6 || col == 0 || col == 6 || (r >= 2 && r <= 4 && col >= 2 && col <= 4))
+				c.Modules[rr][cc] = dark
+			}
+		}
+	}
+	placeFinder(0, 0)
+	placeFinder(0, size-7)
+	placeFinder(size-7, 0)
+
+	// Timing patterns
+	for i := 8; i < size-8; i++ {
+		dark := i%2 == 0
+		c.Modules[6][i] = dark
+		reserved[6][i] = true
+		c.Modules[i][6] = dark
+		reserved[i][6] = true
+	}
+
+	// Alignment pattern (versions 2-5 have exactly one, away from the finders)
+	if center, ok := alignmentPositions[version]; ok {
+		for r := -2; r <= 2; r++ {
+			for col := -2; col <= 2; col++ {
+				rr, cc := center+r, center+col
+				reserved[rr][cc] = true
+				dark := r == -2 || r == 2 || col == -2 || col == 2 || (r == 0 && col == 0)
+				c.Modules[rr][cc] = dark
+			}
+		}
+	}
+
+	// Dark module, fixed per spec at (4*version+9, 8).
+	c.Modules[4*version+9][8] = true
+	reserved[4*version+9][8] = true
+
+	// Reserve the format information strips (actual bits written later).
+	for i := 0; i <= 8; i++ {
+		reserved[8][i] = true
+		reserved[i][8] = true
+	}
+	for i := 0; i < 8; i++ {
+		reserved[8][size-1-i] = true
+		reserved[size-1-i][8] = true
+	}
+}
+
+// placeData zigzags the codewords (MSB first) up the matrix in
+// two-column passes right to left, skipping the timing column and any
+// reserved function modules, per the QR spec.
+func placeData(c *Code, reserved [][]bool, data []byte) {
+	bitIdx := 0
+	totalBits := len(data) * 8
+	nextBit := func() bool {
+		if bitIdx >= totalBits {
+			return false
+		}
+		b := data[bitIdx/8]
+		bit := (b >> uint(7-(bitIdx%8))) & 1
+		bitIdx++
+		return bit == 1
+	}
+
+	size := c.Size
+	col := size - 1
+	upward := true
+	for col > 0 {
+		if col == 6 {
+			col--
+		}
+		for i := 0; i < size; i++ {
+			row := i
+			if upward {
+				row = size - 1 - i
+			}
+			for _, cc := range []int{col, col - 1} {
+				if reserved[row][cc] {
+					continue
+				}
+				if bitIdx < totalBits {
+					c.Modules[row][cc] = nextBit()
+				}
+				// mask pattern 0: (row+col) % 2 == 0 flips the module
+				if (row+cc)%2 == 0 {
+					c.Modules[row][cc] = !c.Modules[row][cc]
+				}
+			}
+		}
+		upward = !upward
+		col -= 2
+	}
+}
+
+// placeFormatInfo writes the two copies of the 15-bit format information
+// (EC level L, mask pattern 0) around the finder patterns.
+func placeFormatInfo(c *Code, reserved [][]bool) {
+	const ecLevelL = 0b01
+	data := ecLevelL<<3 | 0 // mask pattern 0
+
+	// BCH(15,5) with generator 0x537, then XOR the fixed mask.
+	rem := data << 10
+	for i := 14; i >= 10; i-- {
+		if rem&(1<<uint(i)) != 0 {
+			rem ^= 0x537 << uint(i-10)
+		}
+	}
+	format := (data<<10 | rem) ^ 0b101010000010010
+
+	bit := func(i int) bool { return format&(1<<uint(i)) != 0 }
+
+	size := c.Size
+	// Around the top-left finder.
+	for i := 0; i <= 5; i++ {
+		c.Modules[8][i] = bit(i)
+	}
+	c.Modules[8][7] = bit(6)
+	c.Modules[8][8] = bit(7)
+	c.Modules[7][8] = bit(8)
+	for i := 9; i < 15; i++ {
+		c.Modules[14-i][8] = bit(i)
+	}
+
+	// Split copy along the top-right and bottom-left finders.
+	for i := 0; i < 8; i++ {
+		c.Modules[size-1-i][8] = bit(i)
+	}
+	for i := 8; i < 15; i++ {
+		c.Modules[8][size-15+i] = bit(i)
+	}
+
+	_ = reserved
+}
@@ -0,0 +1,117 @@
+package session
+
+import "sync"
+
+// LiveProcess is an in-memory handle to a process one Model actually
+// started, broadcasting its output to every other Model that attaches to
+// the same key while it's still running - unlike the on-disk Session
+// record above, which only supports reattaching after the fact by tailing
+// a log file once the original process (and lazycap invocation) is gone.
+// This is what lets two concurrent `lazycap serve` viewers of the same
+// project see one running build instead of each starting their own.
+type LiveProcess struct {
+	mu        sync.Mutex
+	listeners map[int]chan string
+	nextID    int
+	done      bool
+}
+
+func newLiveProcess() *LiveProcess {
+	return &LiveProcess{listeners: make(map[int]chan string)}
+}
+
+// Subscribe registers a new listener and returns its channel. The channel
+// is closed once the process finishes (via Finish) - including
+// immediately, if it already had by the time Subscribe was called - the
+// same "closed channel means done" convention the owning Model's own
+// output channel already uses, so a follower can be driven by the exact
+// same waitForOutput/processOutputMsg/processFinishedMsg plumbing as a
+// process it started itself.
+func (lp *LiveProcess) Subscribe() chan string {
+	lp.mu.Lock()
+	defer lp.mu.Unlock()
+	ch := make(chan string, 100)
+	if lp.done {
+		close(ch)
+		return ch
+	}
+	id := lp.nextID
+	lp.nextID++
+	lp.listeners[id] = ch
+	return ch
+}
+
+// Broadcast fans line out to every current subscriber, dropping it for any
+// listener whose buffer is full rather than blocking the owner's own
+// output pump.
+func (lp *LiveProcess) Broadcast(line string) {
+	lp.mu.Lock()
+	defer lp.mu.Unlock()
+	for _, ch := range lp.listeners {
+		select {
+		case ch <- line:
+		default:
+		}
+	}
+}
+
+// Finish marks lp done and closes every current subscriber's channel.
+func (lp *LiveProcess) Finish() {
+	lp.mu.Lock()
+	defer lp.mu.Unlock()
+	lp.done = true
+	for id, ch := range lp.listeners {
+		close(ch)
+		delete(lp.listeners, id)
+	}
+}
+
+// Registry tracks the LiveProcesses currently running, keyed by whatever
+// the caller considers "the same build" (e.g. project name + action, see
+// ui.processAction) - a second session attaching to an existing key
+// observes the one already running instead of starting a duplicate.
+type Registry struct {
+	mu    sync.Mutex
+	procs map[string]*LiveProcess
+}
+
+// sharedRegistry is one per lazycap binary: `lazycap serve` handles every
+// SSH session as a goroutine in the same process, so they all share it
+// without needing to be wired through explicitly.
+var sharedRegistry = &Registry{procs: make(map[string]*LiveProcess)}
+
+// Shared returns the process-wide Registry.
+func Shared() *Registry { return sharedRegistry }
+
+// Attach returns the LiveProcess already registered under key, if one is
+// still running.
+func (r *Registry) Attach(key string) (*LiveProcess, bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	lp, ok := r.procs[key]
+	return lp, ok
+}
+
+// Register starts tracking a new LiveProcess under key, replacing any
+// prior entry (which, if still present, must already be Finished - a key
+// is only ever re-registered once Release has cleared the old one).
+func (r *Registry) Register(key string) *LiveProcess {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	lp := newLiveProcess()
+	r.procs[key] = lp
+	return lp
+}
+
+// Release stops tracking key and finishes lp, provided lp is still the
+// entry registered under key (a newer Register call may have already
+// replaced it, e.g. if the owning session restarted the build before this
+// one's cleanup ran).
+func (r *Registry) Release(key string, lp *LiveProcess) {
+	r.mu.Lock()
+	if r.procs[key] == lp {
+		delete(r.procs, key)
+	}
+	r.mu.Unlock()
+	lp.Finish()
+}
@@ -0,0 +1,124 @@
+// Package session persists metadata about long-running lazycap processes
+// (run/sync/build/mirrored-run tabs) to disk, so that a process started in
+// one lazycap invocation can be reattached - or at least reviewed after the
+// fact - from a later invocation against the same project.
+//
+// Each project gets its own directory under the user cache dir holding a
+// sessions.json index plus one log file per process ID. Reattachment is
+// best-effort: once lazycap exits, the new process can no longer read the
+// old one's stdout/stderr pipes directly, so a "live" session is recognized
+// by its PID still being alive and its log file is tailed for new lines
+// instead.
+package session
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"syscall"
+	"time"
+)
+
+// Status mirrors the subset of ui.ProcessStatus that's meaningful once
+// lazycap has restarted: a session is either still running, or it finished
+// with one of the terminal outcomes before we got a chance to observe it.
+type Status string
+
+const (
+	StatusRunning   Status = "running"
+	StatusSuccess   Status = "success"
+	StatusFailed    Status = "failed"
+	StatusCancelled Status = "cancelled"
+)
+
+// Session is one process's persisted record.
+type Session struct {
+	ID        string    `json:"id"`
+	Name      string    `json:"name"`
+	Command   string    `json:"command"`
+	PID       int       `json:"pid"`
+	Status    Status    `json:"status"`
+	LogPath   string    `json:"logPath"`
+	StartTime time.Time `json:"startTime"`
+	EndTime   time.Time `json:"endTime,omitempty"`
+}
+
+// Dir returns the directory sessions for a project named projectName are
+// stored under, creating it if necessary. Callers without a project name
+// (e.g. no project loaded yet) should pass "", which maps to a shared
+// "default" directory.
+func Dir(projectName string) (string, error) {
+	base, err := os.UserCacheDir()
+	if err != nil {
+		return "", err
+	}
+	name := filepath.Clean(projectName)
+	if name == "" || name == "." || name == string(filepath.Separator) {
+		name = "default"
+	}
+	dir := filepath.Join(base, "lazycap", "sessions", name)
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return "", err
+	}
+	return dir, nil
+}
+
+func indexPath(dir string) string {
+	return filepath.Join(dir, "sessions.json")
+}
+
+// Load reads the session index, returning an empty slice (not an error) if
+// it doesn't exist yet.
+func Load(dir string) ([]Session, error) {
+	data, err := os.ReadFile(indexPath(dir))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	var sessions []Session
+	if err := json.Unmarshal(data, &sessions); err != nil {
+		return nil, err
+	}
+	return sessions, nil
+}
+
+// SaveAll overwrites the session index with sessions.
+func SaveAll(dir string, sessions []Session) error {
+	data, err := json.MarshalIndent(sessions, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(indexPath(dir), data, 0o644)
+}
+
+// Upsert adds s to the index, or replaces the existing entry with the same
+// ID, and saves the result.
+func Upsert(dir string, s Session) error {
+	sessions, err := Load(dir)
+	if err != nil {
+		return err
+	}
+	for i := range sessions {
+		if sessions[i].ID == s.ID {
+			sessions[i] = s
+			return SaveAll(dir, sessions)
+		}
+	}
+	return SaveAll(dir, append(sessions, s))
+}
+
+// IsAlive reports whether pid names a process we can signal - i.e. it's
+// still running (and ours to own), not just a reused PID we have no relation
+// to. Signal 0 performs no action beyond the existence/permission check.
+func IsAlive(pid int) bool {
+	if pid <= 0 {
+		return false
+	}
+	proc, err := os.FindProcess(pid)
+	if err != nil {
+		return false
+	}
+	return proc.Signal(syscall.Signal(0)) == nil
+}
@@ -0,0 +1,66 @@
+package settings
+
+import "fmt"
+
+// AppConfig bundles build metadata with the resolved config locations and
+// loaded Settings, so callers in cmd/lazycap don't have to thread each of
+// those through separately.
+type AppConfig struct {
+	Name        string
+	Version     string
+	Commit      string
+	Date        string
+	BuildSource string
+	Debug       bool
+
+	ConfigDir  string
+	ConfigPath string
+	StatePath  string
+	LogDir     string
+
+	Settings *Settings
+}
+
+// NewAppConfig resolves the config directory (honoring LAZYCAP_CONFIG_DIR),
+// loads config.yml from it, and returns an AppConfig carrying both the
+// build metadata and the loaded settings. It's the lazycap analogue of the
+// lazygit family's config.NewAppConfig.
+func NewAppConfig(name, version, commit, date, buildSource string, debug bool) (*AppConfig, error) {
+	configDir, err := ConfigDir()
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve config directory: %w", err)
+	}
+	configPath, err := ConfigPath()
+	if err != nil {
+		return nil, err
+	}
+	statePath, err := StatePath()
+	if err != nil {
+		return nil, err
+	}
+	logDir, err := LogDir()
+	if err != nil {
+		return nil, err
+	}
+
+	loaded, err := Load()
+	if err != nil {
+		return nil, fmt.Errorf("failed to load settings: %w", err)
+	}
+
+	return &AppConfig{
+		Name:        name,
+		Version:     version,
+		Commit:      commit,
+		Date:        date,
+		BuildSource: buildSource,
+		Debug:       debug,
+
+		ConfigDir:  configDir,
+		ConfigPath: configPath,
+		StatePath:  statePath,
+		LogDir:     logDir,
+
+		Settings: loaded,
+	}, nil
+}
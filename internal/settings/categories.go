@@ -0,0 +1,115 @@
+package settings
+
+import (
+	"path/filepath"
+
+	"github.com/icarus-itcs/lazycap/internal/theme"
+)
+
+// Setting describes one user-configurable value for display in the
+// settings panel.
+type Setting struct {
+	Key         string
+	Name        string
+	Description string
+	Type        string // "bool", "string", "int", or "choice"
+	Default     interface{}
+	Choices     []string
+}
+
+// Category groups related settings under a tab in the settings panel.
+type Category struct {
+	Icon     string
+	Name     string
+	Settings []Setting
+}
+
+// GetCategories returns the static list of setting categories shown in the
+// settings panel, in display order.
+func GetCategories() []Category {
+	return []Category{
+		{
+			Icon: "▶",
+			Name: "Run",
+			Settings: []Setting{
+				{Key: "liveReloadDefault", Name: "Live Reload", Description: "Enable live reload by default when running", Type: "bool", Default: false},
+			},
+		},
+		{
+			Icon: "🌍",
+			Name: "General",
+			Settings: []Setting{
+				{Key: "language", Name: "Language", Description: "UI language (BCP-47-ish code, e.g. \"ja\", \"de\"); detected from $LANG/$LC_MESSAGES if empty", Type: "string", Default: ""},
+			},
+		},
+		{
+			Icon: "🌐",
+			Name: "Web",
+			Settings: []Setting{
+				{Key: "webDevCommand", Name: "Dev Command", Description: "Command to start the web dev server (auto-detected if empty)", Type: "string", Default: ""},
+				{Key: "webDevPort", Name: "Dev Port", Description: "Port for the web dev server", Type: "int", Default: 5173},
+				{Key: "webHost", Name: "Host", Description: "Host to bind the web dev server to", Type: "string", Default: "localhost"},
+				{Key: "webHttps", Name: "HTTPS", Description: "Serve the web dev server over HTTPS", Type: "bool", Default: false},
+				{Key: "webOpenBrowser", Name: "Open Browser", Description: "Open a browser automatically when the dev server is ready", Type: "bool", Default: true},
+				{Key: "webBrowserPath", Name: "Browser Path", Description: "Path to a specific browser executable (uses system default if empty)", Type: "string", Default: ""},
+				{Key: "webAutoRestart", Name: "Auto-Restart", Description: "Automatically restart the web dev server with backoff if it crashes", Type: "bool", Default: false},
+			},
+		},
+		{
+			Icon: "📝",
+			Name: "Logs",
+			Settings: []Setting{
+				{Key: "editorCommand", Name: "Editor Command", Description: "Command used to open file references from log output (uses $EDITOR if empty)", Type: "string", Default: ""},
+			},
+		},
+		{
+			Icon: "📊",
+			Name: "Metrics",
+			Settings: []Setting{
+				{Key: "metricsPort", Name: "Metrics Port", Description: "Port to serve Prometheus metrics on (0 disables the metrics endpoint)", Type: "int", Default: 0},
+			},
+		},
+		{
+			Icon: "⚡",
+			Name: "Preflight",
+			Settings: []Setting{
+				{Key: "preflightWatchSeconds", Name: "Health-Watch Interval", Description: "Seconds between background preflight re-checks (0 disables continuous watching)", Type: "int", Default: 30},
+			},
+		},
+		{
+			Icon: "🔌",
+			Name: "Plugins",
+			Settings: []Setting{
+				{Key: "pluginManifestURL", Name: "Marketplace Manifest URL", Description: "HTTPS URL of the plugin marketplace manifest (the \"Available\" tab in the plugins panel is disabled if empty)", Type: "string", Default: ""},
+			},
+		},
+		{
+			Icon: "🎨",
+			Name: "Appearance",
+			Settings: []Setting{
+				{Key: "theme", Name: "Styleset", Description: "Color theme, loaded from ~/.config/lazycap/stylesets/*.ini (built-ins ship embedded)", Type: "choice", Default: theme.DefaultName, Choices: themeChoices()},
+			},
+		},
+	}
+}
+
+// themeChoices lists the stylesets available to cycle through: lazycap's
+// embedded defaults plus anything dropped into StylesetsDir().
+func themeChoices() []string {
+	dir, err := StylesetsDir()
+	if err != nil {
+		dir = ""
+	}
+	return theme.List(dir)
+}
+
+// StylesetsDir resolves ~/.config/lazycap/stylesets (or wherever
+// ConfigDir points), where users can drop their own *.ini stylesets
+// alongside the ones lazycap embeds.
+func StylesetsDir() (string, error) {
+	dir, err := ConfigDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "stylesets"), nil
+}
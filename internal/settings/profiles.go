@@ -0,0 +1,182 @@
+package settings
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// ProfilesDir resolves ~/.config/lazycap/profiles, where SaveProfile writes
+// timestamped snapshots of the current settings for later reuse (e.g.
+// swapping between per-project or per-device configurations).
+func ProfilesDir() (string, error) {
+	dir, err := ConfigDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "profiles"), nil
+}
+
+// ProfileChange is one key whose value would change if a profile were
+// applied, as returned by DiffProfile.
+type ProfileChange struct {
+	Key string
+	Old interface{}
+	New interface{}
+}
+
+// SaveProfile writes s's current values to a timestamped YAML file under
+// ProfilesDir, named "<name>-<unix-timestamp>.yml", and returns its path.
+func (s *Settings) SaveProfile(name string) (string, error) {
+	dir, err := ProfilesDir()
+	if err != nil {
+		return "", err
+	}
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return "", fmt.Errorf("failed to create profiles directory: %w", err)
+	}
+
+	s.mu.RLock()
+	data, err := yaml.Marshal(s.values)
+	s.mu.RUnlock()
+	if err != nil {
+		return "", fmt.Errorf("failed to encode profile: %w", err)
+	}
+
+	filename := fmt.Sprintf("%s-%d.yml", sanitizeProfileName(name), time.Now().Unix())
+	path := filepath.Join(dir, filename)
+	if err := os.WriteFile(path, data, 0o644); err != nil {
+		return "", fmt.Errorf("failed to write %s: %w", path, err)
+	}
+	return path, nil
+}
+
+// ListProfiles returns the names (file base name, no extension) of every
+// saved profile under ProfilesDir, oldest first.
+func ListProfiles() ([]string, error) {
+	dir, err := ProfilesDir()
+	if err != nil {
+		return nil, err
+	}
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to list profiles: %w", err)
+	}
+
+	var names []string
+	for _, e := range entries {
+		if e.IsDir() {
+			continue
+		}
+		ext := filepath.Ext(e.Name())
+		if ext != ".yml" && ext != ".yaml" && ext != ".json" {
+			continue
+		}
+		names = append(names, strings.TrimSuffix(e.Name(), ext))
+	}
+	sort.Strings(names)
+	return names, nil
+}
+
+// DiffProfile previews what LoadProfile(name) would change: every key in
+// the saved profile whose value differs from s's current one, in the same
+// green +new/red -old shape the settings panel renders before applying.
+func (s *Settings) DiffProfile(name string) ([]ProfileChange, error) {
+	loaded, err := readProfile(name)
+	if err != nil {
+		return nil, err
+	}
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	keys := make([]string, 0, len(loaded))
+	for k := range loaded {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	var changes []ProfileChange
+	for _, k := range keys {
+		newVal := loaded[k]
+		oldVal := s.values[k]
+		if fmt.Sprintf("%v", oldVal) != fmt.Sprintf("%v", newVal) {
+			changes = append(changes, ProfileChange{Key: k, Old: oldVal, New: newVal})
+		}
+	}
+	return changes, nil
+}
+
+// LoadProfile replaces s's values with the saved profile name's and
+// persists the result, the same way a manual settings edit does.
+func (s *Settings) LoadProfile(name string) error {
+	loaded, err := readProfile(name)
+	if err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	for k, v := range loaded {
+		s.values[k] = v
+	}
+	s.mu.Unlock()
+
+	return s.Save()
+}
+
+func readProfile(name string) (map[string]interface{}, error) {
+	path, err := profilePath(name)
+	if err != nil {
+		return nil, err
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read profile %q: %w", name, err)
+	}
+	var loaded map[string]interface{}
+	if err := yaml.Unmarshal(data, &loaded); err != nil {
+		return nil, fmt.Errorf("failed to parse profile %q: %w", name, err)
+	}
+	return loaded, nil
+}
+
+func profilePath(name string) (string, error) {
+	dir, err := ProfilesDir()
+	if err != nil {
+		return "", err
+	}
+	for _, ext := range []string{".yml", ".yaml", ".json"} {
+		p := filepath.Join(dir, name+ext)
+		if _, err := os.Stat(p); err == nil {
+			return p, nil
+		}
+	}
+	return "", fmt.Errorf("profile %q not found", name)
+}
+
+// sanitizeProfileName keeps SaveProfile's filenames filesystem-safe,
+// replacing anything outside [a-zA-Z0-9-_] with a dash.
+func sanitizeProfileName(name string) string {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		name = "profile"
+	}
+	var b strings.Builder
+	for _, r := range name {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9', r == '-', r == '_':
+			b.WriteRune(r)
+		default:
+			b.WriteRune('-')
+		}
+	}
+	return b.String()
+}
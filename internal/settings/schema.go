@@ -0,0 +1,192 @@
+package settings
+
+import (
+	"fmt"
+	"time"
+)
+
+// FieldType is the declared type of one Schema Field - lets
+// GetPluginSetting/SetPluginSetting coerce and validate a value instead of
+// guessing from a bare interface{} (the old behavior, which treated a
+// zero-value bool/string/int as "not set" and silently truncated floats
+// into ints).
+type FieldType int
+
+const (
+	TypeBool FieldType = iota
+	TypeInt
+	TypeFloat
+	TypeString
+	TypeDuration
+	TypeEnum
+	TypeStringList
+)
+
+// Field describes one setting a plugin reads/writes through a Schema: its
+// type, default, optional enum choices (when Type is TypeEnum), a
+// validator, and whether it's sensitive enough to withhold from logs and
+// diagnostics.
+type Field struct {
+	Key       string
+	Type      FieldType
+	Default   any
+	Enum      []string
+	Validate  func(any) error
+	Sensitive bool
+}
+
+// Coerce converts value to f's declared Type (e.g. YAML's float64 -> int)
+// and runs f.Validate if set, returning an error for anything that can't
+// be made to fit.
+func (f Field) Coerce(value any) (any, error) {
+	switch f.Type {
+	case TypeBool:
+		v, ok := value.(bool)
+		if !ok {
+			return nil, fmt.Errorf("%s: expected bool, got %T", f.Key, value)
+		}
+		return f.validate(v)
+
+	case TypeInt:
+		switch v := value.(type) {
+		case int:
+			return f.validate(v)
+		case float64:
+			return f.validate(int(v))
+		default:
+			return nil, fmt.Errorf("%s: expected int, got %T", f.Key, value)
+		}
+
+	case TypeFloat:
+		switch v := value.(type) {
+		case float64:
+			return f.validate(v)
+		case int:
+			return f.validate(float64(v))
+		default:
+			return nil, fmt.Errorf("%s: expected float, got %T", f.Key, value)
+		}
+
+	case TypeString:
+		v, ok := value.(string)
+		if !ok {
+			return nil, fmt.Errorf("%s: expected string, got %T", f.Key, value)
+		}
+		return f.validate(v)
+
+	case TypeDuration:
+		switch v := value.(type) {
+		case string:
+			d, err := time.ParseDuration(v)
+			if err != nil {
+				return nil, fmt.Errorf("%s: %w", f.Key, err)
+			}
+			return f.validate(d)
+		case time.Duration:
+			return f.validate(v)
+		default:
+			return nil, fmt.Errorf("%s: expected a duration, got %T", f.Key, value)
+		}
+
+	case TypeEnum:
+		v, ok := value.(string)
+		if !ok {
+			return nil, fmt.Errorf("%s: expected string, got %T", f.Key, value)
+		}
+		for _, choice := range f.Enum {
+			if choice == v {
+				return f.validate(v)
+			}
+		}
+		return nil, fmt.Errorf("%s: %q is not one of %v", f.Key, v, f.Enum)
+
+	case TypeStringList:
+		switch v := value.(type) {
+		case []string:
+			return f.validate(v)
+		case []interface{}:
+			out := make([]string, len(v))
+			for i, item := range v {
+				s, ok := item.(string)
+				if !ok {
+					return nil, fmt.Errorf("%s: element %d is not a string", f.Key, i)
+				}
+				out[i] = s
+			}
+			return f.validate(out)
+		default:
+			return nil, fmt.Errorf("%s: expected a string list, got %T", f.Key, value)
+		}
+
+	default:
+		return nil, fmt.Errorf("%s: unknown field type", f.Key)
+	}
+}
+
+func (f Field) validate(value any) (any, error) {
+	if f.Validate != nil {
+		if err := f.Validate(value); err != nil {
+			return nil, fmt.Errorf("%s: %w", f.Key, err)
+		}
+	}
+	return value, nil
+}
+
+// Schema is the set of Field declarations a plugin registers at Register()
+// time (see plugin.Context.RegisterPluginSchema) describing the settings
+// it reads and writes through GetPluginSetting/SetPluginSetting.
+type Schema struct {
+	fields map[string]Field
+}
+
+// NewSchema builds a Schema from fields, keyed by Field.Key.
+func NewSchema(fields ...Field) *Schema {
+	s := &Schema{fields: make(map[string]Field, len(fields))}
+	for _, f := range fields {
+		s.fields[f.Key] = f
+	}
+	return s
+}
+
+// Field looks up key's declaration. ok is false if s is nil or key wasn't
+// declared.
+func (s *Schema) Field(key string) (Field, bool) {
+	if s == nil {
+		return Field{}, false
+	}
+	f, ok := s.fields[key]
+	return f, ok
+}
+
+// Migration upgrades Settings from the schema version it's registered at
+// (its index in the migrations slice) to the next - renaming or
+// restructuring keys lazycap or a plugin used to store differently,
+// without breaking users who still have the old shape on disk.
+type Migration func(old *Settings) error
+
+var migrations []Migration
+
+// RegisterMigration appends m to the end of the migrations list, from an
+// init() alongside whatever change in defaultValues or a plugin's Schema
+// it corresponds to. m's position in the slice is the schema version it
+// migrates *from*; Load runs every migration from a Settings' own
+// SchemaVersion up to len(migrations).
+func RegisterMigration(m Migration) {
+	migrations = append(migrations, m)
+}
+
+// runMigrations applies any migrations newer than s.SchemaVersion,
+// advancing it as it goes, and persists once it's caught up so they never
+// run twice.
+func (s *Settings) runMigrations() error {
+	if s.SchemaVersion >= len(migrations) {
+		return nil
+	}
+	for s.SchemaVersion < len(migrations) {
+		if err := migrations[s.SchemaVersion](s); err != nil {
+			return fmt.Errorf("settings migration %d failed: %w", s.SchemaVersion, err)
+		}
+		s.SchemaVersion++
+	}
+	return s.Save()
+}
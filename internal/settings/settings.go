@@ -0,0 +1,315 @@
+// Package settings manages lazycap's on-disk user configuration: where it
+// lives, how it's loaded/saved, and the typed getters/setters the settings
+// panel and plugins read and write through.
+package settings
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/icarus-itcs/lazycap/internal/theme"
+)
+
+// Settings holds the loaded configuration values, keyed the same way the
+// YAML file is, plus the path it was loaded from.
+type Settings struct {
+	mu sync.RWMutex
+
+	// SchemaVersion is the highest migration index (see RegisterMigration)
+	// already applied to values - persisted alongside them so Load only
+	// ever runs a migration once.
+	SchemaVersion int
+
+	values map[string]interface{}
+	path   string
+}
+
+// ConfigDir resolves the directory lazycap stores its config, state, and
+// logs in. It honors LAZYCAP_CONFIG_DIR first, then falls back to
+// os.UserConfigDir() (which itself respects XDG_CONFIG_HOME on Linux).
+func ConfigDir() (string, error) {
+	if dir := os.Getenv("LAZYCAP_CONFIG_DIR"); dir != "" {
+		return dir, nil
+	}
+	base, err := os.UserConfigDir()
+	if err != nil {
+		return "", fmt.Errorf("failed to resolve user config directory: %w", err)
+	}
+	return filepath.Join(base, "lazycap"), nil
+}
+
+// ConfigPath returns the resolved path to config.yml.
+func ConfigPath() (string, error) {
+	dir, err := ConfigDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "config.yml"), nil
+}
+
+// StatePath returns the resolved path to state.yml.
+func StatePath() (string, error) {
+	dir, err := ConfigDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "state.yml"), nil
+}
+
+// LogDir returns the resolved path to the log/ directory.
+func LogDir() (string, error) {
+	dir, err := ConfigDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "log"), nil
+}
+
+// DataDir resolves the directory lazycap stores larger, longer-lived data
+// in - currently just the per-process ring-buffered logs (see
+// logbuffer.Open) - as opposed to ConfigDir's config/state/debug-log,
+// which are small and meant to be hand-edited or tailed. It honors
+// XDG_DATA_HOME first, then falls back to ~/.local/share.
+func DataDir() (string, error) {
+	if dir := os.Getenv("XDG_DATA_HOME"); dir != "" {
+		return filepath.Join(dir, "lazycap"), nil
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", fmt.Errorf("failed to resolve user home directory: %w", err)
+	}
+	return filepath.Join(home, ".local", "share", "lazycap"), nil
+}
+
+func defaultValues() map[string]interface{} {
+	return map[string]interface{}{
+		"liveReloadDefault":     false,
+		"webDevPort":            5173,
+		"webHost":               "localhost",
+		"webHttps":              false,
+		"webOpenBrowser":        true,
+		"webBrowserPath":        "",
+		"webDevCommand":         "",
+		"webAutoRestart":        false,
+		"editorCommand":         "",
+		"metricsPort":           0,
+		"preflightWatchSeconds": 30,
+		"language":              "",
+		"pluginManifestURL":     "",
+		"theme":                 theme.DefaultName,
+
+		// List of notify.BackendConfig entries (see internal/notify); nil
+		// means no notification backends are configured.
+		"notifications": nil,
+
+		// Local control API (see internal/controlapi and the --api flag).
+		// apiBindAddress defaults to loopback-only, same as the MCP
+		// plugin's bindAddress - set it to "0.0.0.0" to allow remote
+		// IDE/CI clients to reach it.
+		"apiEnabled":     false,
+		"apiPort":        4550,
+		"apiToken":       "",
+		"apiBindAddress": "127.0.0.1",
+
+		// Permissions a user has granted to installed plugins, keyed by
+		// plugin ID (map[string][]string of internal/plugin.Permission
+		// values), and the trusted ed25519 public key (hex) signed
+		// manifests are verified against - both nil/empty until a plugin
+		// asks for something and a key is pinned (see
+		// internal/plugin/distribution.go).
+		"pluginPermissions":        nil,
+		"pluginSignaturePublicKey": "",
+
+		// Per-process ring-buffered log retention (see logbuffer.Open).
+		"logWindowLines":         5000,
+		"logRetentionMaxMB":      200,
+		"logRetentionMaxAgeDays": 14,
+
+		// Max concurrent HTTP downloads the shared internal/download.Pool
+		// runs for the update subsystem and plugin installer combined.
+		"downloadConcurrency": 4,
+
+		// Per-plugin structured log retention (see
+		// internal/plugin/logging.go): how many recent entries each
+		// plugin's in-memory ring buffer keeps, and how large its
+		// persisted ~/.lazycap/logs/<pluginID>.log is allowed to grow
+		// before rotating.
+		"pluginLogWindowEntries": 1000,
+		"pluginLogMaxMB":         10,
+	}
+}
+
+// Load reads config.yml from the resolved config directory, falling back
+// to defaults for anything missing or if the file doesn't exist yet, then
+// runs any migrations (see RegisterMigration) newer than the file's own
+// SchemaVersion.
+func Load() (*Settings, error) {
+	path, err := ConfigPath()
+	if err != nil {
+		return nil, err
+	}
+
+	s := &Settings{values: defaultValues(), path: path}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			s.SchemaVersion = len(migrations)
+			return s, nil
+		}
+		return nil, fmt.Errorf("failed to read %s: %w", path, err)
+	}
+
+	var loaded map[string]interface{}
+	if err := yaml.Unmarshal(data, &loaded); err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", path, err)
+	}
+	if v, ok := loaded["schemaVersion"]; ok {
+		s.SchemaVersion = toInt(v)
+	}
+	delete(loaded, "schemaVersion")
+	for k, v := range loaded {
+		s.values[k] = v
+	}
+
+	if err := s.runMigrations(); err != nil {
+		return nil, err
+	}
+
+	return s, nil
+}
+
+// Save writes the current values back to the config file, creating its
+// directory if necessary.
+func (s *Settings) Save() error {
+	s.mu.RLock()
+	out := make(map[string]interface{}, len(s.values)+1)
+	for k, v := range s.values {
+		out[k] = v
+	}
+	out["schemaVersion"] = s.SchemaVersion
+	data, err := yaml.Marshal(out)
+	path := s.path
+	s.mu.RUnlock()
+	if err != nil {
+		return fmt.Errorf("failed to encode settings: %w", err)
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return fmt.Errorf("failed to create config directory: %w", err)
+	}
+	if err := os.WriteFile(path, data, 0o644); err != nil {
+		return fmt.Errorf("failed to write %s: %w", path, err)
+	}
+	return nil
+}
+
+func toInt(v interface{}) int {
+	switch n := v.(type) {
+	case int:
+		return n
+	case float64:
+		return int(n)
+	default:
+		return 0
+	}
+}
+
+func (s *Settings) GetBool(key string) bool {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	v, _ := s.values[key].(bool)
+	return v
+}
+
+func (s *Settings) GetString(key string) string {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	v, _ := s.values[key].(string)
+	return v
+}
+
+func (s *Settings) GetInt(key string) int {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	switch v := s.values[key].(type) {
+	case int:
+		return v
+	case float64:
+		return int(v)
+	default:
+		return 0
+	}
+}
+
+// GetRaw returns the raw value stored under key without any type
+// assertion, for callers that parse a nested structure themselves (e.g.
+// notify.BuildDispatcher parsing the "notifications" list).
+func (s *Settings) GetRaw(key string) interface{} {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.values[key]
+}
+
+// SetRaw stores value under key without any type assertion - the write
+// side of GetRaw, used by callers (like GetPluginSetting/SetPluginSetting)
+// that already validated value against a Schema themselves.
+func (s *Settings) SetRaw(key string, value interface{}) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.values[key] = value
+}
+
+func (s *Settings) SetBool(key string, value bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.values[key] = value
+}
+
+func (s *Settings) SetString(key string, value string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.values[key] = value
+}
+
+func (s *Settings) SetInt(key string, value int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.values[key] = value
+}
+
+// ToggleBool flips a boolean setting and returns nothing; callers re-read
+// via GetBool.
+func (s *Settings) ToggleBool(key string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	v, _ := s.values[key].(bool)
+	s.values[key] = !v
+}
+
+// CycleChoice advances a string setting to the next value in choices
+// (wrapping around, and treating "not currently one of choices" as
+// starting before the first entry), returning the new value.
+func (s *Settings) CycleChoice(key string, choices []string) string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	current, _ := s.values[key].(string)
+	next := ""
+	if len(choices) > 0 {
+		idx := -1
+		for i, c := range choices {
+			if c == current {
+				idx = i
+				break
+			}
+		}
+		next = choices[(idx+1)%len(choices)]
+	}
+	s.values[key] = next
+	return next
+}
@@ -0,0 +1,83 @@
+package settings
+
+import (
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+)
+
+// Watcher watches the on-disk config file for external edits (e.g. made
+// from an editor while lazycap is running) so callers can reload Settings
+// in place instead of requiring a restart.
+type Watcher struct {
+	fsw    *fsnotify.Watcher
+	stopCh chan struct{}
+}
+
+// Watch starts watching the directory containing config.yml - not the
+// file itself, since editors commonly save by writing a temp file and
+// renaming it over the original, which fsnotify only reports as events on
+// the containing directory - and returns a Watcher plus a channel that
+// fires once per batch of changes. Bursts of write+rename events from a
+// single save are collapsed into one fire via a 500ms debounce.
+func Watch() (*Watcher, <-chan struct{}, error) {
+	path, err := ConfigPath()
+	if err != nil {
+		return nil, nil, err
+	}
+	fsw, err := fsnotify.NewWatcher()
+	if err != nil {
+		return nil, nil, err
+	}
+	if err := fsw.Add(filepath.Dir(path)); err != nil {
+		fsw.Close()
+		return nil, nil, err
+	}
+
+	changed := make(chan struct{}, 1)
+	stopCh := make(chan struct{})
+	w := &Watcher{fsw: fsw, stopCh: stopCh}
+
+	go func() {
+		var mu sync.Mutex
+		var debounce *time.Timer
+		fire := func() {
+			select {
+			case changed <- struct{}{}:
+			default:
+			}
+		}
+		for {
+			select {
+			case event, ok := <-fsw.Events:
+				if !ok {
+					return
+				}
+				if filepath.Clean(event.Name) != path {
+					continue
+				}
+				mu.Lock()
+				if debounce != nil {
+					debounce.Stop()
+				}
+				debounce = time.AfterFunc(500*time.Millisecond, fire)
+				mu.Unlock()
+			case <-fsw.Errors:
+				// A failed stat on a transient rename-on-save temp file
+				// isn't worth surfacing.
+			case <-stopCh:
+				fsw.Close()
+				return
+			}
+		}
+	}()
+
+	return w, changed, nil
+}
+
+// Stop tears down the underlying fsnotify watcher and its goroutine.
+func (w *Watcher) Stop() {
+	close(w.stopCh)
+}
@@ -0,0 +1,191 @@
+// Package theme parses aerc-style "styleset" files - one `selector=style`
+// line per entry, e.g. `tab.active=#61afef bold` - into a Styleset that
+// internal/ui resolves named selectors (tab.active, settings.value,
+// debug.danger, plugin.running, ...) against when building its lipgloss
+// styles, with wildcard fallback for selectors a styleset leaves unset.
+package theme
+
+import (
+	"bufio"
+	"embed"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+//go:embed stylesets/*.ini
+var embeddedFS embed.FS
+
+// embeddedDir is the directory embeddedFS exposes the bundled default
+// stylesets under.
+const embeddedDir = "stylesets"
+
+// DefaultName is the styleset lazycap ships and falls back to when the
+// user hasn't picked one (or the configured one fails to load) - it
+// reproduces the original hard-coded Capacitor palette exactly.
+const DefaultName = "capacitor"
+
+// Style is one resolved selector's worth of styling: a foreground and/or
+// background color (lipgloss-compatible strings - hex or ANSI names) plus
+// text attributes.
+type Style struct {
+	FG        string
+	BG        string
+	Bold      bool
+	Italic    bool
+	Underline bool
+}
+
+// Styleset is a parsed collection of selector -> Style entries, along with
+// the name it was loaded as.
+type Styleset struct {
+	Name   string
+	styles map[string]Style
+}
+
+// Parse reads an INI-style styleset from r: blank lines and lines starting
+// with '#' or ';' are ignored, every other non-blank line must be
+// `selector=tokens`, where tokens are space-separated and each is either a
+// `#rrggbb` foreground color, a `bg:#rrggbb` background color, or one of
+// the attribute keywords bold/italic/underline.
+func Parse(name string, r io.Reader) (*Styleset, error) {
+	ss := &Styleset{Name: name, styles: make(map[string]Style)}
+
+	scanner := bufio.NewScanner(r)
+	lineNo := 0
+	for scanner.Scan() {
+		lineNo++
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") || strings.HasPrefix(line, ";") {
+			continue
+		}
+
+		selector, rawTokens, ok := strings.Cut(line, "=")
+		if !ok {
+			return nil, fmt.Errorf("%s:%d: expected selector=style, got %q", name, lineNo, line)
+		}
+		selector = strings.TrimSpace(selector)
+
+		var style Style
+		for _, tok := range strings.Fields(rawTokens) {
+			switch {
+			case strings.HasPrefix(tok, "bg:"):
+				style.BG = strings.TrimPrefix(tok, "bg:")
+			case strings.HasPrefix(tok, "fg:"):
+				style.FG = strings.TrimPrefix(tok, "fg:")
+			case strings.HasPrefix(tok, "#"):
+				style.FG = tok
+			case tok == "bold":
+				style.Bold = true
+			case tok == "italic":
+				style.Italic = true
+			case tok == "underline":
+				style.Underline = true
+			default:
+				return nil, fmt.Errorf("%s:%d: unrecognized style token %q", name, lineNo, tok)
+			}
+		}
+		ss.styles[selector] = style
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read styleset %s: %w", name, err)
+	}
+
+	return ss, nil
+}
+
+// Resolve looks up selector, falling back to its "<group>.*" wildcard and
+// then the bare "*" catch-all, and finally to fallback if the styleset
+// defines none of those. A selector is expected in "group.name" form
+// (e.g. "tab.active"); selectors without a "." only ever match themselves
+// or "*".
+func (ss *Styleset) Resolve(selector string, fallback Style) Style {
+	if ss == nil {
+		return fallback
+	}
+	if s, ok := ss.styles[selector]; ok {
+		return s
+	}
+	if group, _, ok := strings.Cut(selector, "."); ok {
+		if s, ok := ss.styles[group+".*"]; ok {
+			return s
+		}
+	}
+	if s, ok := ss.styles["*"]; ok {
+		return s
+	}
+	return fallback
+}
+
+// Default returns the embedded "capacitor" styleset, matching lazycap's
+// original hard-coded palette.
+func Default() *Styleset {
+	ss, err := LoadEmbedded(DefaultName)
+	if err != nil {
+		// The embedded default failing to parse would be a build-time bug,
+		// not a runtime condition - fall back to an empty styleset so
+		// every caller's hard-coded fallback colors still apply.
+		return &Styleset{Name: DefaultName, styles: map[string]Style{}}
+	}
+	return ss
+}
+
+// LoadEmbedded loads one of the stylesets shipped with lazycap by name
+// (without the .ini extension).
+func LoadEmbedded(name string) (*Styleset, error) {
+	f, err := embeddedFS.Open(filepath.Join(embeddedDir, name+".ini"))
+	if err != nil {
+		return nil, fmt.Errorf("unknown built-in styleset %q: %w", name, err)
+	}
+	defer f.Close()
+	return Parse(name, f)
+}
+
+// Load loads a styleset by name, checking userDir first (for
+// `~/.config/lazycap/stylesets/<name>.ini`) before falling back to the
+// stylesets bundled with lazycap.
+func Load(name, userDir string) (*Styleset, error) {
+	if userDir != "" {
+		path := filepath.Join(userDir, name+".ini")
+		if f, err := os.Open(path); err == nil {
+			defer f.Close()
+			return Parse(name, f)
+		}
+	}
+	return LoadEmbedded(name)
+}
+
+// List returns the names (without .ini) of every styleset available to
+// choose from: the ones bundled with lazycap, plus any `*.ini` files found
+// in userDir, deduplicated and sorted with built-ins first.
+func List(userDir string) []string {
+	seen := make(map[string]bool)
+	var names []string
+
+	entries, _ := embeddedFS.ReadDir(embeddedDir)
+	for _, e := range entries {
+		name := strings.TrimSuffix(e.Name(), ".ini")
+		if !seen[name] {
+			seen[name] = true
+			names = append(names, name)
+		}
+	}
+
+	if userDir != "" {
+		userEntries, _ := os.ReadDir(userDir)
+		for _, e := range userEntries {
+			if e.IsDir() || !strings.HasSuffix(e.Name(), ".ini") {
+				continue
+			}
+			name := strings.TrimSuffix(e.Name(), ".ini")
+			if !seen[name] {
+				seen[name] = true
+				names = append(names, name)
+			}
+		}
+	}
+
+	return names
+}
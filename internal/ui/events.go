@@ -0,0 +1,85 @@
+package ui
+
+import (
+	"fmt"
+
+	"github.com/icarus-itcs/lazycap/internal/device"
+	"github.com/icarus-itcs/lazycap/internal/notify"
+)
+
+// notifyDeviceChanges diffs the freshly-loaded device list against the
+// model's current one and fires the corresponding plugin events
+// (device.added/removed/online/offline) before m.devices is replaced with
+// newDevices. Plugins that want to react to a device disappearing (e.g.
+// auto-reboot a preferred simulator) or coming online (e.g. kick off a
+// run) subscribe to these instead of polling GetDevices.
+func (m *Model) notifyDeviceChanges(newDevices []device.Device) {
+	if m.pluginContext == nil {
+		return
+	}
+
+	old := make(map[string]device.Device, len(m.devices))
+	for _, d := range m.devices {
+		old[d.ID] = d
+	}
+	seen := make(map[string]bool, len(newDevices))
+
+	for i := range newDevices {
+		d := newDevices[i]
+		seen[d.ID] = true
+		prev, existed := old[d.ID]
+		if !existed {
+			m.pluginContext.NotifyDeviceAdded(&newDevices[i])
+			continue
+		}
+		if d.Online && !prev.Online {
+			m.pluginContext.NotifyDeviceOnline(&newDevices[i])
+		} else if !d.Online && prev.Online {
+			m.pluginContext.NotifyDeviceOffline(&newDevices[i])
+		}
+	}
+
+	for id := range old {
+		if !seen[id] {
+			m.pluginContext.NotifyDeviceRemoved(id)
+		}
+	}
+}
+
+// notifyProcessOutcome reports a finished process through the configured
+// notification backends (see internal/notify), but only for builds and
+// fatal exits - every other process finishing (sync, web, open, a normal
+// `cap run`) is too routine to be worth paging someone over.
+func (m *Model) notifyProcessOutcome(p *Process, err error) {
+	if m.pluginContext == nil {
+		return
+	}
+
+	var event notify.Event
+	switch {
+	case processAction(p) == "build" && err == nil:
+		event = notify.Event{Kind: notify.KindBuildSucceeded, Severity: notify.SeverityInfo, Title: "Build succeeded", Message: p.Name}
+	case processAction(p) == "build" && err != nil:
+		event = notify.Event{Kind: notify.KindBuildFailed, Severity: notify.SeverityError, Title: "Build failed", Message: fmt.Sprintf("%s: %v", p.Name, err)}
+	case p.Status == ProcessFatal:
+		event = notify.Event{Kind: notify.KindProcessCrashed, Severity: notify.SeverityError, Title: "Process crashed", Message: p.Name}
+	default:
+		return
+	}
+
+	m.pluginContext.Notify(event)
+}
+
+// notifyPreflightUpdated fires preflight.updated whenever preflight checks
+// are (re-)run, so a plugin can react to a newly discovered error/warning
+// without polling.
+func (m *Model) notifyPreflightUpdated() {
+	if m.pluginContext == nil || m.preflightResults == nil {
+		return
+	}
+	m.pluginContext.NotifyPreflightUpdated(
+		m.preflightResults.HasErrors,
+		m.preflightResults.HasWarnings,
+		m.preflightResults.Summary(),
+	)
+}
@@ -0,0 +1,110 @@
+package ui
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/icarus-itcs/lazycap/internal/debug"
+	"github.com/icarus-itcs/lazycap/internal/settings"
+)
+
+// fuzzyMatch subsequence-scores target against query (case-insensitive):
+// +1 for every matched character, +3 more if it directly follows the
+// previous match, -1 per character skipped since the last match. ok is
+// false unless every rune in query appears, in order, somewhere in
+// target - callers use that to filter non-matches, and score to rank the
+// rest (higher is a tighter match).
+func fuzzyMatch(query, target string) (score int, ok bool) {
+	if query == "" {
+		return 0, true
+	}
+
+	q := []rune(strings.ToLower(query))
+	t := []rune(strings.ToLower(target))
+
+	qi := 0
+	lastMatch := -1
+	for ti := 0; ti < len(t) && qi < len(q); ti++ {
+		if t[ti] != q[qi] {
+			continue
+		}
+		score++
+		switch {
+		case lastMatch == ti-1:
+			score += 3
+		case lastMatch != -1:
+			score -= ti - lastMatch - 1
+		}
+		lastMatch = ti
+		qi++
+	}
+
+	return score, qi == len(q)
+}
+
+// fuzzyMatchAny reports the best score across any of fields matching
+// query, used to let a search term match either a name or its
+// description.
+func fuzzyMatchAny(query string, fields ...string) (score int, ok bool) {
+	best := 0
+	matched := false
+	for _, f := range fields {
+		if s, fOK := fuzzyMatch(query, f); fOK {
+			matched = true
+			if s > best {
+				best = s
+			}
+		}
+	}
+	return best, matched
+}
+
+// filterSettings flattens every category's settings into a single list,
+// scored and filtered against query by name and description, and sorted
+// best-match-first - used to back the settings panel's "/" search so a
+// match outside the active tab is still reachable.
+func filterSettings(query string) []settingsFilterResult {
+	var results []settingsFilterResult
+	for ci, cat := range settings.GetCategories() {
+		for si, s := range cat.Settings {
+			score, ok := fuzzyMatchAny(query, s.Name, s.Description)
+			if !ok {
+				continue
+			}
+			results = append(results, settingsFilterResult{
+				categoryIdx: ci,
+				settingIdx:  si,
+				category:    cat.Name,
+				setting:     s,
+				score:       score,
+			})
+		}
+	}
+	sort.SliceStable(results, func(i, j int) bool { return results[i].score > results[j].score })
+	return results
+}
+
+// filterDebugActions scores actions against query by name and description,
+// mirroring filterSettings for the debug panel's "/" search.
+func filterDebugActions(actions []debug.Action, query string) []debugFilterResult {
+	categories := debug.GetCategories()
+
+	var results []debugFilterResult
+	for _, a := range actions {
+		score, ok := fuzzyMatchAny(query, a.Name, a.Description)
+		if !ok {
+			continue
+		}
+		catIdx, catName := 0, fmt.Sprintf("%v", a.Category)
+		for i, c := range categories {
+			if c == a.Category {
+				catIdx, catName = i, fmt.Sprintf("%v", c)
+				break
+			}
+		}
+		results = append(results, debugFilterResult{categoryIdx: catIdx, category: catName, action: a, score: score})
+	}
+	sort.SliceStable(results, func(i, j int) bool { return results[i].score > results[j].score })
+	return results
+}
@@ -0,0 +1,32 @@
+package ui
+
+import "github.com/icarus-itcs/lazycap/internal/keybindings"
+
+// init registers the main view's keybindings with the shared registry so
+// the cheatsheet generator can discover them without depending on the
+// bubbles/key binding types directly.
+func init() {
+	keybindings.Register("main",
+		keybindings.Binding{Key: "↑/k", Description: "up", I18nKey: "keys.main.up"},
+		keybindings.Binding{Key: "↓/j", Description: "down", I18nKey: "keys.main.down"},
+		keybindings.Binding{Key: "tab", Description: "switch pane", I18nKey: "keys.main.tab"},
+		keybindings.Binding{Key: "r", Description: "run", I18nKey: "keys.main.run"},
+		keybindings.Binding{Key: "s", Description: "sync", I18nKey: "keys.main.sync"},
+		keybindings.Binding{Key: "b", Description: "build", I18nKey: "keys.main.build"},
+		keybindings.Binding{Key: "o", Description: "open IDE", I18nKey: "keys.main.open"},
+		keybindings.Binding{Key: "x", Description: "kill", I18nKey: "keys.main.kill"},
+		keybindings.Binding{Key: "R", Description: "refresh", I18nKey: "keys.main.refresh"},
+		keybindings.Binding{Key: "u", Description: "upgrade", I18nKey: "keys.main.upgrade"},
+		keybindings.Binding{Key: "?", Description: "help", I18nKey: "keys.main.help"},
+		keybindings.Binding{Key: "q", Description: "quit", I18nKey: "keys.main.quit"},
+		keybindings.Binding{Key: "←/h", Description: "prev tab", I18nKey: "keys.main.left"},
+		keybindings.Binding{Key: "→/l", Description: "next tab", I18nKey: "keys.main.right"},
+		keybindings.Binding{Key: "c", Description: "copy logs", I18nKey: "keys.main.copy"},
+		keybindings.Binding{Key: "e", Description: "export logs", I18nKey: "keys.main.export"},
+		keybindings.Binding{Key: "p", Description: "preflight", I18nKey: "keys.main.preflight"},
+		keybindings.Binding{Key: ",", Description: "settings", I18nKey: "keys.main.settings"},
+		keybindings.Binding{Key: "d", Description: "debug", I18nKey: "keys.main.debug"},
+		keybindings.Binding{Key: "P", Description: "plugins", I18nKey: "keys.main.plugins"},
+		keybindings.Binding{Key: "enter", Description: "toggle", I18nKey: "keys.main.enter"},
+	)
+}
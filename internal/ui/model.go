@@ -2,6 +2,7 @@ package ui
 
 import (
 	"bufio"
+	"context"
 	"fmt"
 	"os"
 	"os/exec"
@@ -14,17 +15,27 @@ import (
 	"github.com/atotto/clipboard"
 	"github.com/charmbracelet/bubbles/help"
 	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/progress"
 	"github.com/charmbracelet/bubbles/spinner"
+	"github.com/charmbracelet/bubbles/textinput"
 	"github.com/charmbracelet/bubbles/viewport"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
 
-	"lazycap/internal/cap"
-	"lazycap/internal/debug"
-	"lazycap/internal/device"
-	"lazycap/internal/plugin"
-	"lazycap/internal/preflight"
-	"lazycap/internal/settings"
+	"github.com/icarus-itcs/lazycap/internal/cap"
+	"github.com/icarus-itcs/lazycap/internal/debug"
+	"github.com/icarus-itcs/lazycap/internal/device"
+	"github.com/icarus-itcs/lazycap/internal/i18n"
+	"github.com/icarus-itcs/lazycap/internal/jobqueue"
+	"github.com/icarus-itcs/lazycap/internal/logparse"
+	"github.com/icarus-itcs/lazycap/internal/metrics"
+	"github.com/icarus-itcs/lazycap/internal/plugin"
+	"github.com/icarus-itcs/lazycap/internal/preflight"
+	"github.com/icarus-itcs/lazycap/internal/qr"
+	"github.com/icarus-itcs/lazycap/internal/session"
+	"github.com/icarus-itcs/lazycap/internal/settings"
+	"github.com/icarus-itcs/lazycap/internal/theme"
+	"github.com/icarus-itcs/lazycap/internal/update"
 )
 
 // Comprehensive ANSI escape sequence regex - handles:
@@ -36,12 +47,24 @@ var ansiRegex = regexp.MustCompile(`\x1b\[[0-9;?]*[a-zA-Z]|\x1b\][^\x07\x1b]*(?:
 
 // Debug logging
 var (
+	// Debug enables verbose logging to debugLogPath. It is set from the
+	// CLI's -d/--debug flag before the model is constructed.
+	Debug bool
+
+	// ThemeOverride, when non-empty, takes precedence over the "theme"
+	// setting for this run only - set from the CLI's --theme flag before
+	// the model is constructed, and never persisted to settings.
+	ThemeOverride string
+
 	debugFile    *os.File
 	debugFileMu  sync.Mutex
 	debugLogPath = "/tmp/lazycap-debug.log"
 )
 
 func debugLog(format string, args ...interface{}) {
+	if !Debug {
+		return
+	}
 	debugFileMu.Lock()
 	defer debugFileMu.Unlock()
 	if debugFile == nil {
@@ -76,6 +99,14 @@ func (m *Model) getTerminalTitle() string {
 		projectName = m.project.Name
 	}
 
+	if len(m.processGroups) > 0 {
+		g := m.processGroups[len(m.processGroups)-1]
+		gRunning, gSuccess, gFailed := g.Counts(m.processes)
+		if gRunning+gSuccess+gFailed > 0 {
+			return fmt.Sprintf("⚡ %s - %s: %d running, %d ok, %d failed", projectName, g.Name, gRunning, gSuccess, gFailed)
+		}
+	}
+
 	if running > 0 {
 		if running == 1 {
 			// Show what's running
@@ -109,18 +140,35 @@ type Model struct {
 	upgradeInfo *cap.UpgradeInfo
 
 	// Devices
-	devices        []device.Device
-	selectedDevice int
+	devices         []device.Device
+	selectedDevice  int
+	selectedDevices map[int]bool // multi-selected device indices, for mirrored runs
 
 	// Processes (tabs above logs)
 	processes       []*Process
 	selectedProcess int
 	nextProcessID   int
 	outputChans     map[string]chan string
+	processGroups   []*ProcessGroup
+
+	// Log view: severity filter and jump-to-error cursor (internal/logparse)
+	logSeverityFilter logparse.Level
+	logFilterActive   bool
+	logCursor         int // index into the selected process's Entries, -1 if unset
 
 	// Preflight checks
-	preflightResults *preflight.Results
-	showPreflight    bool
+	preflightResults  *preflight.Results
+	showPreflight     bool
+	preflightCursor   int
+	preflightConfirm  bool
+	remediationProcID string
+
+	// preflightHistory tracks the last preflightHistoryLen statuses per
+	// check name (oldest first), fed by the background health-watcher
+	// (see preflightWatchTick), so the header indicator can tell a check
+	// that's flapping (e.g. a simulator that comes and goes) apart from
+	// one that's consistently failing.
+	preflightHistory map[string][]preflight.Status
 
 	// Settings
 	settings         *settings.Settings
@@ -128,12 +176,62 @@ type Model struct {
 	settingsCursor   int
 	settingsCategory int
 
+	// Settings fuzzy-search overlay (see handleSettingsInput's "/" key):
+	// results are flattened across every category so a match outside the
+	// active tab is still reachable, with its category shown inline.
+	settingsFilterActive  bool
+	settingsFilterInput   textinput.Model
+	settingsFilterResults []settingsFilterResult
+	settingsFilterCursor  int
+	lastSettingsQuery     string
+
+	// Profile import/export overlay (see handleSettingsInput's "x"/"i"
+	// keys): "x" exports the current settings straight to disk, "i" opens
+	// this chooser over settings.ListProfiles(). profileDiffPending holds
+	// the name of a profile whose settings.DiffProfile preview is showing,
+	// awaiting a second "enter" to actually apply it via LoadProfile.
+	showProfileChooser bool
+	profileNames       []string
+	profileCursor      int
+	profileDiffPending string
+	profileDiffPreview []settings.ProfileChange
+
+	// settingsWatcher/settingsChangeCh back the hot-reload flow in
+	// settingsReloadedMsg: settingsWatcher is stopped from
+	// gracefulShutdown, and settingsChangeCh is drained by
+	// watchSettingsReload (started from Init).
+	settingsWatcher  *settings.Watcher
+	settingsChangeCh <-chan struct{}
+
+	// Live-reload URL/QR overlay
+	showLiveReload    bool
+	liveReloadProcIdx int
+
 	// Plugins
 	pluginManager *plugin.Manager
 	pluginContext *plugin.AppContext
 	showPlugins   bool
 	pluginCursor  int
 
+	// Plugin marketplace (the "Available" tab in the plugins panel - see
+	// renderPlugins/handlePluginsInput). pluginRegistry is created lazily,
+	// the first time the Available tab is opened, since most sessions
+	// never touch it.
+	pluginRegistry     *plugin.Registry
+	pluginsTab         int // 0 = Installed, 1 = Available
+	marketplaceEntries []plugin.RegistryEntry
+	marketplaceCursor  int
+	marketplaceLoading bool
+	pluginSearchActive bool
+	pluginSearchQuery  string
+	pluginActionStatus map[string]string // plugin ID -> "installing...", "updating...", etc.
+
+	// pluginEnablePlan holds a dependency-resolved plugin.Plan awaiting
+	// confirmation (see handlePluginsInput's "e" key) when enabling the
+	// plugin at pluginEnableTarget would also auto-enable others.
+	pluginEnablePlan   *plugin.Plan
+	pluginEnableTarget string
+
 	// UI
 	focus         Focus
 	logViewport   viewport.Model
@@ -151,6 +249,12 @@ type Model struct {
 	confirmQuit bool
 	quitTime    time.Time
 
+	// PTY input mode: while true, keystrokes are forwarded directly to the
+	// selected process's pty (see Process.WriteInput) instead of being
+	// interpreted as lazycap shortcuts - for answering interactive
+	// prompts from PTY-backed processes like `cap run`.
+	ptyInputMode bool
+
 	// Debug panel
 	showDebug       bool
 	debugActions    []debug.Action
@@ -159,55 +263,146 @@ type Model struct {
 	debugConfirm    bool
 	debugResult     *debug.Result
 	debugResultTime time.Time
+
+	// debugJobs tracks debug actions currently running via
+	// debug.RunActionAsync, keyed by action ID, so renderDebug can show a
+	// progress bar inline and handleDebugInput can cancel one with "c".
+	// Update only ever touches this from the single Bubble Tea event loop,
+	// so a plain map is enough here (same reasoning as m.outputChans) -
+	// any concurrency-safe bookkeeping RunActionAsync itself needs for its
+	// background goroutines lives inside the debug package, not here.
+	debugJobs map[string]*debugJob
+
+	// Debug fuzzy-search overlay (see handleDebugInput's "/" key), mirrors
+	// the settings one above.
+	debugFilterActive  bool
+	debugFilterInput   textinput.Model
+	debugFilterResults []debugFilterResult
+	debugFilterCursor  int
+
+	// jobQueue persists asynchronously-run commands across restarts with
+	// scheduled/retry/dead-letter bookkeeping (see internal/jobqueue).
+	// jobScheduler promotes due jobs and is what waitJobReady blocks on;
+	// both are nil if jobqueue.Open failed (e.g. ConfigDir unavailable),
+	// in which case the queue is simply unavailable for this run.
+	jobQueue       *jobqueue.Store
+	jobScheduler   *jobqueue.Scheduler
+	lastDebugQuery string
+
+	// Session persistence/reattach (internal/session): tea.Cmds to tail any
+	// sessions reattached from a previous lazycap run, started from Init.
+	reattachCmds []tea.Cmd
+
+	// Remote (SSH `lazycap serve`) sessions: exportDir redirects the
+	// export path away from the server's own os.TempDir, and
+	// remoteSession disables clipboard access (there's no local
+	// clipboard to reach on the server). Both are zero-valued, and
+	// therefore no-ops, for a normal local run. See SetRemoteSession.
+	exportDir     string
+	remoteSession bool
+
+	// metrics backs the optional Prometheus /metrics endpoint (see
+	// internal/metrics and the metricsPort setting); always allocated,
+	// but only served over HTTP (or, with --metrics-stdout, printed
+	// periodically) if metricsPort is nonzero.
+	metrics       *metrics.Registry
+	metricsStdout bool
+
+	// apiPort is nonzero while the local control API (see --api and
+	// internal/controlapi) is listening, purely for the header indicator -
+	// the server itself runs outside the Model, started by cmd/lazycap.
+	apiPort int
+}
+
+// SetMetricsStdout switches metrics reporting from the metricsPort HTTP
+// endpoint to printing a snapshot to stdout every 15s instead - for
+// headless/scripted runs that want metrics without an HTTP listener.
+func (m *Model) SetMetricsStdout(enabled bool) {
+	m.metricsStdout = enabled
+}
+
+// SetAPIStatus records that the local control API is listening on port,
+// for the header's "API :port" indicator.
+func (m *Model) SetAPIStatus(port int) {
+	m.apiPort = port
+}
+
+// SetRemoteSession marks this Model as belonging to an SSH session (see
+// `lazycap serve`) and redirects its log export path to exportDir instead
+// of the shared server-local os.TempDir, so concurrent sessions don't
+// collide or leak each other's logs.
+func (m *Model) SetRemoteSession(exportDir string) {
+	m.remoteSession = true
+	m.exportDir = exportDir
 }
 
 type keyMap struct {
-	Up        key.Binding
-	Down      key.Binding
-	Tab       key.Binding
-	Run       key.Binding
-	Sync      key.Binding
-	Build     key.Binding
-	Open      key.Binding
-	Kill      key.Binding
-	Refresh   key.Binding
-	Upgrade   key.Binding
-	Help      key.Binding
-	Quit      key.Binding
-	Left      key.Binding
-	Right     key.Binding
-	Copy      key.Binding
-	Export    key.Binding
-	Preflight key.Binding
-	Settings  key.Binding
-	Debug     key.Binding
-	Plugins   key.Binding
-	Enter     key.Binding
+	Up          key.Binding
+	Down        key.Binding
+	Tab         key.Binding
+	Run         key.Binding
+	Sync        key.Binding
+	Build       key.Binding
+	Open        key.Binding
+	Kill        key.Binding
+	Refresh     key.Binding
+	Upgrade     key.Binding
+	Help        key.Binding
+	Quit        key.Binding
+	Left        key.Binding
+	Right       key.Binding
+	Copy        key.Binding
+	Export      key.Binding
+	Preflight   key.Binding
+	Settings    key.Binding
+	Debug       key.Binding
+	Plugins     key.Binding
+	Enter       key.Binding
+	Select      key.Binding
+	Mirror      key.Binding
+	NextError   key.Binding
+	PrevError   key.Binding
+	Filter      key.Binding
+	OpenRef     key.Binding
+	LiveReload  key.Binding
+	Detach      key.Binding
+	AutoRestart key.Binding
+	SendInput   key.Binding
 }
 
 func defaultKeyMap() keyMap {
 	return keyMap{
-		Up:        key.NewBinding(key.WithKeys("up", "k"), key.WithHelp("↑/k", "up")),
-		Down:      key.NewBinding(key.WithKeys("down", "j"), key.WithHelp("↓/j", "down")),
-		Tab:       key.NewBinding(key.WithKeys("tab"), key.WithHelp("tab", "switch pane")),
-		Run:       key.NewBinding(key.WithKeys("r"), key.WithHelp("r", "run")),
-		Sync:      key.NewBinding(key.WithKeys("s"), key.WithHelp("s", "sync")),
-		Build:     key.NewBinding(key.WithKeys("b"), key.WithHelp("b", "build")),
-		Open:      key.NewBinding(key.WithKeys("o"), key.WithHelp("o", "open IDE")),
-		Kill:      key.NewBinding(key.WithKeys("x"), key.WithHelp("x", "kill")),
-		Refresh:   key.NewBinding(key.WithKeys("R"), key.WithHelp("R", "refresh")),
-		Upgrade:   key.NewBinding(key.WithKeys("u"), key.WithHelp("u", "upgrade")),
-		Help:      key.NewBinding(key.WithKeys("?"), key.WithHelp("?", "help")),
-		Quit:      key.NewBinding(key.WithKeys("q", "ctrl+c"), key.WithHelp("q", "quit")),
-		Left:      key.NewBinding(key.WithKeys("left", "h"), key.WithHelp("←", "prev tab")),
-		Right:     key.NewBinding(key.WithKeys("right", "l"), key.WithHelp("→", "next tab")),
-		Copy:      key.NewBinding(key.WithKeys("c"), key.WithHelp("c", "copy logs")),
-		Export:    key.NewBinding(key.WithKeys("e"), key.WithHelp("e", "export logs")),
-		Preflight: key.NewBinding(key.WithKeys("p"), key.WithHelp("p", "preflight")),
-		Settings:  key.NewBinding(key.WithKeys(","), key.WithHelp(",", "settings")),
-		Debug:     key.NewBinding(key.WithKeys("d"), key.WithHelp("d", "debug")),
-		Plugins:   key.NewBinding(key.WithKeys("P"), key.WithHelp("P", "plugins")),
-		Enter:     key.NewBinding(key.WithKeys("enter", " "), key.WithHelp("enter", "toggle")),
+		Up:          key.NewBinding(key.WithKeys("up", "k"), key.WithHelp("↑/k", "up")),
+		Down:        key.NewBinding(key.WithKeys("down", "j"), key.WithHelp("↓/j", "down")),
+		Tab:         key.NewBinding(key.WithKeys("tab"), key.WithHelp("tab", "switch pane")),
+		Run:         key.NewBinding(key.WithKeys("r"), key.WithHelp("r", "run")),
+		Sync:        key.NewBinding(key.WithKeys("s"), key.WithHelp("s", "sync")),
+		Build:       key.NewBinding(key.WithKeys("b"), key.WithHelp("b", "build")),
+		Open:        key.NewBinding(key.WithKeys("o"), key.WithHelp("o", "open IDE")),
+		Kill:        key.NewBinding(key.WithKeys("x"), key.WithHelp("x", "kill")),
+		Refresh:     key.NewBinding(key.WithKeys("R"), key.WithHelp("R", "refresh")),
+		Upgrade:     key.NewBinding(key.WithKeys("u"), key.WithHelp("u", "upgrade")),
+		Help:        key.NewBinding(key.WithKeys("?"), key.WithHelp("?", "help")),
+		Quit:        key.NewBinding(key.WithKeys("q", "ctrl+c"), key.WithHelp("q", "quit")),
+		Left:        key.NewBinding(key.WithKeys("left", "h"), key.WithHelp("←", "prev tab")),
+		Right:       key.NewBinding(key.WithKeys("right", "l"), key.WithHelp("→", "next tab")),
+		Copy:        key.NewBinding(key.WithKeys("c"), key.WithHelp("c", "copy logs")),
+		Export:      key.NewBinding(key.WithKeys("e"), key.WithHelp("e", "export logs")),
+		Preflight:   key.NewBinding(key.WithKeys("p"), key.WithHelp("p", "preflight")),
+		Settings:    key.NewBinding(key.WithKeys(","), key.WithHelp(",", "settings")),
+		Debug:       key.NewBinding(key.WithKeys("d"), key.WithHelp("d", "debug")),
+		Plugins:     key.NewBinding(key.WithKeys("P"), key.WithHelp("P", "plugins")),
+		Enter:       key.NewBinding(key.WithKeys("enter", " "), key.WithHelp("enter", "toggle")),
+		Select:      key.NewBinding(key.WithKeys(" "), key.WithHelp("space", "select for mirror")),
+		Mirror:      key.NewBinding(key.WithKeys("m"), key.WithHelp("m", "mirrored run")),
+		NextError:   key.NewBinding(key.WithKeys("n"), key.WithHelp("n", "next error")),
+		PrevError:   key.NewBinding(key.WithKeys("N"), key.WithHelp("N", "prev error")),
+		Filter:      key.NewBinding(key.WithKeys("f"), key.WithHelp("f", "filter severity")),
+		OpenRef:     key.NewBinding(key.WithKeys("enter"), key.WithHelp("enter", "open file ref")),
+		LiveReload:  key.NewBinding(key.WithKeys("L"), key.WithHelp("L", "live-reload URL/QR")),
+		Detach:      key.NewBinding(key.WithKeys("D"), key.WithHelp("D", "detach & quit")),
+		AutoRestart: key.NewBinding(key.WithKeys("a"), key.WithHelp("a", "toggle auto-restart")),
+		SendInput:   key.NewBinding(key.WithKeys("!"), key.WithHelp("!", "send input to pty")),
 	}
 }
 
@@ -220,7 +415,10 @@ func (k keyMap) FullHelp() [][]key.Binding {
 		{k.Up, k.Down, k.Tab},
 		{k.Run, k.Sync, k.Build},
 		{k.Open, k.Kill, k.Refresh},
-		{k.Help, k.Quit},
+		{k.Select, k.Mirror},
+		{k.NextError, k.PrevError, k.Filter, k.OpenRef},
+		{k.LiveReload, k.Help, k.Quit},
+		{k.Detach, k.AutoRestart, k.SendInput},
 	}
 }
 
@@ -240,25 +438,76 @@ func NewModelWithPlugins(project *cap.Project, pluginMgr *plugin.Manager, appCtx
 
 	// Load settings
 	userSettings, _ := settings.Load()
+	i18n.SetLanguage(i18n.DetectLanguage(userSettings.GetString("language")))
+	name := userSettings.GetString("theme")
+	if ThemeOverride != "" {
+		name = ThemeOverride
+	}
+	if name != "" {
+		if dir, err := settings.StylesetsDir(); err == nil {
+			if ss, err := theme.Load(name, dir); err == nil {
+				applyTheme(ss)
+			}
+		}
+	}
 
 	m := Model{
-		project:          project,
-		focus:            FocusDevices,
-		spinner:          s,
-		logViewport:      viewport.New(0, 0),
-		help:             help.New(),
-		keys:             defaultKeyMap(),
-		loading:          true,
-		processes:        make([]*Process, 0),
-		outputChans:      make(map[string]chan string),
-		nextProcessID:    1,
-		preflightResults: preflightResults,
-		showPreflight:    preflightResults.HasErrors, // Show automatically if errors
-		settings:         userSettings,
-		pluginManager:    pluginMgr,
-		pluginContext:    appCtx,
+		project:            project,
+		focus:              FocusDevices,
+		spinner:            s,
+		logViewport:        viewport.New(0, 0),
+		help:               help.New(),
+		keys:               defaultKeyMap(),
+		loading:            true,
+		processes:          make([]*Process, 0),
+		outputChans:        make(map[string]chan string),
+		selectedDevices:    make(map[int]bool),
+		nextProcessID:      1,
+		logCursor:          -1,
+		preflightResults:   preflightResults,
+		showPreflight:      preflightResults.HasErrors, // Show automatically if errors
+		settings:           userSettings,
+		pluginManager:      pluginMgr,
+		pluginContext:      appCtx,
+		metrics:            metrics.NewRegistry(),
+		pluginActionStatus: make(map[string]string),
+		debugJobs:          make(map[string]*debugJob),
+	}
+
+	// Reattach any sessions left running (or finished) by a previous
+	// lazycap invocation against this project, before the first tea.Msg
+	// ever arrives.
+	m.reattachCmds = m.reattachSessions()
+
+	// Watch config.yml for edits made outside the TUI (e.g. from an
+	// editor) so they're picked up live - see watchSettingsReload and the
+	// settingsReloadedMsg handler. Not fatal if it fails to start (e.g.
+	// ConfigDir can't be resolved); the settings just won't hot-reload.
+	if watcher, changeCh, err := settings.Watch(); err == nil {
+		m.settingsWatcher = watcher
+		m.settingsChangeCh = changeCh
 	}
 
+	// Start the persistent job queue. Not fatal if it fails to open (e.g.
+	// ConfigDir can't be resolved) - m.jobQueue stays nil and queue-backed
+	// features (see waitJobReady) are simply unavailable for this run.
+	if store, err := jobqueue.Open(); err == nil {
+		m.jobQueue = store
+		m.jobScheduler = jobqueue.NewScheduler(store, 5*time.Second)
+		m.jobScheduler.Start()
+	}
+
+	// Serve Prometheus metrics if configured. Runs for the lifetime of
+	// the process; there's no explicit teardown (same as the pty/log
+	// reader goroutines elsewhere in this file), since the listener dies
+	// with the process anyway.
+	if port := userSettings.GetInt("metricsPort"); port > 0 {
+		go m.metrics.Serve(fmt.Sprintf(":%d", port))
+	}
+
+	m.recordPreflightHistory(preflightResults)
+	m.updatePreflightMetrics()
+
 	// Set up plugin context callbacks if plugins are enabled
 	if appCtx != nil {
 		appCtx.SetSettings(userSettings)
@@ -341,6 +590,7 @@ func NewModelWithPlugins(project *cap.Project, pluginMgr *plugin.Manager, appCtx
 				m.addLog(fmt.Sprintf("[%s] %s", source, message))
 			},
 		)
+		m.notifyPreflightUpdated()
 	}
 
 	return m
@@ -561,11 +811,16 @@ func NewDemoModel(project *cap.Project, pluginMgr *plugin.Manager, appCtx *plugi
 // Messages
 type devicesLoadedMsg struct{ devices []device.Device }
 type upgradeCheckedMsg struct{ info *cap.UpgradeInfo }
+type metricsStdoutTickMsg struct{}
+type preflightTickMsg struct{ results *preflight.Results }
 type errMsg struct{ err error }
 type processStartedMsg struct {
 	processID  string
 	cmd        *exec.Cmd
 	outputChan chan string
+	// ptyFile is the pty master this process was started with, if any
+	// (see runCmdWithPTYOrPipes). Nil for pipe-backed processes.
+	ptyFile *os.File
 }
 type processOutputMsg struct {
 	processID string
@@ -575,11 +830,79 @@ type processFinishedMsg struct {
 	processID string
 	err       error
 }
+type processRestartingMsg struct {
+	processID   string
+	delay       time.Duration
+	retriesLeft int
+	gen         int
+}
+type settingsReloadedMsg struct{ settings *settings.Settings }
 type deviceBootedMsg struct {
 	device     *device.Device
 	liveReload bool
 	err        error
 }
+type marketplaceLoadedMsg struct {
+	entries []plugin.RegistryEntry
+	err     error
+}
+type pluginActionMsg struct {
+	pluginID string
+	action   string // "install", "update", "remove"
+	err      error
+}
+type updateAppliedMsg struct {
+	version string
+	err     error
+}
+type preflightFixResultMsg struct {
+	checkName string
+	result    preflight.CheckResult
+	err       error
+}
+type debugJobStartedMsg struct {
+	actionID   string
+	cancel     context.CancelFunc
+	progressCh <-chan debug.GenericProgress
+	resultCh   <-chan debug.Result
+}
+type debugProgressMsg struct {
+	actionID string
+	progress debug.GenericProgress
+	ch       <-chan debug.GenericProgress
+}
+type debugJobDoneMsg struct {
+	actionID string
+	result   debug.Result
+}
+type jobReadyMsg struct {
+	job *jobqueue.Job
+}
+type processProgressMsg struct {
+	processID string
+	snapshot  ProgressSnapshot
+	ch        <-chan ProgressSnapshot
+}
+
+// settingsFilterResult is one flattened, scored hit produced by filtering
+// settings.GetCategories() against m.settingsFilterInput's query - see
+// filterSettings.
+type settingsFilterResult struct {
+	categoryIdx int
+	settingIdx  int
+	category    string
+	setting     settings.Setting
+	score       int
+}
+
+// debugFilterResult mirrors settingsFilterResult for the debug panel's "/"
+// search - see filterDebugActions.
+type debugFilterResult struct {
+	categoryIdx int
+	category    string
+	action      debug.Action
+	score       int
+}
 
 // Commands
 func loadDevices() tea.Msg {
@@ -595,6 +918,253 @@ func checkUpgrade() tea.Msg {
 	return upgradeCheckedMsg{info}
 }
 
+// metricsStdoutTick schedules the next --metrics-stdout snapshot print,
+// 15s out.
+func metricsStdoutTick() tea.Cmd {
+	return tea.Tick(15*time.Second, func(time.Time) tea.Msg { return metricsStdoutTickMsg{} })
+}
+
+// preflightWatchTick schedules the next background preflight re-check,
+// interval out. Re-running preflight.Run() shells out to several CLI
+// tools (same as the initial check in NewModelWithPlugins), so this runs
+// in its own goroutine via tea.Tick rather than blocking Update.
+func preflightWatchTick(interval time.Duration) tea.Cmd {
+	return tea.Tick(interval, func(time.Time) tea.Msg {
+		return preflightTickMsg{results: preflight.Run()}
+	})
+}
+
+// loadMarketplace fetches the plugin marketplace manifest via m's
+// pluginRegistry (created lazily by handlePluginsInput the first time the
+// Available tab is opened).
+func loadMarketplace(reg *plugin.Registry, query string) tea.Cmd {
+	return func() tea.Msg {
+		if err := reg.Refresh(); err != nil {
+			return marketplaceLoadedMsg{err: err}
+		}
+		return marketplaceLoadedMsg{entries: reg.Search(query)}
+	}
+}
+
+// installPluginCmd downloads entry via reg and asks m's pluginManager to
+// install it from the downloaded path (see the Manager.Install reference
+// in internal/plugin/context_impl.go - Manager itself lives outside this
+// tree's snapshot). On success it notifies ctx so the rest of the app
+// (logging, notify backends) can react to EventPluginInstalled; ctx may be
+// nil (e.g. in tests), in which case the notification is skipped.
+func installPluginCmd(reg *plugin.Registry, mgr *plugin.Manager, ctx *plugin.AppContext, entry plugin.RegistryEntry) tea.Cmd {
+	return func() tea.Msg {
+		dir, err := plugin.PluginDir()
+		if err != nil {
+			return pluginActionMsg{pluginID: entry.ID, action: "install", err: err}
+		}
+		path, err := reg.Download(entry, dir)
+		if err != nil {
+			return pluginActionMsg{pluginID: entry.ID, action: "install", err: err}
+		}
+		err = mgr.Install(entry.ID, path)
+		if err == nil && ctx != nil {
+			ctx.NotifyPluginInstalled(entry.ID)
+		}
+		return pluginActionMsg{pluginID: entry.ID, action: "install", err: err}
+	}
+}
+
+// updatePluginCmd re-downloads entry and asks mgr to update the
+// already-installed plugin in place, notifying ctx the same way
+// installPluginCmd does (there's no separate EventPluginUpdated - an
+// update replaces the installed plugin in place, so it's announced the
+// same as a fresh install).
+func updatePluginCmd(reg *plugin.Registry, mgr *plugin.Manager, ctx *plugin.AppContext, entry plugin.RegistryEntry) tea.Cmd {
+	return func() tea.Msg {
+		dir, err := plugin.PluginDir()
+		if err != nil {
+			return pluginActionMsg{pluginID: entry.ID, action: "update", err: err}
+		}
+		path, err := reg.Download(entry, dir)
+		if err != nil {
+			return pluginActionMsg{pluginID: entry.ID, action: "update", err: err}
+		}
+		err = mgr.Update(entry.ID, path)
+		if err == nil && ctx != nil {
+			ctx.NotifyPluginInstalled(entry.ID)
+		}
+		return pluginActionMsg{pluginID: entry.ID, action: "update", err: err}
+	}
+}
+
+// updateAllPluginsCmd updates every installed plugin that also appears in
+// the marketplace manifest, batched as a single status update rather than
+// one pluginActionMsg per plugin. Each successful update still gets its
+// own notification to ctx, same as updatePluginCmd.
+func updateAllPluginsCmd(reg *plugin.Registry, mgr *plugin.Manager, ctx *plugin.AppContext, entries []plugin.RegistryEntry) tea.Cmd {
+	return func() tea.Msg {
+		dir, err := plugin.PluginDir()
+		if err != nil {
+			return pluginActionMsg{action: "update-all", err: err}
+		}
+		var lastErr error
+		for _, entry := range entries {
+			path, err := reg.Download(entry, dir)
+			if err != nil {
+				lastErr = err
+				continue
+			}
+			if err := mgr.Update(entry.ID, path); err != nil {
+				lastErr = err
+				continue
+			}
+			if ctx != nil {
+				ctx.NotifyPluginInstalled(entry.ID)
+			}
+		}
+		return pluginActionMsg{action: "update-all", err: lastErr}
+	}
+}
+
+// removePluginCmd asks mgr to uninstall the given plugin ID, notifying ctx
+// on success so it can publish EventPluginRemoved.
+func removePluginCmd(mgr *plugin.Manager, ctx *plugin.AppContext, pluginID string) tea.Cmd {
+	return func() tea.Msg {
+		err := mgr.Remove(pluginID)
+		if err == nil && ctx != nil {
+			ctx.NotifyPluginRemoved(pluginID)
+		}
+		return pluginActionMsg{pluginID: pluginID, action: "remove", err: err}
+	}
+}
+
+// applyUpdateCmd asks ctx to run the self-update pipeline for info (see
+// AppContext.ApplyUpdate) - the "press U to update" flow from the
+// preflight panel's version check.
+func applyUpdateCmd(ctx *plugin.AppContext, info *update.Info) tea.Cmd {
+	return func() tea.Msg {
+		err := ctx.ApplyUpdate(info)
+		return updateAppliedMsg{version: info.LatestVersion, err: err}
+	}
+}
+
+// runPreflightFixCmd asks ctx to run the registered Fixer for checkName
+// (see AppContext.RunPreflightFix) - the "f" key on a preflight check with
+// an automated Fix attached.
+func runPreflightFixCmd(ctx *plugin.AppContext, checkName string) tea.Cmd {
+	return func() tea.Msg {
+		result, err := ctx.RunPreflightFix(checkName)
+		return preflightFixResultMsg{checkName: checkName, result: result, err: err}
+	}
+}
+
+// debugJob tracks one in-flight debug.RunActionAsync invocation: its
+// progress bar, the last progress update received, and the cancel func
+// handleDebugInput's "c" key sends a context cancellation through.
+type debugJob struct {
+	bar     progress.Model
+	current int
+	total   int
+	message string
+	cancel  context.CancelFunc
+}
+
+// startDebugAction launches action via debug.RunActionAsync on its own
+// context, so handleDebugInput can cancel it independently of every other
+// job in m.debugJobs.
+func startDebugAction(actionID string) tea.Cmd {
+	return func() tea.Msg {
+		ctx, cancel := context.WithCancel(context.Background())
+		progressCh, resultCh := debug.RunActionAsync(ctx, actionID)
+		return debugJobStartedMsg{actionID: actionID, cancel: cancel, progressCh: progressCh, resultCh: resultCh}
+	}
+}
+
+// waitDebugProgress re-queues itself after every progress event so Update
+// keeps draining ch until it closes (mirrors waitForOutput).
+func waitDebugProgress(actionID string, ch <-chan debug.GenericProgress) tea.Cmd {
+	return func() tea.Msg {
+		p, ok := <-ch
+		if !ok {
+			return nil
+		}
+		return debugProgressMsg{actionID: actionID, progress: p, ch: ch}
+	}
+}
+
+// waitDebugResult blocks for the single terminal Result debug.RunActionAsync
+// sends once the action finishes (or is cancelled).
+func waitDebugResult(actionID string, ch <-chan debug.Result) tea.Cmd {
+	return func() tea.Msg {
+		result, ok := <-ch
+		if !ok {
+			return nil
+		}
+		return debugJobDoneMsg{actionID: actionID, result: result}
+	}
+}
+
+// waitProcessProgress blocks on a Process's Progress.Subscribe channel,
+// re-queuing itself after every change so the TUI redraws exactly when
+// there's something new to show (see Progress.notify) instead of on every
+// log line.
+func waitProcessProgress(processID string, ch <-chan ProgressSnapshot) tea.Cmd {
+	return func() tea.Msg {
+		snap, ok := <-ch
+		if !ok {
+			return nil
+		}
+		return processProgressMsg{processID: processID, snapshot: snap, ch: ch}
+	}
+}
+
+// waitJobReady blocks on the job queue scheduler's Ready channel, re-queuing
+// itself after every promoted job so Update keeps picking them up for the
+// rest of the program's life (see the jobReadyMsg handler).
+func waitJobReady(sched *jobqueue.Scheduler) tea.Cmd {
+	return func() tea.Msg {
+		job, ok := <-sched.Ready()
+		if !ok {
+			return nil
+		}
+		return jobReadyMsg{job: job}
+	}
+}
+
+// preflightHistoryLen caps how many past statuses are kept per check, used
+// to distinguish a flapping check from one that's consistently failing.
+const preflightHistoryLen = 5
+
+// recordPreflightHistory appends each check's current status to its
+// history, trimmed to preflightHistoryLen entries (oldest first).
+func (m *Model) recordPreflightHistory(results *preflight.Results) {
+	if m.preflightHistory == nil {
+		m.preflightHistory = make(map[string][]preflight.Status)
+	}
+	for _, check := range results.Checks {
+		hist := append(m.preflightHistory[check.Name], check.Status)
+		if len(hist) > preflightHistoryLen {
+			hist = hist[len(hist)-preflightHistoryLen:]
+		}
+		m.preflightHistory[check.Name] = hist
+	}
+}
+
+// preflightFlapping reports whether any check's recent history contains
+// both OK and non-OK statuses, as opposed to consistently failing.
+func (m *Model) preflightFlapping() bool {
+	for _, hist := range m.preflightHistory {
+		sawOK, sawBad := false, false
+		for _, s := range hist {
+			if s == preflight.StatusOK {
+				sawOK = true
+			} else {
+				sawBad = true
+			}
+		}
+		if sawOK && sawBad {
+			return true
+		}
+	}
+	return false
+}
+
 func (m *Model) getSelectedDevice() *device.Device {
 	if len(m.devices) == 0 || m.selectedDevice >= len(m.devices) {
 		return nil
@@ -611,7 +1181,7 @@ func (m *Model) getSelectedProcess() *Process {
 
 func (m *Model) hasRunningProcesses() bool {
 	for _, p := range m.processes {
-		if p.Status == ProcessRunning {
+		if p.Status == ProcessRunning || p.Status == ProcessBackoff {
 			return true
 		}
 	}
@@ -628,6 +1198,23 @@ func waitForOutput(processID string, ch chan string) tea.Cmd {
 	}
 }
 
+// watchSettingsReload blocks on a settings.Watcher's change channel and,
+// once it fires, reloads config.yml from disk and reports it as a
+// settingsReloadedMsg for Update to diff against the in-memory settings.
+// Update re-queues this after each fire to keep listening.
+func watchSettingsReload(ch <-chan struct{}) tea.Cmd {
+	return func() tea.Msg {
+		if _, ok := <-ch; !ok {
+			return nil
+		}
+		s, err := settings.Load()
+		if err != nil {
+			return nil
+		}
+		return settingsReloadedMsg{settings: s}
+	}
+}
+
 func bootDevice(dev *device.Device, liveReload bool) tea.Cmd {
 	return func() tea.Msg {
 		if err := cap.BootDevice(dev.ID, dev.Platform, dev.IsEmulator); err != nil {
@@ -657,16 +1244,40 @@ func (m *Model) gracefulShutdown() {
 	if m.pluginManager != nil {
 		m.pluginManager.StopAll()
 	}
+
+	// Stop the settings file watcher
+	if m.settingsWatcher != nil {
+		m.settingsWatcher.Stop()
+	}
+
+	// Stop the job queue scheduler
+	if m.jobScheduler != nil {
+		m.jobScheduler.Stop()
+	}
 }
 
 // Init starts the app
 func (m Model) Init() tea.Cmd {
-	return tea.Batch(
+	cmds := []tea.Cmd{
 		loadDevices,
 		checkUpgrade,
 		m.spinner.Tick,
 		setTerminalTitle(m.getTerminalTitle()),
-	)
+	}
+	cmds = append(cmds, m.reattachCmds...)
+	if m.settingsChangeCh != nil {
+		cmds = append(cmds, watchSettingsReload(m.settingsChangeCh))
+	}
+	if m.metricsStdout {
+		cmds = append(cmds, metricsStdoutTick())
+	}
+	if interval := m.settings.GetInt("preflightWatchSeconds"); interval > 0 {
+		cmds = append(cmds, preflightWatchTick(time.Duration(interval)*time.Second))
+	}
+	if m.jobScheduler != nil {
+		cmds = append(cmds, waitJobReady(m.jobScheduler))
+	}
+	return tea.Batch(cmds...)
 }
 
 // Update handles all messages
@@ -682,6 +1293,12 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			}
 		}
 
+		// Handle PTY input mode: keystrokes go straight to the process
+		// instead of being interpreted as lazycap shortcuts.
+		if m.ptyInputMode {
+			return m.handlePTYInput(msg)
+		}
+
 		// Handle settings mode input
 		if m.showSettings {
 			return m.handleSettingsInput(msg)
@@ -697,6 +1314,16 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			return m.handlePluginsInput(msg)
 		}
 
+		// Handle preflight panel input
+		if m.showPreflight {
+			return m.handlePreflightInput(msg)
+		}
+
+		// Handle live-reload URL/QR overlay input
+		if m.showLiveReload {
+			return m.handleLiveReloadInput(msg)
+		}
+
 		switch {
 		case key.Matches(msg, m.keys.Quit):
 			// Check if Ctrl+C (force quit)
@@ -722,12 +1349,25 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				}
 			}
 			if running > 0 {
-				m.setStatus(fmt.Sprintf("⚠ %d process running! Press q again to quit", running))
+				m.setStatus(fmt.Sprintf("⚠ %d process running! Press q again to quit, D to detach & quit", running))
 			} else {
 				m.setStatus("Press q again to quit")
 			}
 			return m, nil
 
+		case key.Matches(msg, m.keys.Detach):
+			// Detach & quit: leave running processes alone (they keep
+			// running, and their session.json entries stay "running") so
+			// the next lazycap launch against this project reattaches
+			// them, instead of killing them like a regular quit does.
+			if m.confirmQuit && time.Since(m.quitTime) < 3*time.Second {
+				if m.pluginManager != nil {
+					m.pluginManager.StopAll()
+				}
+				return m, tea.Quit
+			}
+			return m, nil
+
 		case key.Matches(msg, m.keys.Help):
 			m.showHelp = !m.showHelp
 			m.showPreflight = false
@@ -737,6 +1377,8 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			m.showPreflight = !m.showPreflight
 			m.showHelp = false
 			m.showSettings = false
+			m.preflightCursor = 0
+			m.preflightConfirm = false
 			return m, nil
 
 		case key.Matches(msg, m.keys.Settings):
@@ -770,6 +1412,16 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			m.pluginCursor = 0
 			return m, nil
 
+		case key.Matches(msg, m.keys.LiveReload):
+			m.showLiveReload = !m.showLiveReload
+			m.showHelp = false
+			m.showPreflight = false
+			m.showSettings = false
+			m.showDebug = false
+			m.showPlugins = false
+			m.liveReloadProcIdx = 0
+			return m, nil
+
 		case key.Matches(msg, m.keys.Tab):
 			if m.focus == FocusDevices {
 				m.focus = FocusLogs
@@ -801,6 +1453,7 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		case key.Matches(msg, m.keys.Left):
 			if m.focus == FocusLogs && m.selectedProcess > 0 {
 				m.selectedProcess--
+				m.logCursor = -1
 				m.updateLogViewport()
 			}
 			return m, nil
@@ -808,10 +1461,25 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		case key.Matches(msg, m.keys.Right):
 			if m.focus == FocusLogs && m.selectedProcess < len(m.processes)-1 {
 				m.selectedProcess++
+				m.logCursor = -1
 				m.updateLogViewport()
 			}
 			return m, nil
 
+		case key.Matches(msg, m.keys.Select):
+			if m.focus == FocusDevices && len(m.devices) > 0 {
+				if m.selectedDevices[m.selectedDevice] {
+					delete(m.selectedDevices, m.selectedDevice)
+				} else {
+					m.selectedDevices[m.selectedDevice] = true
+				}
+			}
+			return m, nil
+
+		case key.Matches(msg, m.keys.Mirror):
+			liveReload := m.settings.GetBool("liveReloadDefault")
+			return m, m.startMirrorRun(liveReload)
+
 		case key.Matches(msg, m.keys.Run):
 			// Use live reload setting
 			liveReload := m.settings.GetBool("liveReloadDefault")
@@ -832,25 +1500,43 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		case key.Matches(msg, m.keys.Kill):
 			p := m.getSelectedProcess()
 			if p != nil && p.Status == ProcessRunning && p.Cmd != nil && p.Cmd.Process != nil {
-				p.Cmd.Process.Kill()
+				m.killSubtree(p) // cascades to p.Children and p's own process group
+				m.updateLogViewport()
+			} else if p != nil && p.Status == ProcessBackoff {
+				p.restartGen++ // abandon the pending restart
 				p.Status = ProcessCancelled
 				p.EndTime = time.Now()
-				p.AddLog("Killed by user")
+				p.AddLog("Cancelled pending restart")
 				m.updateLogViewport()
 			}
+			m.updateProcessMetrics()
+			return m, nil
+
+		case key.Matches(msg, m.keys.AutoRestart):
+			m.toggleAutoRestart()
+			return m, nil
+
+		case key.Matches(msg, m.keys.SendInput):
+			p := m.getSelectedProcess()
+			if p == nil || p.ptyMaster == nil {
+				m.setStatus("No pty to send input to")
+				return m, nil
+			}
+			m.ptyInputMode = true
+			m.setStatus("Sending input to " + p.Name + " - Esc to stop")
 			return m, nil
 
 		case key.Matches(msg, m.keys.Copy):
 			p := m.getSelectedProcess()
 			if p != nil && len(p.Logs) > 0 {
 				content := strings.Join(p.Logs, "\n")
-				if err := clipboard.WriteAll(content); err != nil {
-					m.setStatus("Copy failed: " + err.Error())
+				if err := m.copyToClipboard(content); err != nil {
+					m.setStatus(i18n.T("status.copy_failed", err.Error()))
 				} else {
-					m.setStatus(fmt.Sprintf("Copied %d lines to clipboard", len(p.Logs)))
+					m.setStatus(i18n.T("status.copy_success", len(p.Logs)))
 				}
 			} else {
-				m.setStatus("No logs to copy")
+				m.setStatus(i18n.T("status.no_logs_to_copy"))
 			}
 			return m, nil
 
@@ -858,23 +1544,51 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			p := m.getSelectedProcess()
 			if p != nil && len(p.Logs) > 0 {
 				filename := fmt.Sprintf("lazycap-%s-%s.log", p.Name, time.Now().Format("20060102-150405"))
-				exportPath := filepath.Join(os.TempDir(), filename)
+				exportPath := filepath.Join(m.exportDirOrDefault(), filename)
 				content := strings.Join(p.Logs, "\n")
 				if err := os.WriteFile(exportPath, []byte(content), 0644); err != nil {
-					m.setStatus("Export failed: " + err.Error())
+					m.setStatus(i18n.T("status.export_failed", err.Error()))
 				} else {
-					m.setStatus("Exported to " + exportPath)
+					m.setStatus(i18n.T("status.export_success", exportPath))
 				}
 			} else {
 				m.setStatus("No logs to export")
 			}
 			return m, nil
+
+		case key.Matches(msg, m.keys.Filter):
+			switch {
+			case !m.logFilterActive:
+				m.logFilterActive = true
+				m.logSeverityFilter = logparse.LevelWarn
+			case m.logSeverityFilter == logparse.LevelWarn:
+				m.logSeverityFilter = logparse.LevelError
+			default:
+				m.logFilterActive = false
+			}
+			m.updateLogViewport()
+			m.setStatus("Log filter: " + m.logFilterLabel())
+			return m, nil
+
+		case key.Matches(msg, m.keys.NextError):
+			m.jumpToError(1)
+			return m, nil
+
+		case key.Matches(msg, m.keys.PrevError):
+			m.jumpToError(-1)
+			return m, nil
+
+		case key.Matches(msg, m.keys.OpenRef):
+			if m.focus == FocusLogs {
+				return m, m.openCurrentFileRef()
+			}
 		}
 
 	case tea.WindowSizeMsg:
 		m.width = msg.Width
 		m.height = msg.Height
 		m.updateLayout()
+		m.resizeProcessPTYs()
 
 	case spinner.TickMsg:
 		var cmd tea.Cmd
@@ -883,22 +1597,55 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 
 	case devicesLoadedMsg:
 		m.loading = false
+		m.notifyDeviceChanges(msg.devices)
 		m.devices = msg.devices
+		m.updateDeviceMetrics()
 		cmds = append(cmds, setTerminalTitle(m.getTerminalTitle()))
 
 	case upgradeCheckedMsg:
 		m.upgradeInfo = msg.info
 
+	case metricsStdoutTickMsg:
+		cmds = append(cmds, tea.Println(m.metrics.Render()), metricsStdoutTick())
+
+	case preflightTickMsg:
+		m.preflightResults = msg.results
+		m.recordPreflightHistory(msg.results)
+		m.updatePreflightMetrics()
+		m.notifyPreflightUpdated()
+		if msg.results.HasErrors && !m.showPreflight {
+			m.showPreflight = true
+		}
+		if interval := m.settings.GetInt("preflightWatchSeconds"); interval > 0 {
+			cmds = append(cmds, preflightWatchTick(time.Duration(interval)*time.Second))
+		}
+
 	case processStartedMsg:
 		for _, p := range m.processes {
 			if p.ID == msg.processID {
 				p.Cmd = msg.cmd
 				p.OutputChan = msg.outputChan
+				p.ptyMaster = msg.ptyFile
+				if msg.cmd != nil && msg.cmd.Process != nil {
+					p.PGID = msg.cmd.Process.Pid // setPgid makes the leader its own pgid
+				}
 				m.outputChans[msg.processID] = msg.outputChan
+				m.persistSession(p)
+				if m.pluginContext != nil {
+					m.pluginContext.NotifyProcessStarted(p.ID, p.Name, p.Command)
+				}
+				p.stopSampler = make(chan struct{})
+				p.startResourceSampler(p.stopSampler)
 				break
 			}
 		}
 		cmds = append(cmds, waitForOutput(msg.processID, msg.outputChan), m.spinner.Tick, setTerminalTitle(m.getTerminalTitle()))
+		if p := m.findProcess(msg.processID); p != nil && p.Progress != nil {
+			cmds = append(cmds, waitProcessProgress(p.ID, p.Progress.Subscribe()))
+		}
+
+	case processProgressMsg:
+		cmds = append(cmds, waitProcessProgress(msg.processID, msg.ch))
 
 	case processOutputMsg:
 		for _, p := range m.processes {
@@ -906,6 +1653,17 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				clean := strings.TrimSpace(ansiRegex.ReplaceAllString(msg.line, ""))
 				if clean != "" {
 					p.AddLog(clean)
+					if m.pluginContext != nil {
+						m.pluginContext.AddProcessLog(p.ID, clean)
+					}
+					if g := m.groupForProcess(p.ID); g != nil {
+						if agg := m.findProcess(g.AggregateProcessID); agg != nil {
+							agg.AddLog(fmt.Sprintf("[%s] %s", p.Name, clean))
+							if m.getSelectedProcess() == agg {
+								m.updateLogViewport()
+							}
+						}
+					}
 				}
 				if m.getSelectedProcess() == p {
 					m.updateLogViewport()
@@ -922,20 +1680,101 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		for _, p := range m.processes {
 			if p.ID == msg.processID && p.Status == ProcessRunning {
 				if msg.err != nil {
-					p.Status = ProcessFailed
 					p.AddLog(fmt.Sprintf("Error: %v", msg.err))
 				} else {
-					p.Status = ProcessSuccess
 					p.AddLog("✓ Done")
 				}
 				p.EndTime = time.Now()
+				m.closeSessionLog(p)
+				m.closeLogBuffer(p)
+				p.ptyMaster = nil // already closed by runCmdWithPTYOrPipes
+				if p.stopSampler != nil {
+					close(p.stopSampler)
+					p.stopSampler = nil
+				}
+
+				elapsed := p.EndTime.Sub(p.StartTime)
+				m.metrics.ObserveHistogram("lazycap_process_duration_seconds", elapsed.Seconds())
+				if msg.err != nil {
+					m.metrics.IncCounter("lazycap_process_failures_total", map[string]string{"action": processAction(p)})
+				}
+				switch {
+				case p.AutoRestart && p.RetriesLeft == p.StartRetries &&
+					elapsed < time.Duration(p.StartSeconds)*time.Second:
+					p.Status = ProcessFatal
+					p.AddLog(fmt.Sprintf("Exited after %s (< %ds) on first attempt - not retrying", elapsed.Round(time.Millisecond), p.StartSeconds))
+				case p.AutoRestart && p.RetriesLeft > 0:
+					p.AddLog(fmt.Sprintf("Auto-restarting (%d retries left)", p.RetriesLeft))
+					cmds = append(cmds, m.scheduleRestart(p))
+				case p.AutoRestart:
+					p.Status = ProcessFatal
+					p.AddLog("Exhausted auto-restart retries")
+				case msg.err != nil:
+					p.Status = ProcessFailed
+				default:
+					p.Status = ProcessSuccess
+				}
+
+				if p.JobID != "" && m.jobQueue != nil {
+					if msg.err != nil {
+						m.jobQueue.MarkFailed(p.JobID, msg.err)
+					} else {
+						m.jobQueue.MarkDone(p.JobID)
+					}
+				}
+
+				m.persistSession(p)
+				if p.Live != nil {
+					session.Shared().Release(p.liveKey, p.Live)
+					p.Live = nil
+				}
+				if m.pluginContext != nil {
+					m.pluginContext.NotifyProcessFinished(p.ID, p.Status == ProcessSuccess, msg.err)
+				}
+				m.notifyProcessOutcome(p, msg.err)
 				break
 			}
 		}
 		delete(m.outputChans, msg.processID)
+		m.updateProcessMetrics()
+		if msg.processID == m.remediationProcID {
+			m.remediationProcID = ""
+			m.preflightResults = preflight.Run()
+			m.recordPreflightHistory(m.preflightResults)
+			m.updatePreflightMetrics()
+			m.notifyPreflightUpdated()
+		}
 		m.updateLogViewport()
 		cmds = append(cmds, setTerminalTitle(m.getTerminalTitle()))
 
+	case processRestartingMsg:
+		if p := m.findProcess(msg.processID); p != nil && p.restartGen == msg.gen {
+			m.setStatus(fmt.Sprintf("%s restarting in %s (%d retries left)", p.Name, msg.delay.Round(time.Second), msg.retriesLeft))
+		}
+
+	case settingsReloadedMsg:
+		changed := diffSettings(m.settings, msg.settings)
+		m.settings = msg.settings
+		i18n.SetLanguage(i18n.DetectLanguage(m.settings.GetString("language")))
+		for _, key := range changed {
+			if key == "theme" {
+				m.applyThemeByName(m.settings.GetString("theme"))
+			}
+		}
+		if m.pluginContext != nil {
+			m.pluginContext.SetSettings(m.settings)
+		}
+		if len(changed) > 0 {
+			status := fmt.Sprintf("Settings reloaded (%s changed)", strings.Join(changed, ", "))
+			if webSettingsChanged(changed) && m.findProcessByName("Web") != nil {
+				status += " - kill (x) and re-run (r) Web to apply"
+			}
+			m.setStatus(status)
+		}
+		if m.settingsChangeCh != nil {
+			cmds = append(cmds, watchSettingsReload(m.settingsChangeCh))
+		}
+
 	case deviceBootedMsg:
 		if msg.err != nil {
 			m.addLog(fmt.Sprintf("Boot failed: %v", msg.err))
@@ -947,19 +1786,117 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				break
 			}
 		}
+		m.updateDeviceMetrics()
 		return m, m.startRunCommand(msg.device, msg.liveReload)
 
-	case errMsg:
-		m.loading = false
-		m.addLog(fmt.Sprintf("Error: %v", msg.err))
-	}
-
-	if m.hasRunningProcesses() && len(cmds) == 0 {
-		cmds = append(cmds, m.spinner.Tick)
-	}
+	case marketplaceLoadedMsg:
+		m.marketplaceLoading = false
+		if msg.err != nil {
+			m.setStatus(fmt.Sprintf("Failed to load plugin marketplace: %v", msg.err))
+			return m, nil
+		}
+		m.marketplaceEntries = msg.entries
+		m.marketplaceCursor = 0
 
-	return m, tea.Batch(cmds...)
-}
+	case pluginActionMsg:
+		delete(m.pluginActionStatus, msg.pluginID)
+		if msg.err != nil {
+			m.setStatus(fmt.Sprintf("Plugin %s failed: %v", msg.action, msg.err))
+		} else {
+			switch msg.action {
+			case "update-all":
+				m.setStatus("Updated all plugins")
+			case "install":
+				m.setStatus(fmt.Sprintf("Installed %s", msg.pluginID))
+			case "update":
+				m.setStatus(fmt.Sprintf("Updated %s", msg.pluginID))
+			case "remove":
+				m.setStatus(fmt.Sprintf("Removed %s", msg.pluginID))
+			}
+		}
+
+	case updateAppliedMsg:
+		if msg.err != nil {
+			m.setStatus(fmt.Sprintf("Update to v%s failed: %v", msg.version, msg.err))
+		} else {
+			m.setStatus(fmt.Sprintf("Installed v%s — restart lazycap to use it", msg.version))
+			m.preflightResults = preflight.Run()
+			m.recordPreflightHistory(m.preflightResults)
+			m.updatePreflightMetrics()
+		}
+
+	case preflightFixResultMsg:
+		if msg.err != nil {
+			m.setStatus(fmt.Sprintf("Fix failed for %s: %v", msg.checkName, msg.err))
+		} else {
+			for i, c := range m.preflightResults.Checks {
+				if c.Name == msg.checkName {
+					m.preflightResults.Checks[i] = msg.result
+					break
+				}
+			}
+			m.recordPreflightHistory(m.preflightResults)
+			m.updatePreflightMetrics()
+			m.setStatus("Fixed " + msg.checkName)
+		}
+
+	case debugJobStartedMsg:
+		m.debugJobs[msg.actionID] = &debugJob{bar: progress.New(progress.WithDefaultGradient())}
+		cmds = append(cmds, waitDebugProgress(msg.actionID, msg.progressCh), waitDebugResult(msg.actionID, msg.resultCh))
+
+	case debugProgressMsg:
+		if job, ok := m.debugJobs[msg.actionID]; ok {
+			job.current = msg.progress.Current
+			job.total = msg.progress.Total
+			job.message = msg.progress.Message
+		}
+		cmds = append(cmds, waitDebugProgress(msg.actionID, msg.ch))
+
+	case debugJobDoneMsg:
+		if job, ok := m.debugJobs[msg.actionID]; ok {
+			job.cancel()
+			delete(m.debugJobs, msg.actionID)
+		}
+		m.debugResult = &msg.result
+		m.debugResultTime = time.Now()
+		if msg.result.Success {
+			m.setStatus("✓ " + msg.result.Message)
+		} else {
+			m.setStatus("✗ " + msg.result.Message)
+		}
+
+	case jobReadyMsg:
+		// A promoted job just runs as an ordinary Process, tagged with
+		// JobID so the processFinishedMsg handler above reports its
+		// outcome back to the queue. job.Dir isn't honored yet - runCmd
+		// always runs in the lazycap working directory - and there's no
+		// dedicated "Scheduled"/"Retrying"/"Dead" tab yet; those jobs are
+		// only visible via m.jobQueue.List* for now. Both are deliberate,
+		// smaller-scope follow-ups rather than part of this change.
+		job := msg.job
+		p := m.createProcess(job.Command, strings.Join(append([]string{job.Command}, job.Args...), " "))
+		p.JobID = job.ID
+		p.MaxRetries = job.MaxRetries
+		p.Retries = job.Retries
+		if m.jobQueue != nil {
+			m.jobQueue.MarkInProgress(job.ID)
+		}
+		cmds = append(cmds, runCmd(p.ID, false, m.projectDir(), job.Command, job.Args...))
+		if m.jobScheduler != nil {
+			cmds = append(cmds, waitJobReady(m.jobScheduler))
+		}
+
+	case errMsg:
+		m.loading = false
+		m.addLog(fmt.Sprintf("Error: %v", msg.err))
+	}
+
+	if m.hasRunningProcesses() && len(cmds) == 0 {
+		cmds = append(cmds, m.spinner.Tick)
+	}
+
+	return m, tea.Batch(cmds...)
+}
 
 func (m *Model) updateLayout() {
 	if m.width == 0 || m.height == 0 {
@@ -974,23 +1911,142 @@ func (m *Model) updateLayout() {
 	m.logViewport.Height = logHeight
 }
 
+// logFilterLabel describes the active severity filter for the status bar.
+func (m *Model) logFilterLabel() string {
+	if !m.logFilterActive {
+		return "all"
+	}
+	return m.logSeverityFilter.String() + "+"
+}
+
+// passesLogFilter reports whether an entry should be shown under the
+// current severity filter.
+func (m *Model) passesLogFilter(e logparse.Entry) bool {
+	if !m.logFilterActive {
+		return true
+	}
+	return e.Level >= m.logSeverityFilter
+}
+
+// styleLogEntry colors a rendered log line by its severity level.
+func styleLogEntry(e logparse.Entry) string {
+	switch e.Level {
+	case logparse.LevelError:
+		return errorStyle.Render(e.Clean)
+	case logparse.LevelWarn:
+		return lipgloss.NewStyle().Foreground(warnColor).Render(e.Clean)
+	default:
+		return e.Clean
+	}
+}
+
 func (m *Model) updateLogViewport() {
 	p := m.getSelectedProcess()
 	if p == nil {
 		m.logViewport.SetContent(logEmptyStyle.Render("\n  Run a command to see output here..."))
 		return
 	}
-	m.logViewport.SetContent(strings.Join(p.Logs, "\n"))
-	m.logViewport.GotoBottom()
+	var lines []string
+	for i, e := range p.Entries {
+		if !m.passesLogFilter(e) {
+			continue
+		}
+		line := styleLogEntry(e)
+		if i == m.logCursor {
+			line = "▶ " + line
+		}
+		lines = append(lines, line)
+	}
+	m.logViewport.SetContent(strings.Join(lines, "\n"))
+	if m.logCursor < 0 {
+		m.logViewport.GotoBottom()
+	}
+}
+
+// jumpToError moves logCursor to the next (dir=1) or previous (dir=-1)
+// error-level entry in the selected process, wrapping around, and scrolls
+// the viewport to it.
+func (m *Model) jumpToError(dir int) {
+	p := m.getSelectedProcess()
+	if p == nil {
+		return
+	}
+	errs := p.ErrorIndices()
+	if len(errs) == 0 {
+		m.setStatus("No errors in this tab")
+		return
+	}
+
+	next := -1
+	if dir > 0 {
+		for _, idx := range errs {
+			if idx > m.logCursor {
+				next = idx
+				break
+			}
+		}
+		if next == -1 {
+			next = errs[0]
+		}
+	} else {
+		for i := len(errs) - 1; i >= 0; i-- {
+			if errs[i] < m.logCursor || m.logCursor < 0 {
+				next = errs[i]
+				break
+			}
+		}
+		if next == -1 {
+			next = errs[len(errs)-1]
+		}
+	}
+
+	m.logCursor = next
+	m.updateLogViewport()
+	m.logViewport.SetYOffset(next)
+}
+
+// openCurrentFileRef opens the file reference of the entry at logCursor
+// (if any) in the configured editor ($EDITOR, or the editorCommand
+// setting when set).
+func (m *Model) openCurrentFileRef() tea.Cmd {
+	p := m.getSelectedProcess()
+	if p == nil || m.logCursor < 0 || m.logCursor >= len(p.Entries) {
+		m.setStatus("No file reference on this line")
+		return nil
+	}
+	ref := p.Entries[m.logCursor].FileRef
+	if ref == nil {
+		m.setStatus("No file reference on this line")
+		return nil
+	}
+
+	editor := m.settings.GetString("editorCommand")
+	if editor == "" {
+		editor = os.Getenv("EDITOR")
+	}
+	if editor == "" {
+		editor = "vi"
+	}
+
+	target := ref.Path
+	if ref.Line > 0 {
+		target = fmt.Sprintf("%s:%d", ref.Path, ref.Line)
+	}
+	m.setStatus("Opening " + target)
+	return tea.ExecProcess(exec.Command(editor, target), func(err error) tea.Msg {
+		if err != nil {
+			return errMsg{err}
+		}
+		return nil
+	})
 }
 
 func (m *Model) addLog(line string) {
 	ts := time.Now().Format("15:04:05")
 	if len(m.processes) == 0 {
-		m.processes = append(m.processes, &Process{
-			ID: "system", Name: "System", Status: ProcessSuccess,
-			StartTime: time.Now(), Logs: []string{fmt.Sprintf("[%s] %s", ts, line)},
-		})
+		p := &Process{ID: "system", Name: "System", Status: ProcessSuccess, StartTime: time.Now()}
+		p.AddLog(fmt.Sprintf("[%s] %s", ts, line))
+		m.processes = append(m.processes, p)
 		m.selectedProcess = 0
 	} else {
 		m.processes[0].AddLog(fmt.Sprintf("[%s] %s", ts, line))
@@ -1003,27 +2059,152 @@ func (m *Model) setStatus(msg string) {
 	m.statusTime = time.Now()
 }
 
+// copyToClipboard writes content to the local clipboard, unless this
+// Model belongs to a remote session (see SetRemoteSession) - there's no
+// local clipboard to reach on an SSH server, so remote sessions are told
+// to use export instead.
+func (m *Model) copyToClipboard(content string) error {
+	if m.remoteSession {
+		return fmt.Errorf("clipboard unavailable over SSH; use export instead")
+	}
+	return clipboard.WriteAll(content)
+}
+
+// exportDirOrDefault is where log exports are written: exportDir for a
+// remote session (see SetRemoteSession), or the shared os.TempDir
+// otherwise.
+func (m *Model) exportDirOrDefault() string {
+	if m.exportDir != "" {
+		return m.exportDir
+	}
+	return os.TempDir()
+}
+
 func (m *Model) createProcess(name, command string) *Process {
 	id := fmt.Sprintf("p%d", m.nextProcessID)
 	m.nextProcessID++
 	p := &Process{
 		ID: id, Name: name, Command: command, Status: ProcessRunning,
 		StartTime: time.Now(),
-		Logs:      []string{fmt.Sprintf("[%s] $ %s", time.Now().Format("15:04:05"), command)},
+		Progress:  NewProgress(),
 	}
+	m.openSessionLog(p)
+	m.openLogBuffer(p)
+	p.AddLog(fmt.Sprintf("[%s] $ %s", time.Now().Format("15:04:05"), command))
+	m.persistSession(p)
 	m.processes = append(m.processes, p)
 	m.selectedProcess = len(m.processes) - 1
+	m.logCursor = -1
 	m.updateLogViewport()
+	m.updateProcessMetrics()
 	return p
 }
 
+// liveProcessKey is the session.Registry key for a process about to run
+// cmd in this project - the project name plus the literal command, so two
+// `lazycap serve` sessions against the same project only ever share a
+// LiveProcess when they'd run the exact same thing.
+func (m *Model) liveProcessKey(cmd string) string {
+	return m.sessionKey() + "|" + cmd
+}
+
+// startOrAttach runs start - the tea.Cmd that would otherwise launch p's
+// own process - unless another session already has the same command
+// running for this project (see session.Registry), in which case p
+// attaches to it instead of starting a duplicate. An attached p never gets
+// a real p.Cmd, which every existing nil-check (Signal/kill, resource
+// sampling, persistSession) already treats as "nothing local to act on".
+func (m *Model) startOrAttach(p *Process, start tea.Cmd) tea.Cmd {
+	key := m.liveProcessKey(p.Command)
+	if lp, ok := session.Shared().Attach(key); ok {
+		p.AddLog("Attached to a build already running in another session")
+		ch := lp.Subscribe()
+		return func() tea.Msg {
+			return processStartedMsg{processID: p.ID, outputChan: ch}
+		}
+	}
+	p.Live = session.Shared().Register(key)
+	p.liveKey = key
+	return start
+}
+
+// processAction buckets a Process into the coarse "action" label used by
+// the lazycap_processes_running/lazycap_process_duration_seconds/
+// lazycap_process_failures_total metrics (see internal/metrics).
+func processAction(p *Process) string {
+	switch p.Name {
+	case "Sync":
+		return "sync"
+	case "Build":
+		return "build"
+	case "Web":
+		return "web"
+	case "Open":
+		return "open"
+	case "Upgrade":
+		return "upgrade"
+	default:
+		if strings.HasPrefix(p.Name, "Mirror (") || strings.Contains(p.Command, "cap run") {
+			return "run"
+		}
+		return "other"
+	}
+}
+
+// processMetricActions lists every label updateProcessMetrics sets a
+// gauge for, so actions that drop to zero running processes still get an
+// explicit 0 rather than going stale at their last nonzero value.
+var processMetricActions = []string{"run", "sync", "build", "web", "open", "upgrade", "other"}
+
+// updateProcessMetrics recomputes lazycap_processes_running from the
+// current process list. Called at every point a process starts or stops
+// running (createProcess, processFinishedMsg, Kill, AutoRestart toggle).
+func (m *Model) updateProcessMetrics() {
+	counts := make(map[string]float64, len(processMetricActions))
+	for _, p := range m.processes {
+		if p.Status == ProcessRunning || p.Status == ProcessBackoff {
+			counts[processAction(p)]++
+		}
+	}
+	for _, action := range processMetricActions {
+		m.metrics.SetGauge("lazycap_processes_running", map[string]string{"action": action}, counts[action])
+	}
+}
+
+// updateDeviceMetrics sets lazycap_devices_online from the current device
+// list. Called after devicesLoadedMsg and deviceBootedMsg.
+func (m *Model) updateDeviceMetrics() {
+	var online float64
+	for _, d := range m.devices {
+		if d.Online {
+			online++
+		}
+	}
+	m.metrics.SetGauge("lazycap_devices_online", nil, online)
+}
+
+// updatePreflightMetrics sets lazycap_preflight_errors from the current
+// preflight results. Called after every preflight.Run().
+func (m *Model) updatePreflightMetrics() {
+	if m.preflightResults == nil {
+		return
+	}
+	var errors float64
+	for _, c := range m.preflightResults.Checks {
+		if c.Status == preflight.StatusError {
+			errors++
+		}
+	}
+	m.metrics.SetGauge("lazycap_preflight_errors", nil, errors)
+}
+
 func (m *Model) runAction(action string, liveReload bool) tea.Cmd {
 	dev := m.getSelectedDevice()
 
 	switch action {
 	case "run":
 		if dev == nil {
-			m.addLog("No device selected")
+			m.addLog(i18n.T("status.no_device_selected"))
 			return nil
 		}
 		// Handle web platform
@@ -1031,7 +2212,7 @@ func (m *Model) runAction(action string, liveReload bool) tea.Cmd {
 			return m.startWebDevCommand()
 		}
 		if !dev.Online {
-			m.addLog(fmt.Sprintf("Booting %s...", dev.Name))
+			m.addLog(i18n.T("status.booting_device", dev.Name))
 			p := m.createProcess("Boot "+dev.Name, "xcrun simctl boot")
 			p.AddLog("Waiting for simulator...")
 			return tea.Batch(bootDevice(dev, liveReload), m.spinner.Tick)
@@ -1089,7 +2270,94 @@ func (m *Model) startRunCommand(dev *device.Device, liveReload bool) tea.Cmd {
 		name = shortName + " (live)"
 	}
 	p := m.createProcess(name, "npx "+strings.Join(args, " "))
-	return runCmd(p.ID, "npx", args...)
+	p.UsePTY = true
+	return m.startOrAttach(p, runCmd(p.ID, true, m.projectDir(), "npx", args...))
+}
+
+// startMirrorRun launches `npx cap run` in parallel against every
+// multi-selected device (toggled with Select/space in the devices pane),
+// reusing the same createProcess/runCmd plumbing as a single-device run so
+// each gets its own tab, plus a synthetic aggregate tab that interleaves
+// every member's output, prefixed by device short name.
+func (m *Model) startMirrorRun(liveReload bool) tea.Cmd {
+	var targets []*device.Device
+	for idx := range m.selectedDevices {
+		if idx >= 0 && idx < len(m.devices) {
+			targets = append(targets, &m.devices[idx])
+		}
+	}
+	if len(targets) < 2 {
+		m.addLog("Select at least 2 devices with space before starting a mirrored run")
+		return nil
+	}
+
+	group := &ProcessGroup{
+		ID:   fmt.Sprintf("grp%d", len(m.processGroups)+1),
+		Name: fmt.Sprintf("Mirror (%d)", len(targets)),
+	}
+	aggregate := m.createProcess(group.Name, "mirrored run")
+	group.AggregateProcessID = aggregate.ID
+
+	var cmds []tea.Cmd
+	for _, dev := range targets {
+		shortName := dev.Name
+		if len(shortName) > 15 {
+			shortName = shortName[:13] + ".."
+		}
+		name := shortName
+		args := []string{"cap", "run", dev.Platform, "--target", dev.ID}
+		if liveReload {
+			args = append(args, "-l", "--external")
+			name = shortName + " (live)"
+		}
+		p := m.createProcess(name, "npx "+strings.Join(args, " "))
+		p.UsePTY = true
+		p.ParentID = aggregate.ID
+		aggregate.Children = append(aggregate.Children, p.ID)
+		group.ProcessIDs = append(group.ProcessIDs, p.ID)
+		cmds = append(cmds, m.startOrAttach(p, runCmd(p.ID, true, m.projectDir(), "npx", args...)))
+	}
+
+	m.processGroups = append(m.processGroups, group)
+	m.selectedDevices = make(map[int]bool)
+	m.selectedProcess = len(m.processes) - len(targets) - 1 // focus the aggregate tab
+	m.updateLogViewport()
+	return tea.Batch(cmds...)
+}
+
+// findProcess looks up a process by ID, returning nil if it's gone (e.g.
+// pruned in a future cleanup pass).
+func (m *Model) findProcess(id string) *Process {
+	for _, p := range m.processes {
+		if p.ID == id {
+			return p
+		}
+	}
+	return nil
+}
+
+// findProcessByName returns the first still-running process with the
+// given Name (e.g. "Web"), or nil if none is running.
+func (m *Model) findProcessByName(name string) *Process {
+	for _, p := range m.processes {
+		if p.Name == name && (p.Status == ProcessRunning || p.Status == ProcessBackoff) {
+			return p
+		}
+	}
+	return nil
+}
+
+// groupForProcess returns the ProcessGroup a process belongs to, or nil if
+// it wasn't launched as part of a mirrored run.
+func (m *Model) groupForProcess(processID string) *ProcessGroup {
+	for _, g := range m.processGroups {
+		for _, id := range g.ProcessIDs {
+			if id == processID {
+				return g
+			}
+		}
+	}
+	return nil
 }
 
 func (m *Model) startSyncCommand(platform string) tea.Cmd {
@@ -1098,22 +2366,22 @@ func (m *Model) startSyncCommand(platform string) tea.Cmd {
 		args = append(args, platform)
 	}
 	p := m.createProcess("Sync", "npx "+strings.Join(args, " "))
-	return runCmd(p.ID, "npx", args...)
+	return m.startOrAttach(p, runCmd(p.ID, false, m.projectDir(), "npx", args...))
 }
 
 func (m *Model) startBuildCommand() tea.Cmd {
 	p := m.createProcess("Build", "npm run build")
-	return runCmd(p.ID, "npm", "run", "build")
+	return m.startOrAttach(p, runCmd(p.ID, false, m.projectDir(), "npm", "run", "build"))
 }
 
 func (m *Model) startOpenCommand(platform string) tea.Cmd {
 	p := m.createProcess("Open", "npx cap open "+platform)
-	return runCmd(p.ID, "npx", "cap", "open", platform)
+	return m.startOrAttach(p, runCmd(p.ID, false, m.projectDir(), "npx", "cap", "open", platform))
 }
 
 func (m *Model) startUpgrade() tea.Cmd {
 	p := m.createProcess("Upgrade", "npm install @capacitor/core@latest @capacitor/cli@latest")
-	return runCmd(p.ID, "npm", "install", "@capacitor/core@latest", "@capacitor/cli@latest")
+	return m.startOrAttach(p, runCmd(p.ID, false, m.projectDir(), "npm", "install", "@capacitor/core@latest", "@capacitor/cli@latest"))
 }
 
 func (m *Model) startWebDevCommand() tea.Cmd {
@@ -1129,6 +2397,13 @@ func (m *Model) startWebDevCommand() tea.Cmd {
 	https := m.settings.GetBool("webHttps")
 
 	p := m.createProcess("Web", command)
+	p.UsePTY = true
+	if m.settings.GetBool("webAutoRestart") {
+		p.AutoRestart = true
+		p.StartRetries = 5
+		p.StartSeconds = 3
+		p.RetriesLeft = p.StartRetries
+	}
 
 	// Kill any process using the port first
 	if cap.KillPort(port) {
@@ -1157,10 +2432,16 @@ func (m *Model) startWebDevCommand() tea.Cmd {
 
 	// Run the command directly - let the dev server use its own defaults
 	// The command should be the full command like "npm run dev" or "npx vite"
-	return runWebCmd(p.ID, command, port, host)
+	return m.startOrAttach(p, runWebCmd(p.ID, m.projectDir(), command, port, host, true))
 }
 
-func runCmd(processID, name string, args ...string) tea.Cmd {
+// runCmd runs name/args in dir - the project's own root directory, passed
+// in by the caller rather than read from os.Getwd(). lazycap serve runs
+// every SSH session's Model in one shared process, so relying on the
+// process-wide cwd here would race against another session's os.Chdir;
+// threading dir through keeps each session's commands pinned to its own
+// project regardless of what any other goroutine does to the cwd.
+func runCmd(processID string, usePTY bool, dir string, name string, args ...string) tea.Cmd {
 	return func() tea.Msg {
 		ch := make(chan string, 100)
 
@@ -1189,16 +2470,27 @@ func runCmd(processID, name string, args ...string) tea.Cmd {
 		cmd.Env = os.Environ()
 
 		// Set working directory
-		if cwd, err := os.Getwd(); err == nil {
+		if dir != "" {
+			cmd.Dir = dir
+		} else if cwd, err := os.Getwd(); err == nil {
 			cmd.Dir = cwd
 		}
 
+		// Run as its own process group leader so a shell pipeline or a
+		// `make -j` invocation can be cascade-signalled as a tree (see
+		// Process.Signal/GracefulStop) instead of just the shell itself.
+		setPgid(cmd)
+
+		if usePTY {
+			return runCmdWithPTYOrPipes(processID, cmd, ch)
+		}
 		return runCmdWithPipes(processID, cmd, ch)
 	}
 }
 
-// runWebCmd runs a web dev server command with proper port/host handling
-func runWebCmd(processID, command string, port int, host string) tea.Cmd {
+// runWebCmd runs a web dev server command with proper port/host handling,
+// in dir for the same reason runCmd takes one.
+func runWebCmd(processID, dir, command string, port int, host string, usePTY bool) tea.Cmd {
 	return func() tea.Msg {
 		ch := make(chan string, 100)
 
@@ -1212,10 +2504,10 @@ func runWebCmd(processID, command string, port int, host string) tea.Cmd {
 		if hasExtraArgs {
 			// For npm/yarn/pnpm run commands, add -- separator
 			if strings.HasPrefix(command, "npm run") ||
-			   strings.HasPrefix(command, "yarn run") ||
-			   strings.HasPrefix(command, "pnpm run") ||
-			   strings.HasPrefix(command, "yarn ") ||
-			   strings.HasPrefix(command, "pnpm ") {
+				strings.HasPrefix(command, "yarn run") ||
+				strings.HasPrefix(command, "pnpm run") ||
+				strings.HasPrefix(command, "yarn ") ||
+				strings.HasPrefix(command, "pnpm ") {
 				cmdStr += " --"
 			}
 
@@ -1237,10 +2529,17 @@ func runWebCmd(processID, command string, port int, host string) tea.Cmd {
 		cmd := exec.Command(shell, "-c", shellCmd)
 		cmd.Env = os.Environ()
 
-		if cwd, err := os.Getwd(); err == nil {
+		if dir != "" {
+			cmd.Dir = dir
+		} else if cwd, err := os.Getwd(); err == nil {
 			cmd.Dir = cwd
 		}
 
+		setPgid(cmd)
+
+		if usePTY {
+			return runCmdWithPTYOrPipes(processID, cmd, ch)
+		}
 		return runCmdWithPipes(processID, cmd, ch)
 	}
 }
@@ -1314,6 +2613,10 @@ func (m Model) View() string {
 		return m.renderPlugins()
 	}
 
+	if m.showLiveReload {
+		return m.renderLiveReload()
+	}
+
 	// Build the view
 	left := m.renderLeft()
 	right := m.renderRight()
@@ -1358,9 +2661,9 @@ func (m *Model) renderHeader() string {
 				count++
 			}
 		}
-		status = fmt.Sprintf("%s %d running", m.spinner.View(), count)
+		status = m.spinner.View() + " " + i18n.Plural("header.processes_running", count)
 	} else {
-		status = mutedStyle.Render(fmt.Sprintf("%d devices", len(m.devices)))
+		status = mutedStyle.Render(i18n.Plural("header.devices", len(m.devices)))
 	}
 
 	// Upgrade notice
@@ -1372,9 +2675,12 @@ func (m *Model) renderHeader() string {
 	// Preflight indicator
 	var preflightIndicator string
 	if m.preflightResults != nil {
-		if m.preflightResults.HasErrors {
+		switch {
+		case m.preflightResults.HasErrors && m.preflightFlapping():
+			preflightIndicator = "  " + lipgloss.NewStyle().Foreground(warnColor).Render("⚠ preflight flapping")
+		case m.preflightResults.HasErrors:
 			preflightIndicator = "  " + errorStyle.Render("⚠ preflight errors")
-		} else if m.preflightResults.HasWarnings {
+		case m.preflightResults.HasWarnings:
 			preflightIndicator = "  " + lipgloss.NewStyle().Foreground(warnColor).Render("⚠ preflight warnings")
 		}
 	}
@@ -1396,13 +2702,19 @@ func (m *Model) renderHeader() string {
 		}
 	}
 
+	// Control API indicator
+	var apiIndicator string
+	if m.apiPort != 0 {
+		apiIndicator = "  " + mutedStyle.Render(fmt.Sprintf("API :%d", m.apiPort))
+	}
+
 	// Status message (show for 3 seconds)
 	var statusMsg string
 	if m.statusMessage != "" && time.Since(m.statusTime) < 3*time.Second {
 		statusMsg = "  " + successStyle.Render(m.statusMessage)
 	}
 
-	return fmt.Sprintf("%s  %s  %s  %s%s%s%s%s", logo, project, platformStr, status, upgrade, preflightIndicator, pluginStatus, statusMsg)
+	return fmt.Sprintf("%s  %s  %s  %s%s%s%s%s%s", logo, project, platformStr, status, upgrade, preflightIndicator, pluginStatus, apiIndicator, statusMsg)
 }
 
 func (m *Model) renderLeft() string {
@@ -1445,6 +2757,12 @@ func (m *Model) renderLeft() string {
 			name = name[:15] + "..."
 		}
 
+		// Mirror multi-select checkbox
+		mirrorMark := " "
+		if m.selectedDevices[i] {
+			mirrorMark = lipgloss.NewStyle().Foreground(capCyan).Bold(true).Render("✓")
+		}
+
 		// Build the line
 		isSelected := i == m.selectedDevice
 		isFocused := m.focus == FocusDevices
@@ -1453,18 +2771,18 @@ func (m *Model) renderLeft() string {
 			// Selected and focused: arrow indicator + cyan text
 			arrow := lipgloss.NewStyle().Foreground(capBlue).Bold(true).Render("▶")
 			nameStyled := lipgloss.NewStyle().Foreground(capCyan).Bold(true).Render(name)
-			line := fmt.Sprintf(" %s %s %s %s  %s", arrow, status, platform, nameStyled, deviceType)
+			line := fmt.Sprintf(" %s%s %s %s %s  %s", arrow, mirrorMark, status, platform, nameStyled, deviceType)
 			items = append(items, line)
 		} else if isSelected {
 			// Selected but not focused: subtle highlight
 			arrow := mutedStyle.Render("▶")
 			nameStyled := lipgloss.NewStyle().Foreground(capLight).Render(name)
-			line := fmt.Sprintf(" %s %s %s %s  %s", arrow, status, platform, nameStyled, deviceType)
+			line := fmt.Sprintf(" %s%s %s %s %s  %s", arrow, mirrorMark, status, platform, nameStyled, deviceType)
 			items = append(items, line)
 		} else {
 			// Not selected
 			nameStyled := lipgloss.NewStyle().Foreground(capLight).Render(name)
-			line := fmt.Sprintf("   %s %s %s  %s", status, platform, nameStyled, deviceType)
+			line := fmt.Sprintf("  %s %s %s %s  %s", mirrorMark, status, platform, nameStyled, deviceType)
 			items = append(items, line)
 		}
 	}
@@ -1523,6 +2841,10 @@ func (m *Model) renderRight() string {
 			icon = failedStyle.Render("✗")
 		case ProcessCancelled:
 			icon = mutedStyle.Render("○")
+		case ProcessBackoff:
+			icon = m.spinner.View()
+		case ProcessFatal:
+			icon = failedStyle.Render("☠")
 		}
 
 		name := p.Name
@@ -1530,17 +2852,41 @@ func (m *Model) renderRight() string {
 			name = name[:10] + ".."
 		}
 
+		// Indent children under their parent (see startMirrorRun) with a
+		// tree branch glyph, since the tab bar is one line rather than a
+		// multi-line list.
+		indent := ""
+		if p.ParentID != "" {
+			indent = mutedStyle.Render("└ ")
+		}
+
 		// Simple format: selected gets highlight, others are muted
 		if i == m.selectedProcess {
 			// Selected: bright with underline effect using brackets
-			tabParts = append(tabParts, fmt.Sprintf("%s [%s]", icon, lipgloss.NewStyle().Foreground(capBlue).Bold(true).Render(name)))
+			tabParts = append(tabParts, fmt.Sprintf("%s%s [%s]", indent, icon, lipgloss.NewStyle().Foreground(capBlue).Bold(true).Render(name)))
 		} else {
 			// Unselected: muted
-			tabParts = append(tabParts, fmt.Sprintf("%s %s", icon, mutedStyle.Render(name)))
+			tabParts = append(tabParts, fmt.Sprintf("%s%s %s", indent, icon, mutedStyle.Render(name)))
+		}
+
+		if p.Progress != nil {
+			if label := progressLabel(p.Progress.Snapshot()); label != "" {
+				tabParts[len(tabParts)-1] += " " + mutedStyle.Render(label)
+			}
 		}
 	}
 
 	tabBar := strings.Join(tabParts, "  │  ")
+	if m.logFilterActive {
+		tabBar += "  " + mutedStyle.Render("["+m.logFilterLabel()+"]")
+	}
+	if sel := m.getSelectedProcess(); sel != nil && sel.Status == ProcessBackoff {
+		wait := time.Until(sel.RestartAt).Round(time.Second)
+		if wait < 0 {
+			wait = 0
+		}
+		tabBar += "  " + mutedStyle.Render(fmt.Sprintf("[restarting in %s]", wait))
+	}
 
 	// Logs
 	logContent := m.logViewport.View()
@@ -1624,7 +2970,7 @@ func (m *Model) renderPreflight() string {
 	nameStyle := lipgloss.NewStyle().Width(20)
 	pathStyle := mutedStyle
 
-	for _, check := range m.preflightResults.Checks {
+	for i, check := range m.preflightResults.Checks {
 		var icon string
 		var msgStyle lipgloss.Style
 
@@ -1643,11 +2989,28 @@ func (m *Model) renderPreflight() string {
 		name := nameStyle.Render(check.Name)
 		msg := msgStyle.Render(check.Message)
 
-		line := fmt.Sprintf("  %s %s %s", icon, name, msg)
+		cursor := "  "
+		if i == m.preflightCursor {
+			cursor = lipgloss.NewStyle().Foreground(capBlue).Bold(true).Render("▶ ")
+		}
+
+		line := fmt.Sprintf("%s%s %s %s", cursor, icon, name, msg)
 		if check.Path != "" && check.Status == preflight.StatusOK {
 			line += "  " + pathStyle.Render(check.Path)
 		}
 		lines = append(lines, line)
+
+		if i == m.preflightCursor && len(check.Remediations) > 0 {
+			r := check.Remediations[0]
+			prompt := "Press enter to run"
+			if r.RequiresConfirm {
+				prompt = "Press enter twice to run"
+			}
+			lines = append(lines, "      "+mutedStyle.Render(fmt.Sprintf("%s: %s (%s)", r.Name, r.Description, prompt)))
+		}
+		if i == m.preflightCursor && check.Fix != nil {
+			lines = append(lines, "      "+mutedStyle.Render(fmt.Sprintf("%s: %s (press f to fix)", check.Fix.Name, check.Fix.Description)))
+		}
 	}
 
 	lines = append(lines, "")
@@ -1671,14 +3034,32 @@ func (m *Model) renderPreflight() string {
 
 	lines = append(lines, "")
 	lines = append(lines, "")
-	lines = append(lines, helpStyle.Render("  Press "+helpKeyStyle.Render("p")+" to close  •  "+helpKeyStyle.Render("q")+" to quit"))
+	lines = append(lines, helpStyle.Render("  "+helpKeyStyle.Render("↑/↓")+" select  •  "+helpKeyStyle.Render("enter")+" run fix  •  "+helpKeyStyle.Render("f")+" auto-fix  •  "+helpKeyStyle.Render("v")+" verbose diagnostics  •  "+helpKeyStyle.Render("p")+" close  •  "+helpKeyStyle.Render("q")+" quit"))
 
 	return strings.Join(lines, "\n")
 }
 
-func (m Model) handleSettingsInput(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
-	categories := settings.GetCategories()
-	currentCategory := categories[m.settingsCategory]
+// preflightDiagnosticCommand maps a preflight check name to the verbose
+// diagnostic command ("v" in the preflight panel) for checks where a plain
+// install/version check doesn't say enough - e.g. a simulator/emulator
+// that's "present" but not actually responding.
+func preflightDiagnosticCommand(checkName string) (name string, args []string, ok bool) {
+	switch checkName {
+	case "iOS Simulator":
+		return "xcrun", []string{"simctl", "list"}, true
+	case "Android ADB", "Android Emulator":
+		return "adb", []string{"devices", "-l"}, true
+	default:
+		return "", nil, false
+	}
+}
+
+// handlePreflightInput drives the preflight panel: moving between checks
+// and, for ones that failed with a Remediation attached, running it as a
+// Process tab (mirroring the debug panel's confirm-then-run pattern for
+// anything destructive).
+func (m Model) handlePreflightInput(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	checks := m.preflightResults.Checks
 
 	switch msg.String() {
 	case "ctrl+c":
@@ -1686,7 +3067,6 @@ func (m Model) handleSettingsInput(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 		return m, tea.Quit
 
 	case "q":
-		// Require confirmation
 		if m.confirmQuit && time.Since(m.quitTime) < 3*time.Second {
 			m.gracefulShutdown()
 			return m, tea.Quit
@@ -1696,74 +3076,567 @@ func (m Model) handleSettingsInput(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 		m.setStatus("Press q again to quit")
 		return m, nil
 
-	case "esc", ",":
-		m.showSettings = false
+	case "esc", "p":
+		m.showPreflight = false
 		return m, nil
 
 	case "up", "k":
-		if m.settingsCursor > 0 {
-			m.settingsCursor--
-		} else if m.settingsCategory > 0 {
-			// Move to previous category
-			m.settingsCategory--
-			m.settingsCursor = len(categories[m.settingsCategory].Settings) - 1
+		if m.preflightCursor > 0 {
+			m.preflightCursor--
+			m.preflightConfirm = false
 		}
 		return m, nil
 
 	case "down", "j":
-		if m.settingsCursor < len(currentCategory.Settings)-1 {
-			m.settingsCursor++
-		} else if m.settingsCategory < len(categories)-1 {
-			// Move to next category
-			m.settingsCategory++
-			m.settingsCursor = 0
+		if m.preflightCursor < len(checks)-1 {
+			m.preflightCursor++
+			m.preflightConfirm = false
 		}
 		return m, nil
 
-	case "left", "h":
-		if m.settingsCategory > 0 {
-			m.settingsCategory--
-			m.settingsCursor = 0
+	case "v":
+		if m.preflightCursor >= len(checks) {
+			return m, nil
 		}
-		return m, nil
+		name, args, ok := preflightDiagnosticCommand(checks[m.preflightCursor].Name)
+		if !ok {
+			m.setStatus("No verbose diagnostics available for " + checks[m.preflightCursor].Name)
+			return m, nil
+		}
+		m.showPreflight = false
+		p := m.createProcess(checks[m.preflightCursor].Name+" diagnostics", name+" "+strings.Join(args, " "))
+		return m, runCmd(p.ID, false, m.projectDir(), name, args...)
 
-	case "right", "l":
-		if m.settingsCategory < len(categories)-1 {
-			m.settingsCategory++
-			m.settingsCursor = 0
+	case "U":
+		info := m.preflightResults.UpdateInfo
+		if info == nil || !info.UpdateAvailable {
+			return m, nil
 		}
-		return m, nil
+		if m.pluginContext == nil {
+			m.setStatus("Plugin context unavailable - cannot self-update")
+			return m, nil
+		}
+		m.setStatus(fmt.Sprintf("Installing v%s...", info.LatestVersion))
+		return m, applyUpdateCmd(m.pluginContext, info)
+
+	case "f":
+		if m.preflightCursor >= len(checks) {
+			return m, nil
+		}
+		check := checks[m.preflightCursor]
+		if check.Fix == nil {
+			m.setStatus("No automated fix available for " + check.Name)
+			return m, nil
+		}
+		if m.pluginContext == nil {
+			m.setStatus("Plugin context unavailable - cannot run fix")
+			return m, nil
+		}
+		m.setStatus("Running fix: " + check.Fix.Name)
+		return m, runPreflightFixCmd(m.pluginContext, check.Name)
 
 	case "enter", " ":
-		// Toggle or cycle the current setting
-		setting := currentCategory.Settings[m.settingsCursor]
-		switch setting.Type {
-		case "bool":
-			m.settings.ToggleBool(setting.Key)
-			m.settings.Save()
-			m.setStatus(fmt.Sprintf("%s: %v", setting.Name, m.settings.GetBool(setting.Key)))
-		case "choice":
-			newVal := m.settings.CycleChoice(setting.Key, setting.Choices)
-			m.settings.Save()
-			displayVal := newVal
-			if displayVal == "" {
-				displayVal = "(auto)"
-			}
-			m.setStatus(fmt.Sprintf("%s: %s", setting.Name, displayVal))
+		if m.preflightCursor >= len(checks) {
+			return m, nil
 		}
-		return m, nil
+		check := checks[m.preflightCursor]
+		if len(check.Remediations) == 0 {
+			m.setStatus("No remediation available for " + check.Name)
+			return m, nil
+		}
+		remediation := check.Remediations[0]
+		if remediation.RequiresConfirm && !m.preflightConfirm {
+			m.preflightConfirm = true
+			m.setStatus("⚠ Press enter again to run: " + remediation.Name)
+			return m, nil
+		}
+		m.preflightConfirm = false
+		return m, m.runRemediation(remediation)
 	}
 
 	return m, nil
 }
 
-func (m *Model) renderSettings() string {
-	categories := settings.GetCategories()
-
-	// Title
-	title := lipgloss.NewStyle().
-		Foreground(capBlue).
-		Bold(true).
+// runRemediation executes a preflight Remediation. Pure Go fixes (Run set)
+// apply immediately and re-run preflight checks in place; shell-command
+// fixes are launched as a regular Process tab like any other action, and
+// preflight is re-run once that tab finishes (see processFinishedMsg).
+func (m *Model) runRemediation(r preflight.Remediation) tea.Cmd {
+	if r.Run != nil {
+		p := m.createProcess(r.Name, r.Description)
+		summary, err := r.Run(".")
+		if err != nil {
+			p.Status = ProcessFailed
+			p.AddLog("Error: " + err.Error())
+		} else {
+			p.Status = ProcessSuccess
+			p.AddLog(summary)
+		}
+		p.EndTime = time.Now()
+		m.preflightResults = preflight.Run()
+		m.recordPreflightHistory(m.preflightResults)
+		m.updatePreflightMetrics()
+		m.updateProcessMetrics()
+		m.updateLogViewport()
+		return nil
+	}
+
+	p := m.createProcess(r.Name, r.Command+" "+strings.Join(r.Args, " "))
+	m.remediationProcID = p.ID
+	m.showPreflight = false
+	return runCmd(p.ID, false, m.projectDir(), r.Command, r.Args...)
+}
+
+// liveReloadProcesses returns the processes that have a detected
+// Local:/Network: dev-server URL, for the live-reload overlay.
+func (m *Model) liveReloadProcesses() []*Process {
+	var out []*Process
+	for _, p := range m.processes {
+		if p.URLMatcher.PreferredURL() != "" {
+			out = append(out, p)
+		}
+	}
+	return out
+}
+
+// handleLiveReloadInput drives the live-reload URL/QR overlay: cycling
+// between dev servers when more than one is running, and copying the
+// current one's URL to the clipboard.
+func (m Model) handleLiveReloadInput(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	procs := m.liveReloadProcesses()
+
+	switch msg.String() {
+	case "ctrl+c":
+		m.gracefulShutdown()
+		return m, tea.Quit
+
+	case "q":
+		if m.confirmQuit && time.Since(m.quitTime) < 3*time.Second {
+			m.gracefulShutdown()
+			return m, tea.Quit
+		}
+		m.confirmQuit = true
+		m.quitTime = time.Now()
+		m.setStatus("Press q again to quit")
+		return m, nil
+
+	case "esc", "L":
+		m.showLiveReload = false
+		return m, nil
+
+	case "left", "h", "up", "k":
+		if m.liveReloadProcIdx > 0 {
+			m.liveReloadProcIdx--
+		}
+		return m, nil
+
+	case "right", "l", "down", "j", "tab":
+		if m.liveReloadProcIdx < len(procs)-1 {
+			m.liveReloadProcIdx++
+		}
+		return m, nil
+
+	case "c":
+		if m.liveReloadProcIdx < len(procs) {
+			url := procs[m.liveReloadProcIdx].URLMatcher.PreferredURL()
+			if err := m.copyToClipboard(url); err != nil {
+				m.setStatus("Copy failed: " + err.Error())
+			} else {
+				m.setStatus("Copied " + url + " to clipboard")
+			}
+		}
+		return m, nil
+	}
+
+	return m, nil
+}
+
+// renderLiveReload draws the QR/URL overlay for the currently selected
+// live-reload dev server, so a physical device can scan it to connect.
+func (m *Model) renderLiveReload() string {
+	title := lipgloss.NewStyle().
+		Foreground(capBlue).
+		Bold(true).
+		MarginBottom(1).
+		Render("  📱 Live Reload")
+
+	procs := m.liveReloadProcesses()
+	var lines []string
+	lines = append(lines, "", title, "")
+
+	if len(procs) == 0 {
+		lines = append(lines, "  "+mutedStyle.Render("No running dev server URL detected yet."))
+		lines = append(lines, "  "+mutedStyle.Render("Start a live-reload run or web dev server first."))
+		lines = append(lines, "")
+		lines = append(lines, helpStyle.Render("  "+helpKeyStyle.Render("L")+" close  •  "+helpKeyStyle.Render("q")+" quit"))
+		return strings.Join(lines, "\n")
+	}
+
+	if m.liveReloadProcIdx >= len(procs) {
+		m.liveReloadProcIdx = len(procs) - 1
+	}
+	p := procs[m.liveReloadProcIdx]
+	url := p.URLMatcher.PreferredURL()
+
+	if len(procs) > 1 {
+		var tabs []string
+		for i, proc := range procs {
+			name := proc.Name
+			if i == m.liveReloadProcIdx {
+				tabs = append(tabs, lipgloss.NewStyle().Foreground(capCyan).Bold(true).Render("["+name+"]"))
+			} else {
+				tabs = append(tabs, mutedStyle.Render(name))
+			}
+		}
+		lines = append(lines, "  "+strings.Join(tabs, "  "))
+		lines = append(lines, "")
+	}
+
+	lines = append(lines, "  "+successStyle.Render(url))
+	lines = append(lines, "")
+
+	code, err := qr.Encode(url)
+	if err != nil {
+		lines = append(lines, "  "+errorStyle.Render("Could not render QR: "+err.Error()))
+	} else {
+		for _, row := range strings.Split(strings.TrimRight(code.Render(), "\n"), "\n") {
+			lines = append(lines, "  "+row)
+		}
+	}
+
+	lines = append(lines, "")
+	help := "  " + helpKeyStyle.Render("c") + " copy URL  •  " + helpKeyStyle.Render("L") + " close  •  " + helpKeyStyle.Render("q") + " quit"
+	if len(procs) > 1 {
+		help = "  " + helpKeyStyle.Render("tab") + " switch  •  " + help[2:]
+	}
+	lines = append(lines, helpStyle.Render(help))
+
+	return strings.Join(lines, "\n")
+}
+
+// diffSettings returns the keys (across all categories) whose typed value
+// differs between old and updated, for reporting what a hot-reload
+// actually changed.
+func diffSettings(old, updated *settings.Settings) []string {
+	var changed []string
+	for _, cat := range settings.GetCategories() {
+		for _, s := range cat.Settings {
+			switch s.Type {
+			case "bool":
+				if old.GetBool(s.Key) != updated.GetBool(s.Key) {
+					changed = append(changed, s.Key)
+				}
+			case "int":
+				if old.GetInt(s.Key) != updated.GetInt(s.Key) {
+					changed = append(changed, s.Key)
+				}
+			default: // "string", "choice"
+				if old.GetString(s.Key) != updated.GetString(s.Key) {
+					changed = append(changed, s.Key)
+				}
+			}
+		}
+	}
+	return changed
+}
+
+// webSettingsChanged reports whether any of the changed keys affect a
+// running web dev server, so the hot-reload status can prompt the user to
+// restart it (settings aren't re-read by an already-running process).
+func webSettingsChanged(changed []string) bool {
+	for _, key := range changed {
+		switch key {
+		case "webDevCommand", "webDevPort", "webHost", "webHttps", "webBrowserPath", "webOpenBrowser":
+			return true
+		}
+	}
+	return false
+}
+
+// handlePTYInput forwards keystrokes straight to the selected process's
+// pty while m.ptyInputMode is on (see the SendInput keybinding), so the
+// user can answer an interactive y/n (or similar) prompt from a
+// PTY-backed process like `cap run`. Esc leaves the mode without sending
+// anything.
+func (m Model) handlePTYInput(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "esc":
+		m.ptyInputMode = false
+		m.setStatus("Stopped sending input")
+		return m, nil
+	case "enter":
+		if p := m.getSelectedProcess(); p != nil {
+			p.WriteInput("\r")
+		}
+		return m, nil
+	default:
+		if p := m.getSelectedProcess(); p != nil && len(msg.Runes) > 0 {
+			p.WriteInput(string(msg.Runes))
+		}
+		return m, nil
+	}
+}
+
+func (m Model) handleSettingsInput(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	if m.settingsFilterActive {
+		return m.handleSettingsFilterInput(msg)
+	}
+	if m.showProfileChooser {
+		return m.handleProfileChooserInput(msg)
+	}
+
+	categories := settings.GetCategories()
+	currentCategory := categories[m.settingsCategory]
+
+	switch msg.String() {
+	case "ctrl+c":
+		m.gracefulShutdown()
+		return m, tea.Quit
+
+	case "q":
+		// Require confirmation
+		if m.confirmQuit && time.Since(m.quitTime) < 3*time.Second {
+			m.gracefulShutdown()
+			return m, tea.Quit
+		}
+		m.confirmQuit = true
+		m.quitTime = time.Now()
+		m.setStatus("Press q again to quit")
+		return m, nil
+
+	case "esc", ",":
+		m.showSettings = false
+		return m, nil
+
+	case "x":
+		name := "default"
+		if m.project != nil && m.project.Name != "" {
+			name = m.project.Name
+		}
+		path, err := m.settings.SaveProfile(name)
+		if err != nil {
+			m.setStatus(fmt.Sprintf("Failed to export settings: %v", err))
+		} else {
+			m.setStatus("Exported settings to " + path)
+		}
+		return m, nil
+
+	case "i":
+		names, err := settings.ListProfiles()
+		if err != nil {
+			m.setStatus(fmt.Sprintf("Failed to list profiles: %v", err))
+			return m, nil
+		}
+		if len(names) == 0 {
+			dir, _ := settings.ProfilesDir()
+			m.setStatus("No saved profiles under " + dir)
+			return m, nil
+		}
+		m.profileNames = names
+		m.profileCursor = 0
+		m.profileDiffPending = ""
+		m.profileDiffPreview = nil
+		m.showProfileChooser = true
+		return m, nil
+
+	case "/":
+		m.settingsFilterInput = textinput.New()
+		m.settingsFilterInput.Placeholder = "search settings..."
+		m.settingsFilterInput.Prompt = "🔍 "
+		m.settingsFilterInput.SetValue(m.lastSettingsQuery)
+		m.settingsFilterInput.CursorEnd()
+		m.settingsFilterInput.Focus()
+		m.settingsFilterResults = filterSettings(m.lastSettingsQuery)
+		m.settingsFilterCursor = 0
+		m.settingsFilterActive = true
+		return m, nil
+
+	case "up", "k":
+		if m.settingsCursor > 0 {
+			m.settingsCursor--
+		} else if m.settingsCategory > 0 {
+			// Move to previous category
+			m.settingsCategory--
+			m.settingsCursor = len(categories[m.settingsCategory].Settings) - 1
+		}
+		return m, nil
+
+	case "down", "j":
+		if m.settingsCursor < len(currentCategory.Settings)-1 {
+			m.settingsCursor++
+		} else if m.settingsCategory < len(categories)-1 {
+			// Move to next category
+			m.settingsCategory++
+			m.settingsCursor = 0
+		}
+		return m, nil
+
+	case "left", "h":
+		if m.settingsCategory > 0 {
+			m.settingsCategory--
+			m.settingsCursor = 0
+		}
+		return m, nil
+
+	case "right", "l":
+		if m.settingsCategory < len(categories)-1 {
+			m.settingsCategory++
+			m.settingsCursor = 0
+		}
+		return m, nil
+
+	case "enter", " ":
+		// Toggle or cycle the current setting
+		setting := currentCategory.Settings[m.settingsCursor]
+		switch setting.Type {
+		case "bool":
+			m.settings.ToggleBool(setting.Key)
+			m.settings.Save()
+			m.setStatus(fmt.Sprintf("%s: %v", setting.Name, m.settings.GetBool(setting.Key)))
+		case "choice":
+			newVal := m.settings.CycleChoice(setting.Key, setting.Choices)
+			m.settings.Save()
+			displayVal := newVal
+			if displayVal == "" {
+				displayVal = "(auto)"
+			}
+			if setting.Key == "theme" {
+				m.applyThemeByName(newVal)
+			}
+			m.setStatus(fmt.Sprintf("%s: %s", setting.Name, displayVal))
+		}
+		return m, nil
+	}
+
+	return m, nil
+}
+
+// handleSettingsFilterInput drives the settings panel's "/" search overlay
+// while m.settingsFilterActive is on: navigation and enter/esc are handled
+// here directly, everything else is forwarded to m.settingsFilterInput
+// (bubbles/textinput) and the flattened result list is rescored on every
+// keystroke.
+func (m Model) handleSettingsFilterInput(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "ctrl+c":
+		m.gracefulShutdown()
+		return m, tea.Quit
+
+	case "esc":
+		m.settingsFilterActive = false
+		m.settingsFilterInput.Blur()
+		return m, nil
+
+	case "enter":
+		if len(m.settingsFilterResults) > 0 {
+			r := m.settingsFilterResults[m.settingsFilterCursor]
+			m.settingsCategory = r.categoryIdx
+			m.settingsCursor = r.settingIdx
+		}
+		m.lastSettingsQuery = m.settingsFilterInput.Value()
+		m.settingsFilterActive = false
+		m.settingsFilterInput.Blur()
+		return m, nil
+
+	case "up", "ctrl+p":
+		if m.settingsFilterCursor > 0 {
+			m.settingsFilterCursor--
+		}
+		return m, nil
+
+	case "down", "ctrl+n":
+		if m.settingsFilterCursor < len(m.settingsFilterResults)-1 {
+			m.settingsFilterCursor++
+		}
+		return m, nil
+	}
+
+	var cmd tea.Cmd
+	m.settingsFilterInput, cmd = m.settingsFilterInput.Update(msg)
+	m.settingsFilterResults = filterSettings(m.settingsFilterInput.Value())
+	if m.settingsFilterCursor >= len(m.settingsFilterResults) {
+		m.settingsFilterCursor = 0
+	}
+	return m, cmd
+}
+
+// handleProfileChooserInput drives the settings panel's "i" profile
+// chooser: navigation and a first "enter" stage a settings.DiffProfile
+// preview, a second "enter" on the same entry commits it via LoadProfile.
+func (m Model) handleProfileChooserInput(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "ctrl+c":
+		m.gracefulShutdown()
+		return m, tea.Quit
+
+	case "esc":
+		m.showProfileChooser = false
+		m.profileDiffPending = ""
+		m.profileDiffPreview = nil
+		return m, nil
+
+	case "up", "k":
+		if m.profileDiffPending == "" && m.profileCursor > 0 {
+			m.profileCursor--
+		}
+		return m, nil
+
+	case "down", "j":
+		if m.profileDiffPending == "" && m.profileCursor < len(m.profileNames)-1 {
+			m.profileCursor++
+		}
+		return m, nil
+
+	case "enter":
+		if len(m.profileNames) == 0 {
+			return m, nil
+		}
+		name := m.profileNames[m.profileCursor]
+
+		if m.profileDiffPending == name {
+			if err := m.settings.LoadProfile(name); err != nil {
+				m.setStatus(fmt.Sprintf("Failed to load profile %s: %v", name, err))
+			} else {
+				m.setStatus("Loaded profile " + name)
+			}
+			m.showProfileChooser = false
+			m.profileDiffPending = ""
+			m.profileDiffPreview = nil
+			return m, nil
+		}
+
+		changes, err := m.settings.DiffProfile(name)
+		if err != nil {
+			m.setStatus(fmt.Sprintf("Failed to preview profile %s: %v", name, err))
+			return m, nil
+		}
+		if len(changes) == 0 {
+			m.setStatus("Profile " + name + " matches current settings - nothing to apply")
+			m.showProfileChooser = false
+			return m, nil
+		}
+		m.profileDiffPending = name
+		m.profileDiffPreview = changes
+		return m, nil
+	}
+
+	return m, nil
+}
+
+func (m *Model) renderSettings() string {
+	if m.settingsFilterActive {
+		return m.renderSettingsFilter()
+	}
+	if m.showProfileChooser {
+		return m.renderProfileChooser()
+	}
+
+	categories := settings.GetCategories()
+
+	// Title
+	title := lipgloss.NewStyle().
+		Foreground(capBlue).
+		Bold(true).
 		Render("  ⚡ Settings")
 
 	var lines []string
@@ -1820,12 +3693,12 @@ func (m *Model) renderSettings() string {
 					val = val[:22] + "..."
 				}
 				valueStr = val
-				valueStyle = lipgloss.NewStyle().Foreground(capCyan)
+				valueStyle = settingsValueStyle
 			}
 		case "int":
 			val := m.settings.GetInt(s.Key)
 			valueStr = fmt.Sprintf("%d", val)
-			valueStyle = lipgloss.NewStyle().Foreground(capCyan)
+			valueStyle = settingsValueStyle
 		case "choice":
 			val := m.settings.GetString(s.Key)
 			if val == "" {
@@ -1833,7 +3706,7 @@ func (m *Model) renderSettings() string {
 			} else {
 				valueStr = val
 			}
-			valueStyle = lipgloss.NewStyle().Foreground(capCyan)
+			valueStyle = settingsValueStyle
 		}
 
 		name := nameStyle.Render(s.Name)
@@ -1848,39 +3721,137 @@ func (m *Model) renderSettings() string {
 				Foreground(capDark).
 				Background(capBlue).
 				Bold(true).
-				Render(fmt.Sprintf("▶ %s  %s  %s", nameStyle.Render(s.Name), valueStr, s.Description))
+				Render(fmt.Sprintf("▶ %s  %s  %s", nameStyle.Render(s.Name), valueStr, s.Description))
+		}
+
+		lines = append(lines, line)
+	}
+
+	// Padding
+	for len(lines) < 20 {
+		lines = append(lines, "")
+	}
+
+	// Config file path
+	configPathStr, _ := settings.ConfigPath()
+	lines = append(lines, "")
+	lines = append(lines, mutedStyle.Render(fmt.Sprintf("  Config: %s", configPathStr)))
+
+	// Help
+	lines = append(lines, "")
+	helpLine := helpStyle.Render("  ") +
+		helpKeyStyle.Render("←/→") + helpStyle.Render(" category  ") +
+		helpKeyStyle.Render("↑/↓") + helpStyle.Render(" select  ") +
+		helpKeyStyle.Render("enter") + helpStyle.Render(" toggle  ") +
+		helpKeyStyle.Render("/") + helpStyle.Render(" search  ") +
+		helpKeyStyle.Render("x") + helpStyle.Render(" export  ") +
+		helpKeyStyle.Render("i") + helpStyle.Render(" import  ") +
+		helpKeyStyle.Render("esc") + helpStyle.Render(" close  ") +
+		helpKeyStyle.Render("q") + helpStyle.Render(" quit")
+	lines = append(lines, helpLine)
+
+	return strings.Join(lines, "\n")
+}
+
+// renderProfileChooser renders the settings panel's "i" overlay: a list of
+// saved profiles, or - once one is selected - a green +new/red -old diff
+// preview of what applying it would change, in the same preview-before-
+// apply shape as the marketplace install flow.
+func (m *Model) renderProfileChooser() string {
+	title := lipgloss.NewStyle().Foreground(capBlue).Bold(true).Render("  📦 Settings Profiles")
+
+	var lines []string
+	lines = append(lines, "", title, "")
+
+	if m.profileDiffPending != "" {
+		lines = append(lines, mutedStyle.Render(fmt.Sprintf("  Applying %q will change:", m.profileDiffPending)))
+		lines = append(lines, "")
+		for _, c := range m.profileDiffPreview {
+			lines = append(lines, "    "+lipgloss.NewStyle().Bold(true).Render(c.Key))
+			lines = append(lines, "      "+errorStyle.Render(fmt.Sprintf("- %v", c.Old)))
+			lines = append(lines, "      "+successStyle.Render(fmt.Sprintf("+ %v", c.New)))
+		}
+		lines = append(lines, "")
+		helpLine := helpStyle.Render("  ") +
+			helpKeyStyle.Render("enter") + helpStyle.Render(" apply  ") +
+			helpKeyStyle.Render("esc") + helpStyle.Render(" cancel")
+		lines = append(lines, helpLine)
+		return strings.Join(lines, "\n")
+	}
+
+	for i, name := range m.profileNames {
+		if i == m.profileCursor {
+			lines = append(lines, lipgloss.NewStyle().
+				Foreground(capDark).
+				Background(capBlue).
+				Bold(true).
+				Render("▶ "+name))
+		} else {
+			lines = append(lines, "  "+lipgloss.NewStyle().Foreground(capLight).Render(name))
+		}
+	}
+	if len(m.profileNames) == 0 {
+		lines = append(lines, mutedStyle.Render("  No saved profiles"))
+	}
+
+	lines = append(lines, "")
+	helpLine := helpStyle.Render("  ") +
+		helpKeyStyle.Render("↑/↓") + helpStyle.Render(" select  ") +
+		helpKeyStyle.Render("enter") + helpStyle.Render(" preview  ") +
+		helpKeyStyle.Render("esc") + helpStyle.Render(" cancel")
+	lines = append(lines, helpLine)
+
+	return strings.Join(lines, "\n")
+}
+
+// renderSettingsFilter renders the flattened, scored search results for the
+// settings panel's "/" overlay in place of the normal category-tabbed view.
+func (m *Model) renderSettingsFilter() string {
+	title := lipgloss.NewStyle().Foreground(capBlue).Bold(true).Render("  ⚡ Settings")
+
+	var lines []string
+	lines = append(lines, "", title, "")
+	lines = append(lines, "  "+m.settingsFilterInput.View())
+	lines = append(lines, "")
+
+	for i, r := range m.settingsFilterResults {
+		label := fmt.Sprintf("[%s] %s", r.category, r.setting.Name)
+		if i == m.settingsFilterCursor {
+			lines = append(lines, lipgloss.NewStyle().
+				Foreground(capDark).
+				Background(capBlue).
+				Bold(true).
+				Render(fmt.Sprintf("▶ %s  %s", label, r.setting.Description)))
+		} else {
+			name := lipgloss.NewStyle().Foreground(capLight).Render(label)
+			lines = append(lines, fmt.Sprintf("  %s  %s", name, mutedStyle.Render(r.setting.Description)))
 		}
-
-		lines = append(lines, line)
+	}
+	if len(m.settingsFilterResults) == 0 {
+		lines = append(lines, mutedStyle.Render("  No matching settings"))
 	}
 
-	// Padding
 	for len(lines) < 20 {
 		lines = append(lines, "")
 	}
 
-	// Config file path
-	configPathStr, _ := settings.ConfigPath()
-	lines = append(lines, "")
-	lines = append(lines, mutedStyle.Render(fmt.Sprintf("  Config: %s", configPathStr)))
-
-	// Help
 	lines = append(lines, "")
 	helpLine := helpStyle.Render("  ") +
-		helpKeyStyle.Render("←/→") + helpStyle.Render(" category  ") +
 		helpKeyStyle.Render("↑/↓") + helpStyle.Render(" select  ") +
-		helpKeyStyle.Render("enter") + helpStyle.Render(" toggle  ") +
-		helpKeyStyle.Render("esc") + helpStyle.Render(" close  ") +
-		helpKeyStyle.Render("q") + helpStyle.Render(" quit")
+		helpKeyStyle.Render("enter") + helpStyle.Render(" jump  ") +
+		helpKeyStyle.Render("esc") + helpStyle.Render(" cancel")
 	lines = append(lines, helpLine)
 
 	return strings.Join(lines, "\n")
 }
 
-
 func (m Model) handleDebugInput(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	if m.debugFilterActive {
+		return m.handleDebugFilterInput(msg)
+	}
+
 	categories := debug.GetCategories()
-	
+
 	// Filter actions for current category
 	var currentActions []debug.Action
 	for _, a := range m.debugActions {
@@ -1909,6 +3880,18 @@ func (m Model) handleDebugInput(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 		m.debugConfirm = false
 		return m, nil
 
+	case "/":
+		m.debugFilterInput = textinput.New()
+		m.debugFilterInput.Placeholder = "search actions..."
+		m.debugFilterInput.Prompt = "🔍 "
+		m.debugFilterInput.SetValue(m.lastDebugQuery)
+		m.debugFilterInput.CursorEnd()
+		m.debugFilterInput.Focus()
+		m.debugFilterResults = filterDebugActions(m.debugActions, m.lastDebugQuery)
+		m.debugFilterCursor = 0
+		m.debugFilterActive = true
+		return m, nil
+
 	case "up", "k":
 		if m.debugCursor > 0 {
 			m.debugCursor--
@@ -1943,26 +3926,34 @@ func (m Model) handleDebugInput(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 		if len(currentActions) == 0 {
 			return m, nil
 		}
-		
+
 		action := currentActions[m.debugCursor]
-		
+
+		if _, running := m.debugJobs[action.ID]; running {
+			m.setStatus(action.Name + " is already running")
+			return m, nil
+		}
+
 		// Dangerous actions require confirmation
 		if action.Dangerous && !m.debugConfirm {
 			m.debugConfirm = true
 			m.setStatus("⚠ Press enter again to confirm: " + action.Name)
 			return m, nil
 		}
-		
-		// Run the action
+
+		// Run the action in the background so it can't freeze the TUI
 		m.debugConfirm = false
-		result := debug.RunAction(action.ID)
-		m.debugResult = &result
-		m.debugResultTime = time.Now()
-		
-		if result.Success {
-			m.setStatus("✓ " + result.Message)
-		} else {
-			m.setStatus("✗ " + result.Message)
+		m.setStatus("Running " + action.Name + "...")
+		return m, startDebugAction(action.ID)
+
+	case "c":
+		if len(currentActions) == 0 {
+			return m, nil
+		}
+		action := currentActions[m.debugCursor]
+		if job, ok := m.debugJobs[action.ID]; ok {
+			job.cancel()
+			m.setStatus("Cancelling " + action.Name + "...")
 		}
 		return m, nil
 	}
@@ -1970,7 +3961,74 @@ func (m Model) handleDebugInput(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 	return m, nil
 }
 
+// handleDebugFilterInput drives the debug panel's "/" search overlay,
+// mirroring handleSettingsFilterInput.
+func (m Model) handleDebugFilterInput(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "ctrl+c":
+		m.gracefulShutdown()
+		return m, tea.Quit
+
+	case "esc":
+		m.debugFilterActive = false
+		m.debugFilterInput.Blur()
+		return m, nil
+
+	case "enter":
+		if len(m.debugFilterResults) > 0 {
+			r := m.debugFilterResults[m.debugFilterCursor]
+			m.debugCategory = r.categoryIdx
+			m.debugCursor = 0
+
+			// renderDebug/handleDebugInput index into a per-category action
+			// list they rebuild on the fly from m.debugActions, so the
+			// cursor has to land on the same rebuilt index, not r's
+			// position in the flattened, cross-category results.
+			categories := debug.GetCategories()
+			idx := 0
+			for _, a := range m.debugActions {
+				if a.Category != categories[r.categoryIdx] {
+					continue
+				}
+				if a.ID == r.action.ID {
+					m.debugCursor = idx
+					break
+				}
+				idx++
+			}
+		}
+		m.lastDebugQuery = m.debugFilterInput.Value()
+		m.debugFilterActive = false
+		m.debugFilterInput.Blur()
+		return m, nil
+
+	case "up", "ctrl+p":
+		if m.debugFilterCursor > 0 {
+			m.debugFilterCursor--
+		}
+		return m, nil
+
+	case "down", "ctrl+n":
+		if m.debugFilterCursor < len(m.debugFilterResults)-1 {
+			m.debugFilterCursor++
+		}
+		return m, nil
+	}
+
+	var cmd tea.Cmd
+	m.debugFilterInput, cmd = m.debugFilterInput.Update(msg)
+	m.debugFilterResults = filterDebugActions(m.debugActions, m.debugFilterInput.Value())
+	if m.debugFilterCursor >= len(m.debugFilterResults) {
+		m.debugFilterCursor = 0
+	}
+	return m, cmd
+}
+
 func (m *Model) renderDebug() string {
+	if m.debugFilterActive {
+		return m.renderDebugFilter()
+	}
+
 	categories := debug.GetCategories()
 
 	// Title
@@ -2014,7 +4072,7 @@ func (m *Model) renderDebug() string {
 		// Warning indicator for dangerous actions
 		var dangerIcon string
 		if action.Dangerous {
-			dangerIcon = lipgloss.NewStyle().Foreground(warnColor).Render("⚠ ")
+			dangerIcon = debugDangerStyle.Render("⚠ ")
 		} else {
 			dangerIcon = "  "
 		}
@@ -2022,28 +4080,43 @@ func (m *Model) renderDebug() string {
 		name := action.Name
 		desc := action.Description
 
+		job := m.debugJobs[action.ID]
+
 		if isSelected {
 			// Highlight selected
 			arrow := lipgloss.NewStyle().Foreground(capBlue).Bold(true).Render("▶")
 			nameStyled := lipgloss.NewStyle().Foreground(capCyan).Bold(true).Render(name)
-			
+
 			lines = append(lines, fmt.Sprintf(" %s%s%s", arrow, dangerIcon, nameStyled))
 			lines = append(lines, fmt.Sprintf("      %s", mutedStyle.Render(desc)))
-			
+
 			// Show confirmation prompt for dangerous actions
 			if action.Dangerous && m.debugConfirm {
-				lines = append(lines, fmt.Sprintf("      %s", lipgloss.NewStyle().Foreground(warnColor).Bold(true).Render("Press enter again to confirm")))
+				lines = append(lines, fmt.Sprintf("      %s", debugDangerStyle.Render("Press enter again to confirm")))
 			}
 		} else {
 			nameStyled := lipgloss.NewStyle().Foreground(capLight).Render(name)
 			lines = append(lines, fmt.Sprintf("  %s%s", dangerIcon, nameStyled))
 		}
+
+		if job != nil {
+			ratio := 0.0
+			if job.total > 0 {
+				ratio = float64(job.current) / float64(job.total)
+			}
+			lines = append(lines, fmt.Sprintf("      %s %s", job.bar.ViewAs(ratio), mutedStyle.Render(job.message)))
+		}
 	}
 
 	if len(currentActions) == 0 {
 		lines = append(lines, mutedStyle.Render("  No actions available for this category"))
 	}
 
+	if len(m.debugJobs) > 0 {
+		lines = append(lines, "")
+		lines = append(lines, mutedStyle.Render(fmt.Sprintf("  %d job(s) running", len(m.debugJobs))))
+	}
+
 	// Padding
 	for len(lines) < 18 {
 		lines = append(lines, "")
@@ -2073,6 +4146,8 @@ func (m *Model) renderDebug() string {
 		helpKeyStyle.Render("←/→") + helpStyle.Render(" category  ") +
 		helpKeyStyle.Render("↑/↓") + helpStyle.Render(" select  ") +
 		helpKeyStyle.Render("enter") + helpStyle.Render(" run  ") +
+		helpKeyStyle.Render("c") + helpStyle.Render(" cancel  ") +
+		helpKeyStyle.Render("/") + helpStyle.Render(" search  ") +
 		helpKeyStyle.Render("esc") + helpStyle.Render(" close  ") +
 		helpKeyStyle.Render("q") + helpStyle.Render(" quit")
 	lines = append(lines, helpLine)
@@ -2082,6 +4157,107 @@ func (m *Model) renderDebug() string {
 	return strings.Join(lines, "\n")
 }
 
+// renderDebugFilter renders the flattened, scored search results for the
+// debug panel's "/" overlay, mirroring renderSettingsFilter.
+func (m *Model) renderDebugFilter() string {
+	title := lipgloss.NewStyle().Foreground(capBlue).Bold(true).Render("  🔧 Debug & Cleanup Tools")
+
+	var lines []string
+	lines = append(lines, "", title, "")
+	lines = append(lines, "  "+m.debugFilterInput.View())
+	lines = append(lines, "")
+
+	for i, r := range m.debugFilterResults {
+		var dangerIcon string
+		if r.action.Dangerous {
+			dangerIcon = debugDangerStyle.Render("⚠ ")
+		} else {
+			dangerIcon = "  "
+		}
+		label := fmt.Sprintf("[%s] %s", r.category, r.action.Name)
+		if i == m.debugFilterCursor {
+			lines = append(lines, lipgloss.NewStyle().
+				Foreground(capDark).
+				Background(capBlue).
+				Bold(true).
+				Render(fmt.Sprintf("▶ %s%s  %s", dangerIcon, label, r.action.Description)))
+		} else {
+			name := lipgloss.NewStyle().Foreground(capLight).Render(label)
+			lines = append(lines, fmt.Sprintf("  %s%s  %s", dangerIcon, name, mutedStyle.Render(r.action.Description)))
+		}
+	}
+	if len(m.debugFilterResults) == 0 {
+		lines = append(lines, mutedStyle.Render("  No matching actions"))
+	}
+
+	for len(lines) < 18 {
+		lines = append(lines, "")
+	}
+
+	lines = append(lines, "")
+	helpLine := helpStyle.Render("  ") +
+		helpKeyStyle.Render("↑/↓") + helpStyle.Render(" select  ") +
+		helpKeyStyle.Render("enter") + helpStyle.Render(" jump  ") +
+		helpKeyStyle.Render("esc") + helpStyle.Render(" cancel")
+	lines = append(lines, helpLine)
+
+	return strings.Join(lines, "\n")
+}
+
+// applyThemeByName loads the named styleset (from settings.StylesetsDir(),
+// falling back to lazycap's embedded ones) and rebuilds every lipgloss
+// style in internal/ui from it, live - see styles.go's applyTheme. Falls
+// back to theme.Default() (and reports the failure) if name can't be
+// loaded.
+func (m *Model) applyThemeByName(name string) {
+	if name == "" {
+		name = theme.DefaultName
+	}
+	dir, _ := settings.StylesetsDir()
+	ss, err := theme.Load(name, dir)
+	if err != nil {
+		m.setStatus(fmt.Sprintf("Failed to load styleset %q: %v", name, err))
+		ss = theme.Default()
+	}
+	applyTheme(ss)
+}
+
+// pluginManifestURL returns the configured marketplace manifest URL, or ""
+// if the Available tab should stay disabled (see the "Plugins" settings
+// category).
+func (m *Model) pluginManifestURL() string {
+	if m.settings == nil {
+		return ""
+	}
+	return m.settings.GetString("pluginManifestURL")
+}
+
+// handlePluginSearchInput captures raw keystrokes into m.pluginSearchQuery
+// while m.pluginSearchActive is on, mirroring handlePTYInput's
+// capture-everything approach. Enter runs the search; esc cancels it.
+func (m Model) handlePluginSearchInput(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "esc":
+		m.pluginSearchActive = false
+		return m, nil
+	case "enter":
+		m.pluginSearchActive = false
+		m.marketplaceLoading = true
+		return m, loadMarketplace(m.pluginRegistry, m.pluginSearchQuery)
+	case "backspace":
+		if len(m.pluginSearchQuery) > 0 {
+			runes := []rune(m.pluginSearchQuery)
+			m.pluginSearchQuery = string(runes[:len(runes)-1])
+		}
+		return m, nil
+	default:
+		if len(msg.Runes) > 0 {
+			m.pluginSearchQuery += string(msg.Runes)
+		}
+		return m, nil
+	}
+}
+
 func (m Model) handlePluginsInput(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 	if m.pluginManager == nil {
 		// No plugin manager, just close
@@ -2089,6 +4265,14 @@ func (m Model) handlePluginsInput(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 		return m, nil
 	}
 
+	if m.pluginSearchActive {
+		return m.handlePluginSearchInput(msg)
+	}
+
+	if m.pluginsTab == 1 {
+		return m.handleMarketplaceInput(msg)
+	}
+
 	allPlugins := plugin.All()
 
 	switch msg.String() {
@@ -2110,6 +4294,9 @@ func (m Model) handlePluginsInput(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 		m.showPlugins = false
 		return m, nil
 
+	case "tab":
+		return m.switchToMarketplaceTab()
+
 	case "up", "k":
 		if m.pluginCursor > 0 {
 			m.pluginCursor--
@@ -2146,23 +4333,223 @@ func (m Model) handlePluginsInput(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 		// Toggle enabled state
 		if len(allPlugins) > 0 && m.pluginCursor < len(allPlugins) {
 			p := allPlugins[m.pluginCursor]
-			enabled := m.pluginManager.IsEnabled(p.ID())
-			if err := m.pluginManager.SetEnabled(p.ID(), !enabled); err != nil {
-				m.setStatus(fmt.Sprintf("Failed to toggle %s: %v", p.Name(), err))
-			} else {
-				if enabled {
-					m.setStatus(fmt.Sprintf("Disabled %s", p.Name()))
+
+			if m.pluginManager.IsEnabled(p.ID()) {
+				m.pluginEnablePlan = nil
+				if err := m.pluginManager.SetEnabled(p.ID(), false); err != nil {
+					m.setStatus(fmt.Sprintf("Failed to disable %s: %v", p.Name(), err))
 				} else {
-					m.setStatus(fmt.Sprintf("Enabled %s", p.Name()))
+					if m.pluginContext != nil {
+						m.pluginContext.NotifyPluginDisabled(p.ID())
+					}
+					m.setStatus(fmt.Sprintf("Disabled %s", p.Name()))
+				}
+				return m, nil
+			}
+
+			// A pending plan for this exact plugin means the user already
+			// saw the auto-enable confirmation and pressed "e" again.
+			if m.pluginEnablePlan != nil && m.pluginEnableTarget == p.ID() {
+				plan := m.pluginEnablePlan
+				m.pluginEnablePlan = nil
+				m.pluginEnableTarget = ""
+				for _, id := range plan.StartOrder {
+					if err := m.pluginManager.SetEnabled(id, true); err != nil {
+						m.setStatus(fmt.Sprintf("Failed to enable %s: %v", id, err))
+						return m, nil
+					}
+					if m.pluginContext != nil {
+						m.pluginContext.NotifyPluginEnabled(id)
+					}
+				}
+				m.setStatus(fmt.Sprintf("Enabled %s", p.Name()))
+				return m, nil
+			}
+
+			plan, err := m.resolvePluginEnable(p.ID())
+			if err != nil {
+				m.setStatus(fmt.Sprintf("Cannot enable %s: %v", p.Name(), err))
+				return m, nil
+			}
+			if len(plan.AutoEnabled) > 0 {
+				m.pluginEnablePlan = plan
+				m.pluginEnableTarget = p.ID()
+				m.setStatus(fmt.Sprintf("Enabling %s also requires %s - press e again to confirm", p.Name(), strings.Join(plan.AutoEnabled, ", ")))
+				return m, nil
+			}
+			if err := m.pluginManager.SetEnabled(p.ID(), true); err != nil {
+				m.setStatus(fmt.Sprintf("Failed to enable %s: %v", p.Name(), err))
+			} else {
+				if m.pluginContext != nil {
+					m.pluginContext.NotifyPluginEnabled(p.ID())
 				}
+				m.setStatus(fmt.Sprintf("Enabled %s", p.Name()))
 			}
 		}
 		return m, nil
+
+	case "x":
+		// Uninstall the selected plugin
+		if len(allPlugins) > 0 && m.pluginCursor < len(allPlugins) {
+			p := allPlugins[m.pluginCursor]
+			m.pluginActionStatus[p.ID()] = "removing..."
+			return m, removePluginCmd(m.pluginManager, m.pluginContext, p.ID())
+		}
+		return m, nil
+	}
+
+	return m, nil
+}
+
+// switchToMarketplaceTab flips to the Available tab, disabling it (with an
+// explanatory status) if no marketplace manifest URL is configured, and
+// triggering a load the first time it's opened.
+func (m Model) switchToMarketplaceTab() (tea.Model, tea.Cmd) {
+	if m.pluginsTab == 1 {
+		m.pluginsTab = 0
+		return m, nil
+	}
+
+	url := m.pluginManifestURL()
+	if url == "" {
+		m.setStatus("Plugin marketplace disabled - set pluginManifestURL in Settings > Plugins")
+		return m, nil
+	}
+
+	m.pluginsTab = 1
+	if m.pluginRegistry == nil {
+		m.pluginRegistry = plugin.NewRegistry(url)
+	}
+	if len(m.marketplaceEntries) == 0 && !m.marketplaceLoading {
+		m.marketplaceLoading = true
+		return m, loadMarketplace(m.pluginRegistry, "")
+	}
+	return m, nil
+}
+
+func (m Model) handleMarketplaceInput(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "ctrl+c":
+		m.gracefulShutdown()
+		return m, tea.Quit
+
+	case "esc", "P":
+		m.showPlugins = false
+		return m, nil
+
+	case "tab":
+		return m.switchToMarketplaceTab()
+
+	case "up", "k":
+		if m.marketplaceCursor > 0 {
+			m.marketplaceCursor--
+		}
+		return m, nil
+
+	case "down", "j":
+		if m.marketplaceCursor < len(m.marketplaceEntries)-1 {
+			m.marketplaceCursor++
+		}
+		return m, nil
+
+	case "/":
+		m.pluginSearchActive = true
+		m.pluginSearchQuery = ""
+		return m, nil
+
+	case "i":
+		if entry, ok := m.selectedMarketplaceEntry(); ok {
+			m.pluginActionStatus[entry.ID] = "installing..."
+			return m, installPluginCmd(m.pluginRegistry, m.pluginManager, m.pluginContext, entry)
+		}
+		return m, nil
+
+	case "U":
+		if entry, ok := m.selectedMarketplaceEntry(); ok {
+			m.pluginActionStatus[entry.ID] = "updating..."
+			return m, updatePluginCmd(m.pluginRegistry, m.pluginManager, m.pluginContext, entry)
+		}
+		return m, nil
+
+	case "u":
+		if len(m.marketplaceEntries) == 0 {
+			return m, nil
+		}
+		for _, e := range m.marketplaceEntries {
+			m.pluginActionStatus[e.ID] = "updating..."
+		}
+		return m, updateAllPluginsCmd(m.pluginRegistry, m.pluginManager, m.pluginContext, m.marketplaceEntries)
+
+	case "x":
+		if entry, ok := m.selectedMarketplaceEntry(); ok {
+			m.pluginActionStatus[entry.ID] = "removing..."
+			return m, removePluginCmd(m.pluginManager, m.pluginContext, entry.ID)
+		}
+		return m, nil
 	}
 
 	return m, nil
 }
 
+// selectedMarketplaceEntry returns the marketplace entry under the cursor,
+// if any.
+func (m Model) selectedMarketplaceEntry() (plugin.RegistryEntry, bool) {
+	if len(m.marketplaceEntries) == 0 || m.marketplaceCursor >= len(m.marketplaceEntries) {
+		return plugin.RegistryEntry{}, false
+	}
+	return m.marketplaceEntries[m.marketplaceCursor], true
+}
+
+// isPluginInstalled reports whether id is already registered locally (see
+// plugin.All()), used to label marketplace entries as "installed".
+func (m *Model) isPluginInstalled(id string) bool {
+	for _, p := range plugin.All() {
+		if p.ID() == id {
+			return true
+		}
+	}
+	return false
+}
+
+// resolvePluginEnable runs plugin.Resolve for enabling id alongside
+// whatever's already enabled, against the marketplace manifest's declared
+// `requires` constraints (see RegistryEntry.Requires). Locally registered
+// plugins the manifest doesn't know about (built-ins, or when no
+// marketplace URL is configured at all) are seeded in as dependency-free
+// candidates for themselves, so enabling a plain plugin with no
+// dependencies still resolves to a trivial one-plugin plan.
+func (m *Model) resolvePluginEnable(id string) (*plugin.Plan, error) {
+	var entries []plugin.RegistryEntry
+	if url := m.pluginManifestURL(); url != "" {
+		if m.pluginRegistry == nil {
+			m.pluginRegistry = plugin.NewRegistry(url)
+		}
+		entries = m.pluginRegistry.Entries()
+		if len(entries) == 0 {
+			if err := m.pluginRegistry.Refresh(); err == nil {
+				entries = m.pluginRegistry.Entries()
+			}
+		}
+	}
+
+	known := make(map[string]bool, len(entries))
+	for _, e := range entries {
+		known[e.ID] = true
+	}
+
+	enabledIDs := []string{id}
+	for _, p := range plugin.All() {
+		if !known[p.ID()] {
+			entries = append(entries, plugin.RegistryEntry{ID: p.ID(), Version: "0.0.0"})
+		}
+		if p.ID() != id && m.pluginManager.IsEnabled(p.ID()) {
+			enabledIDs = append(enabledIDs, p.ID())
+		}
+	}
+
+	return plugin.Resolve(enabledIDs, entries)
+}
+
 func (m *Model) renderPlugins() string {
 	// Title
 	title := lipgloss.NewStyle().
@@ -2184,6 +4571,14 @@ func (m *Model) renderPlugins() string {
 		return strings.Join(lines, "\n")
 	}
 
+	lines = append(lines, "  "+m.renderPluginTabs())
+	lines = append(lines, "")
+
+	if m.pluginsTab == 1 {
+		lines = append(lines, m.renderMarketplace()...)
+		return strings.Join(lines, "\n")
+	}
+
 	allPlugins := plugin.All()
 
 	if len(allPlugins) == 0 {
@@ -2197,7 +4592,7 @@ func (m *Model) renderPlugins() string {
 			// Status indicator
 			var status string
 			if isRunning {
-				status = successStyle.Render("● running")
+				status = pluginRunningStyle.Render("● running")
 			} else if isEnabled {
 				status = mutedStyle.Render("○ stopped")
 			} else {
@@ -2250,9 +4645,103 @@ func (m *Model) renderPlugins() string {
 		helpKeyStyle.Render("↑/↓") + helpStyle.Render(" select  ") +
 		helpKeyStyle.Render("enter") + helpStyle.Render(" start/stop  ") +
 		helpKeyStyle.Render("e") + helpStyle.Render(" enable/disable  ") +
+		helpKeyStyle.Render("x") + helpStyle.Render(" uninstall  ") +
+		helpKeyStyle.Render("tab") + helpStyle.Render(" marketplace  ") +
 		helpKeyStyle.Render("esc") + helpStyle.Render(" close  ") +
 		helpKeyStyle.Render("q") + helpStyle.Render(" quit")
 	lines = append(lines, helpLine)
 
 	return strings.Join(lines, "\n")
 }
+
+// renderPluginTabs renders the Installed/Available tab header.
+func (m *Model) renderPluginTabs() string {
+	tabStyle := mutedStyle
+	activeTabStyle := lipgloss.NewStyle().Foreground(capCyan).Bold(true).Underline(true)
+
+	installed, available := tabStyle, tabStyle
+	if m.pluginsTab == 0 {
+		installed = activeTabStyle
+	} else {
+		available = activeTabStyle
+	}
+	return installed.Render("Installed") + "   " + available.Render("Available")
+}
+
+// renderMarketplace renders the Available tab: the search box (if active
+// or non-empty), the loading/disabled/empty states, and the list of
+// marketplace entries with per-entry action status.
+func (m *Model) renderMarketplace() []string {
+	var lines []string
+
+	if m.pluginManifestURL() == "" {
+		lines = append(lines, mutedStyle.Render("  No marketplace manifest configured (set pluginManifestURL in Settings > Plugins)"))
+		for len(lines) < 18 {
+			lines = append(lines, "")
+		}
+		lines = append(lines, "")
+		lines = append(lines, helpStyle.Render("  ")+helpKeyStyle.Render("tab")+helpStyle.Render(" installed  ")+helpKeyStyle.Render("esc")+helpStyle.Render(" close"))
+		return lines
+	}
+
+	if m.pluginSearchActive {
+		lines = append(lines, "  "+lipgloss.NewStyle().Foreground(capCyan).Render("Search: "+m.pluginSearchQuery+"█"))
+	} else if m.pluginSearchQuery != "" {
+		lines = append(lines, "  "+mutedStyle.Render("Search: \""+m.pluginSearchQuery+"\" (press / to change)"))
+	}
+	lines = append(lines, "")
+
+	switch {
+	case m.marketplaceLoading:
+		lines = append(lines, "  "+m.spinner.View()+" Loading marketplace...")
+	case len(m.marketplaceEntries) == 0:
+		lines = append(lines, mutedStyle.Render("  No plugins found"))
+	default:
+		for i, e := range m.marketplaceEntries {
+			isSelected := i == m.marketplaceCursor
+			status := m.pluginActionStatus[e.ID]
+			if status == "" && m.isPluginInstalled(e.ID) {
+				status = "installed"
+			}
+
+			var statusStyled string
+			switch {
+			case status == "":
+				statusStyled = ""
+			case status == "installed":
+				statusStyled = successStyle.Render("● " + status)
+			default:
+				statusStyled = mutedStyle.Render("○ " + status)
+			}
+
+			if isSelected {
+				arrow := lipgloss.NewStyle().Foreground(capBlue).Bold(true).Render("▶")
+				nameStyled := lipgloss.NewStyle().Foreground(capCyan).Bold(true).Render(e.ID)
+				lines = append(lines, fmt.Sprintf(" %s %s  %s  %s", arrow, nameStyled, mutedStyle.Render("v"+e.Version), statusStyled))
+				lines = append(lines, fmt.Sprintf("      %s", mutedStyle.Render(e.Description)))
+				lines = append(lines, "")
+			} else {
+				nameStyled := lipgloss.NewStyle().Foreground(capLight).Render(e.ID)
+				lines = append(lines, fmt.Sprintf("   %s  %s  %s", nameStyled, mutedStyle.Render("v"+e.Version), statusStyled))
+			}
+		}
+	}
+
+	for len(lines) < 18 {
+		lines = append(lines, "")
+	}
+
+	lines = append(lines, "")
+	helpLine := helpStyle.Render("  ") +
+		helpKeyStyle.Render("↑/↓") + helpStyle.Render(" select  ") +
+		helpKeyStyle.Render("i") + helpStyle.Render(" install  ") +
+		helpKeyStyle.Render("U") + helpStyle.Render(" update  ") +
+		helpKeyStyle.Render("u") + helpStyle.Render(" update all  ") +
+		helpKeyStyle.Render("x") + helpStyle.Render(" uninstall  ") +
+		helpKeyStyle.Render("/") + helpStyle.Render(" search  ") +
+		helpKeyStyle.Render("tab") + helpStyle.Render(" installed  ") +
+		helpKeyStyle.Render("esc") + helpStyle.Render(" close")
+	lines = append(lines, helpLine)
+
+	return lines
+}
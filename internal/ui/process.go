@@ -1,8 +1,14 @@
 package ui
 
 import (
+	"os"
 	"os/exec"
+	"sync"
 	"time"
+
+	"github.com/icarus-itcs/lazycap/internal/logbuffer"
+	"github.com/icarus-itcs/lazycap/internal/logparse"
+	"github.com/icarus-itcs/lazycap/internal/session"
 )
 
 // ProcessStatus represents the state of a process
@@ -13,6 +19,21 @@ const (
 	ProcessSuccess
 	ProcessFailed
 	ProcessCancelled
+	// ProcessBackoff is a supervised process waiting out an exponential
+	// backoff delay before its next auto-restart attempt (see
+	// Model.scheduleRestart).
+	ProcessBackoff
+	// ProcessFatal is a supervised process that crashed too quickly to be
+	// worth retrying, or that has exhausted StartRetries.
+	ProcessFatal
+	// ProcessScheduled is a queued job (see JobID) waiting for its
+	// ScheduledAt time before it becomes runnable.
+	ProcessScheduled
+	// ProcessRetry is a queued job waiting out its backoff delay after a
+	// prior attempt failed (see jobqueue.Store.MarkFailed).
+	ProcessRetry
+	// ProcessDead is a queued job that exhausted MaxRetries.
+	ProcessDead
 )
 
 // Process represents a running or completed command
@@ -24,9 +45,107 @@ type Process struct {
 	StartTime  time.Time
 	EndTime    time.Time
 	Logs       []string
+	Entries    []logparse.Entry
 	Cmd        *exec.Cmd
 	OutputChan chan string
 	Error      error
+
+	// Progress tracks structured stage/step/detail updates for this
+	// process (see ParseProgressLine), rendered next to StatusIcon() in
+	// the process tab bar. Always non-nil once created via createProcess.
+	Progress *Progress
+
+	// URLMatcher tracks any Local:/Network: dev-server URL seen in this
+	// process's output, for the live-reload URL/QR overlay.
+	URLMatcher logparse.DevServerURLMatcher
+
+	// LogPath is where this process's output is mirrored on disk, for
+	// session persistence/reattach across lazycap restarts (see the
+	// session package). Empty if persistence couldn't be set up.
+	LogPath string
+	// LogFile is the open handle LogPath is written through while the
+	// process is live; nil for historical (already-finished) sessions
+	// loaded back from disk.
+	LogFile *os.File
+
+	// RingLog mirrors every AddLog line into a ring-buffered, gzip-segmented
+	// on-disk log independent of LogPath/LogFile (see openLogBuffer), so
+	// output that has scrolled out of Logs is still searchable/exportable
+	// long after the fact. Nil if it couldn't be set up.
+	RingLog *logbuffer.LogBuffer
+
+	// UsePTY requests a real pty for this process (see runCmdWithPTYOrPipes)
+	// instead of plain stdout/stderr pipes, so interactive prompts and
+	// TTY-only output (e.g. colorized `cap run` progress) render as they
+	// would in a real terminal. Falls back to pipes automatically if pty
+	// allocation fails (e.g. on Windows).
+	UsePTY bool
+	// ptyMaster is the pty master end while the process is live and was
+	// actually started with a PTY; nil otherwise. WriteInput forwards
+	// keystrokes through it.
+	ptyMaster *os.File
+
+	// Supervisor state (see Model.scheduleRestart). AutoRestart opts a
+	// process into supervisord-style auto-restart with exponential
+	// backoff; StartRetries/StartSeconds configure how forgiving that is.
+	AutoRestart  bool
+	StartRetries int
+	StartSeconds int
+	RetriesLeft  int
+	// RestartAt is when the next auto-restart attempt will fire, valid
+	// while Status == ProcessBackoff.
+	RestartAt time.Time
+	// restartGen is bumped whenever a pending restart should be
+	// abandoned (AutoRestart toggled off, or the tab killed) so the
+	// scheduled tea.Cmd can no-op instead of restarting a process nobody
+	// wants running anymore.
+	restartGen int
+
+	// JobID is set when this process was launched from the job queue
+	// (see Model.waitJobReady) rather than directly by the user, linking
+	// it back to its jobqueue.Job for MarkDone/MarkFailed reporting.
+	// Empty for ordinary, non-queued processes.
+	JobID       string
+	Retries     int
+	MaxRetries  int
+	ScheduledAt time.Time
+	NextRetryAt time.Time
+
+	// Resource sampling (see startResourceSampler/Sample), populated from
+	// gopsutil on a timer rather than on every log line. resourceMu
+	// guards the fields below since the sampler goroutine writes them
+	// while the Bubble Tea event loop reads them from render.
+	resourceMu      sync.Mutex
+	State           ProcState
+	CPUPercent      float64
+	RSSBytes        uint64
+	NumThreads      int32
+	IOReadBytes     uint64
+	IOWriteBytes    uint64
+	resourceHistory []ResourceSample
+	// stopSampler signals startResourceSampler's goroutine to exit; closed
+	// once, when the process finishes (see processFinishedMsg).
+	stopSampler chan struct{}
+
+	// Process tree (see Signal/GracefulStop in processtree.go). ParentID
+	// and Children link this Process to others in m.processes the way
+	// ProcessGroup links a mirrored run's members to its aggregate tab;
+	// PGID is the OS process group captured at spawn time via setPgid,
+	// letting Signal's cascade reach the whole tree of real OS processes
+	// underneath a single shell invocation (a pipeline, `make -j`, ...)
+	// even when lazycap never gave most of them their own Process.
+	ParentID string
+	Children []string
+	PGID     int
+
+	// Live is non-nil when this process is the one that actually owns a
+	// shared session.LiveProcess, i.e. it's the first session to start
+	// this exact command for this project - see Model.startOrAttach. Every
+	// AddLog call broadcasts through it so any other session attached to
+	// the same LiveProcess sees the same output. liveKey is what it was
+	// registered under, needed to Release it once the process finishes.
+	Live    *session.LiveProcess
+	liveKey string
 }
 
 // Duration returns how long the process has been running or ran
@@ -37,8 +156,19 @@ func (p *Process) Duration() time.Duration {
 	return p.EndTime.Sub(p.StartTime)
 }
 
-// StatusIcon returns an icon representing the process status
+// StatusIcon returns an icon representing the process status. For a
+// still-ProcessRunning process, the underlying OS process's State (see
+// Sample) further distinguishes a hung/zombie/stopped PID from one that's
+// genuinely making progress, rather than reporting a generic "running".
 func (p *Process) StatusIcon() string {
+	if p.Status == ProcessRunning {
+		switch p.State {
+		case StateZombie:
+			return "🧟"
+		case StateStopped, StateTracingStop:
+			return "⏸"
+		}
+	}
 	switch p.Status {
 	case ProcessRunning:
 		return "◐" // Will be replaced with spinner
@@ -48,16 +178,104 @@ func (p *Process) StatusIcon() string {
 		return "✗"
 	case ProcessCancelled:
 		return "○"
+	case ProcessBackoff:
+		return "⟳"
+	case ProcessFatal:
+		return "☠"
+	case ProcessScheduled:
+		return "⏰"
+	case ProcessRetry:
+		return "⟳"
+	case ProcessDead:
+		return "☠"
 	default:
 		return "?"
 	}
 }
 
-// AddLog adds a log line to the process
+// AddLog adds a log line to the process, parsing it into a structured
+// Entry (severity, timestamp, file reference) alongside the raw text.
 func (p *Process) AddLog(line string) {
 	p.Logs = append(p.Logs, line)
+	p.Entries = append(p.Entries, logparse.Parse(line))
+	p.URLMatcher.Feed(line)
+	if p.Progress != nil {
+		if key, value, ok := ParseProgressLine(line); ok {
+			p.Progress.Message(key, value)
+		}
+	}
 	// Keep max 5000 lines per process
 	if len(p.Logs) > 5000 {
 		p.Logs = p.Logs[len(p.Logs)-5000:]
+		p.Entries = p.Entries[len(p.Entries)-5000:]
+	}
+	if p.LogFile != nil {
+		p.LogFile.WriteString(line + "\n")
+	}
+	if p.RingLog != nil {
+		// runCmdWithPTYOrPipes funnels stdout and stderr into the same
+		// OutputChan, so the distinction is already lost by the time it
+		// reaches AddLog; tag everything Stdout until that plumbing carries
+		// the stream through too.
+		p.RingLog.Append(logbuffer.Stdout, line)
+	}
+	if p.Live != nil {
+		p.Live.Broadcast(line)
+	}
+}
+
+// WriteInput forwards input (e.g. an answer to an interactive y/n prompt)
+// to this process's pty. No-op if the process has no live pty, which is
+// the case for UsePTY-false processes and for ones where pty allocation
+// fell back to plain pipes.
+func (p *Process) WriteInput(s string) error {
+	if p.ptyMaster == nil {
+		return nil
+	}
+	_, err := p.ptyMaster.WriteString(s)
+	return err
+}
+
+// ErrorIndices returns the positions in Entries classified as LevelError,
+// in order, for jump-to-next/prev-error navigation.
+func (p *Process) ErrorIndices() []int {
+	var out []int
+	for i, e := range p.Entries {
+		if e.Level == logparse.LevelError {
+			out = append(out, i)
+		}
+	}
+	return out
+}
+
+// ProcessGroup ties together the per-device processes spawned by a
+// mirrored run (see Model.startMirrorRun) with the synthetic aggregate
+// process that interleaves all of their output into one tab.
+type ProcessGroup struct {
+	ID                 string
+	Name               string
+	ProcessIDs         []string
+	AggregateProcessID string
+}
+
+// Counts returns the running/success/failed tallies across the group's
+// member processes, for summary display (e.g. in the terminal title).
+func (g *ProcessGroup) Counts(processes []*Process) (running, success, failed int) {
+	for _, id := range g.ProcessIDs {
+		for _, p := range processes {
+			if p.ID != id {
+				continue
+			}
+			switch p.Status {
+			case ProcessRunning:
+				running++
+			case ProcessSuccess:
+				success++
+			case ProcessFailed, ProcessCancelled:
+				failed++
+			}
+			break
+		}
 	}
+	return running, success, failed
 }
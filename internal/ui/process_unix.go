@@ -0,0 +1,38 @@
+//go:build !windows
+
+package ui
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"syscall"
+)
+
+// setPgid makes cmd the leader of its own process group once started, so
+// Process.Signal's cascade can reach every OS process it spawns (a shell
+// pipeline, `make -j`, ...) via the negative-PID convention, not just the
+// shell itself.
+func setPgid(cmd *exec.Cmd) {
+	if cmd.SysProcAttr == nil {
+		cmd.SysProcAttr = &syscall.SysProcAttr{}
+	}
+	cmd.SysProcAttr.Setpgid = true
+}
+
+// signalProcessGroup delivers sig to every process in pgid's group via
+// the kill(2) negative-PID convention.
+func signalProcessGroup(pgid int, sig os.Signal) error {
+	s, ok := sig.(syscall.Signal)
+	if !ok {
+		return fmt.Errorf("unsupported signal type %T", sig)
+	}
+	return syscall.Kill(-pgid, s)
+}
+
+// killTree is unix's fallback when a Process has no PGID recorded (e.g. it
+// exited before Signal was called) - unlike Windows, a plain SIGKILL on
+// the leader PID is available even without the process group.
+func killTree(pid int) error {
+	return syscall.Kill(pid, syscall.SIGKILL)
+}
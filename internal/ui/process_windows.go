@@ -0,0 +1,29 @@
+//go:build windows
+
+package ui
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"strconv"
+)
+
+// setPgid is a no-op on Windows: there's no POSIX process group to join,
+// so cascade signalling falls back entirely to killTree's taskkill /T.
+func setPgid(cmd *exec.Cmd) {}
+
+// signalProcessGroup has no Windows equivalent - os.Interrupt itself isn't
+// deliverable there (the stdlib os package documents this), and there's
+// no process group to target either. Callers fall back to killTree.
+func signalProcessGroup(pgid int, sig os.Signal) error {
+	return fmt.Errorf("process groups are not supported on windows")
+}
+
+// killTree force-kills pid and every descendant via taskkill /T /F, the
+// same fallback telegraf's processes plugin and most Windows process
+// managers use since there's no SIGTERM-equivalent graceful signal to
+// send to a process tree.
+func killTree(pid int) error {
+	return exec.Command("taskkill", "/PID", strconv.Itoa(pid), "/T", "/F").Run()
+}
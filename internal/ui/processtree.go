@@ -0,0 +1,86 @@
+package ui
+
+import (
+	"fmt"
+	"os"
+	"runtime"
+	"syscall"
+	"time"
+)
+
+// Signal delivers sig to p's underlying OS process. With cascade true it
+// targets the whole subtree instead of just the leader: on unix that's
+// the process group captured at spawn time (see PGID, setPgid); on
+// Windows, where neither process groups nor arbitrary signal delivery are
+// available (os.Interrupt itself isn't deliverable there), cascade always
+// force-kills the tree via killTree regardless of sig.
+func (p *Process) Signal(sig os.Signal, cascade bool) error {
+	if p.Cmd == nil || p.Cmd.Process == nil {
+		return fmt.Errorf("process %s has no live OS process", p.ID)
+	}
+
+	if cascade {
+		if runtime.GOOS == "windows" {
+			return killTree(p.Cmd.Process.Pid)
+		}
+		if p.PGID != 0 {
+			return signalProcessGroup(p.PGID, sig)
+		}
+	}
+
+	return p.Cmd.Process.Signal(sig)
+}
+
+// gracefulStopPoll is how often GracefulStop checks whether p has exited
+// while waiting out its timeout.
+const gracefulStopPoll = 100 * time.Millisecond
+
+// GracefulStop asks p's whole subtree to exit with SIGTERM, then escalates
+// to SIGKILL if it's still running after timeout - the same
+// os.Interrupt-then-os.Kill escalation the stdlib os package leaves to
+// the caller, automated here. Windows has no SIGTERM-equivalent it can
+// deliver, so it goes straight to killTree's taskkill /T /F.
+func (p *Process) GracefulStop(timeout time.Duration) error {
+	if p.Cmd == nil || p.Cmd.Process == nil {
+		return fmt.Errorf("process %s has no live OS process", p.ID)
+	}
+	if runtime.GOOS == "windows" {
+		return killTree(p.Cmd.Process.Pid)
+	}
+
+	if err := p.Signal(syscall.SIGTERM, true); err != nil {
+		return err
+	}
+
+	deadline := time.Now().Add(timeout)
+	for time.Now().Before(deadline) {
+		if p.Status != ProcessRunning {
+			return nil
+		}
+		time.Sleep(gracefulStopPoll)
+	}
+	if p.Status != ProcessRunning {
+		return nil
+	}
+	return p.Signal(syscall.SIGKILL, true)
+}
+
+// killSubtree force-kills p and, recursively, every Process in
+// m.processes it lists as a Children (e.g. a mirrored run's per-device
+// members under their aggregate tab), cascading each one's own OS process
+// group along the way (see Process.Signal). Used by the Kill key so
+// cancelling a parent tab takes its whole tree down in one press.
+func (m *Model) killSubtree(p *Process) {
+	if p == nil {
+		return
+	}
+	for _, childID := range p.Children {
+		m.killSubtree(m.findProcess(childID))
+	}
+	if p.Status == ProcessRunning && p.Cmd != nil && p.Cmd.Process != nil {
+		p.Signal(os.Kill, true)
+		p.Status = ProcessCancelled
+		p.EndTime = time.Now()
+		p.AddLog("Killed by user")
+	}
+}
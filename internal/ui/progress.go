@@ -0,0 +1,208 @@
+package ui
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"reflect"
+	"strings"
+	"sync"
+)
+
+// ProgressSnapshot is a point-in-time copy of a Progress's state, safe to
+// read, JSON-encode, or compare without holding any lock - see
+// Progress.Snapshot and Progress.Serialize.
+type ProgressSnapshot struct {
+	Stage      string            `json:"stage,omitempty"`
+	StageIndex int               `json:"stage_index"`
+	StageCount int               `json:"stage_count"`
+	Messages   map[string]string `json:"messages,omitempty"`
+	Done       bool              `json:"done"`
+	Err        string            `json:"error,omitempty"`
+	Result     any               `json:"result,omitempty"`
+}
+
+// Progress models a long-running Process as an ordered set of named stages
+// plus arbitrary key/value messages (e.g. Step: "3/7", Detail: "42MB of
+// 128MB"), following the shape of skia's progress package. Every mutating
+// method notifies Subscribe channels so the TUI can redraw on change
+// rather than on every log line (see renderRight and ParseProgressLine).
+// A Progress is safe for concurrent use - logs are fed to it from the
+// process output goroutine while the TUI reads it from Update/View.
+type Progress struct {
+	mu sync.Mutex
+
+	stages     []string
+	stageIndex int
+	stageLabel string
+
+	messages map[string]string
+
+	done   bool
+	err    error
+	result any
+
+	last ProgressSnapshot
+	subs []chan ProgressSnapshot
+}
+
+// NewProgress returns a Progress over the given ordered stage names.
+// Commands that don't know their stages up front (e.g. stdout-parsed
+// progress, see ParseProgressLine) can pass none and rely on Message's
+// "stage" key instead.
+func NewProgress(stages ...string) *Progress {
+	return &Progress{stages: stages, messages: make(map[string]string)}
+}
+
+// Message records an arbitrary key/value update (e.g. "detail", "42MB of
+// 128MB"). The key "stage" is special-cased to set the current free-form
+// stage label directly, for commands whose stage names aren't known
+// ahead of time.
+func (p *Progress) Message(key, value string) {
+	p.mu.Lock()
+	if key == "stage" {
+		p.stageLabel = value
+	} else {
+		p.messages[key] = value
+	}
+	p.mu.Unlock()
+	p.notify()
+}
+
+// IncStage advances to the next of Progress's predefined stages, if any
+// remain.
+func (p *Progress) IncStage() {
+	p.mu.Lock()
+	if p.stageIndex < len(p.stages) {
+		p.stageIndex++
+	}
+	p.mu.Unlock()
+	p.notify()
+}
+
+// FinishedWithResults marks the work done and attaches its result value
+// (e.g. a parsed summary struct) for Serialize/Snapshot consumers.
+func (p *Progress) FinishedWithResults(result any) {
+	p.mu.Lock()
+	p.done = true
+	p.result = result
+	p.mu.Unlock()
+	p.notify()
+}
+
+// Error marks the work done with a failure.
+func (p *Progress) Error(err error) {
+	p.mu.Lock()
+	p.done = true
+	p.err = err
+	p.mu.Unlock()
+	p.notify()
+}
+
+func (p *Progress) snapshotLocked() ProgressSnapshot {
+	stage := p.stageLabel
+	if stage == "" && p.stageIndex < len(p.stages) {
+		stage = p.stages[p.stageIndex]
+	}
+	msgs := make(map[string]string, len(p.messages))
+	for k, v := range p.messages {
+		msgs[k] = v
+	}
+	snap := ProgressSnapshot{
+		Stage:      stage,
+		StageIndex: p.stageIndex,
+		StageCount: len(p.stages),
+		Messages:   msgs,
+		Done:       p.done,
+		Result:     p.result,
+	}
+	if p.err != nil {
+		snap.Err = p.err.Error()
+	}
+	return snap
+}
+
+// Snapshot returns the current state.
+func (p *Progress) Snapshot() ProgressSnapshot {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.snapshotLocked()
+}
+
+// Serialize writes the current state to w as JSON.
+func (p *Progress) Serialize(w io.Writer) error {
+	return json.NewEncoder(w).Encode(p.Snapshot())
+}
+
+// Subscribe returns a channel that receives a new ProgressSnapshot every
+// time this Progress's state actually changes - never on a no-op update,
+// so a subscriber can redraw exactly when there's something new to show.
+// The channel is never closed; it simply stops receiving once the
+// Progress (and its owning Process) are no longer referenced.
+func (p *Progress) Subscribe() <-chan ProgressSnapshot {
+	ch := make(chan ProgressSnapshot, 1)
+	p.mu.Lock()
+	p.subs = append(p.subs, ch)
+	p.mu.Unlock()
+	return ch
+}
+
+func (p *Progress) notify() {
+	p.mu.Lock()
+	snap := p.snapshotLocked()
+	if reflect.DeepEqual(snap, p.last) {
+		p.mu.Unlock()
+		return
+	}
+	p.last = snap
+	subs := append([]chan ProgressSnapshot{}, p.subs...)
+	p.mu.Unlock()
+
+	for _, ch := range subs {
+		select {
+		case ch <- snap:
+		default:
+			// Subscriber hasn't drained the last update yet; the next
+			// change carries the latest state forward anyway.
+		}
+	}
+}
+
+// progressLabel renders a snapshot as a short "(stage 3/7: detail)" tag
+// for the process tab bar (see renderRight), or "" if there's nothing
+// worth showing yet.
+func progressLabel(snap ProgressSnapshot) string {
+	var parts []string
+	if snap.Stage != "" {
+		parts = append(parts, snap.Stage)
+	}
+	if snap.StageCount > 0 {
+		parts = append(parts, fmt.Sprintf("%d/%d", snap.StageIndex+1, snap.StageCount))
+	}
+	if detail, ok := snap.Messages["detail"]; ok {
+		parts = append(parts, detail)
+	}
+	if len(parts) == 0 {
+		return ""
+	}
+	return "(" + strings.Join(parts, " ") + ")"
+}
+
+// ParseProgressLine extracts a key/value pair from a line shaped like
+// "progress: key=value" - the convention a command opts into on stdout to
+// report structured progress instead of (or alongside) plain log text -
+// or reports ok=false for any other line. See Process.AddLog, which feeds
+// every line through this before appending it to Progress.
+func ParseProgressLine(line string) (key, value string, ok bool) {
+	const prefix = "progress:"
+	trimmed := strings.TrimSpace(line)
+	if !strings.HasPrefix(trimmed, prefix) {
+		return "", "", false
+	}
+	rest := strings.TrimSpace(trimmed[len(prefix):])
+	eq := strings.Index(rest, "=")
+	if eq < 0 {
+		return "", "", false
+	}
+	return strings.TrimSpace(rest[:eq]), strings.TrimSpace(rest[eq+1:]), true
+}
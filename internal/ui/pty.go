@@ -0,0 +1,55 @@
+package ui
+
+import (
+	"bufio"
+	"os/exec"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/creack/pty"
+)
+
+// runCmdWithPTYOrPipes starts cmd attached to a real pty so interactive
+// prompts and TTY-only output render correctly, falling back to
+// runCmdWithPipes if pty allocation isn't available (e.g. on Windows, or
+// in a container without /dev/ptmx). cmd has not been started yet in
+// either case, so the fallback is safe.
+func runCmdWithPTYOrPipes(processID string, cmd *exec.Cmd, ch chan string) tea.Msg {
+	f, err := pty.Start(cmd)
+	if err != nil {
+		return runCmdWithPipes(processID, cmd, ch)
+	}
+
+	go func() {
+		scanner := bufio.NewScanner(f)
+		for scanner.Scan() {
+			select {
+			case ch <- scanner.Text():
+			default:
+			}
+		}
+	}()
+
+	go func() {
+		cmd.Wait()
+		f.Close()
+		close(ch)
+	}()
+
+	return processStartedMsg{processID: processID, cmd: cmd, outputChan: ch, ptyFile: f}
+}
+
+// resizeProcessPTYs propagates a terminal resize to every live process's
+// pty, sized to the log viewport (not the full terminal - the device
+// list/header/footer take up the rest), so full-screen TUIs running
+// inside (e.g. a wizard from `cap run`) redraw at the right size.
+func (m *Model) resizeProcessPTYs() {
+	for _, p := range m.processes {
+		if p.ptyMaster == nil || p.Status != ProcessRunning {
+			continue
+		}
+		pty.Setsize(p.ptyMaster, &pty.Winsize{
+			Rows: uint16(m.logViewport.Height),
+			Cols: uint16(m.logViewport.Width),
+		})
+	}
+}
@@ -0,0 +1,184 @@
+package ui
+
+import (
+	"runtime"
+	"time"
+
+	gopsprocess "github.com/shirou/gopsutil/v3/process"
+)
+
+// ProcState mirrors /proc/[pid]/status's process state field - a richer,
+// OS-level view of the underlying PID than the coarse ProcessStatus enum,
+// which only tracks lazycap's view of a command's lifecycle (started,
+// exited, auto-restarting, ...). Populated by Process.Sample.
+type ProcState int
+
+const (
+	StateUnknown ProcState = iota
+	StateRunning
+	StateSleeping
+	// StateDiskSleep is an uninterruptible sleep, commonly labeled
+	// "Blocked" in process monitors.
+	StateDiskSleep
+	StateStopped
+	StateTracingStop
+	StateZombie
+	StateDead
+)
+
+// String renders s the way a process monitor would.
+func (s ProcState) String() string {
+	switch s {
+	case StateRunning:
+		return "Running"
+	case StateSleeping:
+		return "Sleeping"
+	case StateDiskSleep:
+		return "Blocked"
+	case StateStopped:
+		return "Stopped"
+	case StateTracingStop:
+		return "TracingStop"
+	case StateZombie:
+		return "Zombie"
+	case StateDead:
+		return "Dead"
+	default:
+		return "Unknown"
+	}
+}
+
+// gopsutilStateToProcState maps gopsutil's single-character /proc status
+// codes (shared verbatim across the platforms it supports) to ProcState.
+// See (*gopsprocess.Process).Status.
+func gopsutilStateToProcState(codes []string) ProcState {
+	if len(codes) == 0 {
+		return StateUnknown
+	}
+	switch codes[0] {
+	case gopsprocess.Run:
+		return StateRunning
+	case gopsprocess.Sleep:
+		return StateSleeping
+	case gopsprocess.Stop:
+		return StateStopped
+	case gopsprocess.Idle:
+		return StateSleeping
+	case gopsprocess.Zombie:
+		return StateZombie
+	case gopsprocess.Wait:
+		return StateDiskSleep
+	case gopsprocess.Lock:
+		return StateDiskSleep
+	default:
+		return StateUnknown
+	}
+}
+
+// ResourceSample is one point in a Process's rolling resource history -
+// see Process.Sample and Process.ResourceHistory.
+type ResourceSample struct {
+	Time         time.Time
+	State        ProcState
+	CPUPercent   float64
+	RSSBytes     uint64
+	NumThreads   int32
+	IOReadBytes  uint64
+	IOWriteBytes uint64
+}
+
+// maxResourceSamples caps Process.resourceHistory so a long-lived dev
+// server's sample history doesn't grow without bound.
+const maxResourceSamples = 120
+
+// resourceSampleInterval is how often startResourceSampler polls gopsutil
+// for a running Process.
+const resourceSampleInterval = 2 * time.Second
+
+// Sample takes one resource reading of p's underlying PID via gopsutil and
+// appends it to p's rolling history, trimmed to maxResourceSamples. A
+// no-op if p has no live OS process to sample.
+func (p *Process) Sample() error {
+	if p.Cmd == nil || p.Cmd.Process == nil {
+		return nil
+	}
+	proc, err := gopsprocess.NewProcess(int32(p.Cmd.Process.Pid))
+	if err != nil {
+		return err
+	}
+
+	sample := ResourceSample{Time: time.Now()}
+
+	if cpu, err := proc.CPUPercent(); err == nil {
+		sample.CPUPercent = cpu
+	}
+	if mem, err := proc.MemoryInfo(); err == nil && mem != nil {
+		sample.RSSBytes = mem.RSS
+	}
+
+	if runtime.GOOS == "windows" {
+		// Windows job-object accounting (what gopsutil falls back to
+		// here) doesn't expose thread count, I/O counters or a
+		// /proc-style state string - telegraf's processes plugin hits
+		// the same wall, so we report only what's actually available.
+		sample.State = StateUnknown
+	} else {
+		if codes, err := proc.Status(); err == nil {
+			sample.State = gopsutilStateToProcState(codes)
+		}
+		if threads, err := proc.NumThreads(); err == nil {
+			sample.NumThreads = threads
+		}
+		if io, err := proc.IOCounters(); err == nil && io != nil {
+			sample.IOReadBytes = io.ReadBytes
+			sample.IOWriteBytes = io.WriteBytes
+		}
+	}
+
+	p.resourceMu.Lock()
+	p.State = sample.State
+	p.CPUPercent = sample.CPUPercent
+	p.RSSBytes = sample.RSSBytes
+	p.NumThreads = sample.NumThreads
+	p.IOReadBytes = sample.IOReadBytes
+	p.IOWriteBytes = sample.IOWriteBytes
+	p.resourceHistory = append(p.resourceHistory, sample)
+	if len(p.resourceHistory) > maxResourceSamples {
+		p.resourceHistory = p.resourceHistory[len(p.resourceHistory)-maxResourceSamples:]
+	}
+	p.resourceMu.Unlock()
+
+	return nil
+}
+
+// ResourceHistory returns a copy of p's rolling resource samples, oldest
+// first.
+func (p *Process) ResourceHistory() []ResourceSample {
+	p.resourceMu.Lock()
+	defer p.resourceMu.Unlock()
+	out := make([]ResourceSample, len(p.resourceHistory))
+	copy(out, p.resourceHistory)
+	return out
+}
+
+// startResourceSampler polls Sample every resourceSampleInterval until
+// stop is closed or p.Cmd's process is no longer running, so it winds
+// itself down without needing explicit cancellation from callers that
+// forget to close stop (e.g. a crash between Kill and close(stop)).
+func (p *Process) startResourceSampler(stop <-chan struct{}) {
+	go func() {
+		ticker := time.NewTicker(resourceSampleInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-stop:
+				return
+			case <-ticker.C:
+				if p.Status != ProcessRunning {
+					return
+				}
+				p.Sample()
+			}
+		}
+	}()
+}
@@ -0,0 +1,268 @@
+package ui
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/icarus-itcs/lazycap/internal/logbuffer"
+	"github.com/icarus-itcs/lazycap/internal/session"
+	"github.com/icarus-itcs/lazycap/internal/settings"
+)
+
+// sessionKey names the on-disk session directory for the current project,
+// so that sessions from different projects never collide (see
+// session.Dir).
+func (m *Model) sessionKey() string {
+	if m.project != nil {
+		return m.project.Name
+	}
+	return ""
+}
+
+// projectDir returns the Capacitor project's root directory, for passing
+// into runCmd/runWebCmd instead of letting them fall back to the
+// process-wide cwd (see runCmd's doc comment for why that matters under
+// `lazycap serve`).
+func (m *Model) projectDir() string {
+	if m.project != nil {
+		return m.project.RootDir
+	}
+	return ""
+}
+
+// persistSession writes (or updates) the on-disk record for p, swallowing
+// errors - session persistence is a convenience, not something a failed
+// write should ever surface to the user mid-run.
+func (m *Model) persistSession(p *Process) {
+	dir, err := session.Dir(m.sessionKey())
+	if err != nil {
+		return
+	}
+	s := session.Session{
+		ID:        p.ID,
+		Name:      p.Name,
+		Command:   p.Command,
+		LogPath:   p.LogPath,
+		StartTime: p.StartTime,
+		EndTime:   p.EndTime,
+	}
+	if p.Cmd != nil && p.Cmd.Process != nil {
+		s.PID = p.Cmd.Process.Pid
+	}
+	switch p.Status {
+	case ProcessRunning:
+		s.Status = session.StatusRunning
+	case ProcessSuccess:
+		s.Status = session.StatusSuccess
+	case ProcessFailed:
+		s.Status = session.StatusFailed
+	case ProcessCancelled:
+		s.Status = session.StatusCancelled
+	}
+	_ = session.Upsert(dir, s)
+}
+
+// openSessionLog creates (truncating) the on-disk log file for a
+// newly-started process and wires it up for tee'd writes via AddLog.
+func (m *Model) openSessionLog(p *Process) {
+	dir, err := session.Dir(m.sessionKey())
+	if err != nil {
+		return
+	}
+	path := filepath.Join(dir, p.ID+".log")
+	f, err := os.Create(path)
+	if err != nil {
+		return
+	}
+	p.LogPath = path
+	p.LogFile = f
+}
+
+// closeSessionLog flushes and releases the process's log file handle once
+// it has finished; the file itself is left on disk as a historical record.
+func (m *Model) closeSessionLog(p *Process) {
+	if p.LogFile != nil {
+		p.LogFile.Close()
+		p.LogFile = nil
+	}
+}
+
+// openLogBuffer opens p's ring-buffered, gzip-segmented on-disk log under
+// DataDir()/logs/<processID>, honoring the logWindowLines/
+// logRetentionMaxMB/logRetentionMaxAgeDays settings. Left nil on any
+// failure - the ring buffer is a bonus (search/export/tail) on top of
+// LogPath/LogFile, never a requirement for a process to run.
+func (m *Model) openLogBuffer(p *Process) {
+	base, err := settings.DataDir()
+	if err != nil {
+		return
+	}
+	windowSize := 5000
+	if m.settings != nil {
+		if n := m.settings.GetInt("logWindowLines"); n > 0 {
+			windowSize = n
+		}
+	}
+	retention := logbuffer.RetentionPolicy{}
+	if m.settings != nil {
+		retention.MaxBytes = int64(m.settings.GetInt("logRetentionMaxMB")) * 1024 * 1024
+		retention.MaxAge = time.Duration(m.settings.GetInt("logRetentionMaxAgeDays")) * 24 * time.Hour
+	}
+	rb, err := logbuffer.Open(filepath.Join(base, "logs", p.ID), windowSize, retention)
+	if err != nil {
+		return
+	}
+	p.RingLog = rb
+}
+
+// closeLogBuffer flushes and closes p's ring buffer once it has finished.
+// The gzip segments are left on disk so Search/Range/Export still work
+// against the historical tab.
+func (m *Model) closeLogBuffer(p *Process) {
+	if p.RingLog != nil {
+		p.RingLog.Close()
+	}
+}
+
+// reattachSessions scans the project's session directory for records left
+// behind by a previous lazycap run. Sessions whose PID is still alive are
+// reattached as running tabs (their log file is tailed for new output, and
+// Kill still works against the recovered *os.Process); everything else is
+// loaded back as a read-only historical tab so its output isn't lost just
+// because lazycap restarted.
+//
+// Returns the reattached processes and the tea.Cmd needed to start tailing
+// the live ones - both are folded into the Model under construction by the
+// caller (NewModelWithPlugins).
+func (m *Model) reattachSessions() []tea.Cmd {
+	dir, err := session.Dir(m.sessionKey())
+	if err != nil {
+		return nil
+	}
+	sessions, err := session.Load(dir)
+	if err != nil {
+		return nil
+	}
+
+	var tailCmds []tea.Cmd
+	for _, s := range sessions {
+		p := &Process{
+			ID:        s.ID,
+			Name:      s.Name,
+			Command:   s.Command,
+			LogPath:   s.LogPath,
+			StartTime: s.StartTime,
+			EndTime:   s.EndTime,
+		}
+		m.bumpNextProcessID(s.ID)
+
+		offset := loadLogInto(p, s.LogPath)
+
+		// Opened after loadLogInto so the lines it replayed from LogPath
+		// (already persisted to this process's segments by the previous
+		// run) aren't written into the ring buffer a second time;
+		// nextSegmentIndex picks up numbering where that run left off.
+		m.openLogBuffer(p)
+
+		if session.IsAlive(s.PID) {
+			p.Status = ProcessRunning
+			if proc, err := os.FindProcess(s.PID); err == nil {
+				p.Cmd = &exec.Cmd{Process: proc}
+			}
+			if f, err := os.OpenFile(s.LogPath, os.O_APPEND|os.O_WRONLY, 0o644); err == nil {
+				p.LogFile = f
+			}
+			p.AddLog(fmt.Sprintf("[%s] reattached to running session (pid %d)", time.Now().Format("15:04:05"), s.PID))
+			tailCmds = append(tailCmds, tailSessionLog(p.ID, s.LogPath, offset, s.PID))
+		} else {
+			switch s.Status {
+			case session.StatusFailed, session.StatusCancelled:
+				p.Status = ProcessFailed
+			default:
+				p.Status = ProcessSuccess
+			}
+		}
+
+		m.processes = append(m.processes, p)
+	}
+	return tailCmds
+}
+
+// bumpNextProcessID keeps future createProcess IDs from colliding with a
+// reattached session's original "p<N>" ID.
+func (m *Model) bumpNextProcessID(id string) {
+	n, err := strconv.Atoi(strings.TrimPrefix(id, "p"))
+	if err != nil {
+		return
+	}
+	if n >= m.nextProcessID {
+		m.nextProcessID = n + 1
+	}
+}
+
+// loadLogInto reads a process's persisted log file in full, parsing each
+// line the same way live output is, and returns the byte offset to resume
+// tailing from.
+func loadLogInto(p *Process, logPath string) int64 {
+	if logPath == "" {
+		return 0
+	}
+	f, err := os.Open(logPath)
+	if err != nil {
+		return 0
+	}
+	defer f.Close()
+
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		p.AddLog(scanner.Text())
+	}
+	offset, _ := f.Seek(0, io.SeekCurrent)
+	return offset
+}
+
+// tailSessionLog polls a reattached session's log file for lines written
+// since offset, feeding them through the same processStartedMsg/
+// processOutputMsg/processFinishedMsg pipeline a live-spawned process uses.
+// It exits once the process is no longer alive and the file has no more
+// unread data.
+func tailSessionLog(processID, logPath string, offset int64, pid int) tea.Cmd {
+	return func() tea.Msg {
+		ch := make(chan string, 100)
+		go func() {
+			defer close(ch)
+			f, err := os.Open(logPath)
+			if err != nil {
+				return
+			}
+			defer f.Close()
+			f.Seek(offset, io.SeekStart)
+			reader := bufio.NewReader(f)
+			for {
+				line, err := reader.ReadString('\n')
+				if len(line) > 0 {
+					ch <- strings.TrimRight(line, "\n")
+				}
+				if err != nil {
+					if !session.IsAlive(pid) {
+						return
+					}
+					time.Sleep(500 * time.Millisecond)
+				}
+			}
+		}()
+		var proc *os.Process
+		proc, _ = os.FindProcess(pid)
+		return processStartedMsg{processID: processID, cmd: &exec.Cmd{Process: proc}, outputChan: ch}
+	}
+}
@@ -1,28 +1,182 @@
 package ui
 
-import "github.com/charmbracelet/lipgloss"
+import (
+	"github.com/charmbracelet/lipgloss"
 
-// Capacitor brand colors
+	"github.com/icarus-itcs/lazycap/internal/theme"
+)
+
+// Capacitor brand colors and every derived lipgloss.Style used across the
+// TUI. These used to be package-level const-ish vars initialized once;
+// they're now rebuilt by applyTheme from a theme.Styleset (see the
+// "Appearance" settings category), falling back to the values below -
+// lazycap's original palette - for any selector a styleset leaves unset.
 var (
-	// Primary colors - Capacitor blue
-	capBlue      = lipgloss.Color("#119EFF")
-	capCyan      = lipgloss.Color("#73B7F6")
-	capDark      = lipgloss.Color("#16161D")
-	capLight     = lipgloss.Color("#ECEDEE")
-	capGray      = lipgloss.Color("#4A4A5A")
-
-	// Status colors
-	successColor = lipgloss.Color("#4ADE80")
-	errorColor   = lipgloss.Color("#F87171")
-	warnColor    = lipgloss.Color("#FBBF24")
-	mutedColor   = lipgloss.Color("#64748B")
-
-	// Platform colors
-	iosColor     = lipgloss.Color("#0A84FF")
-	androidColor = lipgloss.Color("#34D399")
-	webColor     = lipgloss.Color("#F97316") // Orange for web
+	capBlue  lipgloss.Color
+	capCyan  lipgloss.Color
+	capDark  lipgloss.Color
+	capLight lipgloss.Color
+	capGray  lipgloss.Color
+
+	successColor lipgloss.Color
+	errorColor   lipgloss.Color
+	warnColor    lipgloss.Color
+	mutedColor   lipgloss.Color
+
+	iosColor     lipgloss.Color
+	androidColor lipgloss.Color
+	webColor     lipgloss.Color
+
+	baseStyle lipgloss.Style
+
+	headerStyle  lipgloss.Style
+	projectStyle lipgloss.Style
+	dividerStyle lipgloss.Style
+	titleStyle   lipgloss.Style
+
+	activePaneStyle   lipgloss.Style
+	inactivePaneStyle lipgloss.Style
+
+	itemStyle     lipgloss.Style
+	selectedStyle lipgloss.Style
+
+	onlineStyle  lipgloss.Style
+	offlineStyle lipgloss.Style
+
+	runningStyle lipgloss.Style
+	successStyle lipgloss.Style
+	failedStyle  lipgloss.Style
+
+	logPaneStyle       lipgloss.Style
+	activeLogPaneStyle lipgloss.Style
+	logTitleStyle      lipgloss.Style
+	logEmptyStyle      lipgloss.Style
+
+	helpStyle    lipgloss.Style
+	helpKeyStyle lipgloss.Style
+
+	iosBadge     lipgloss.Style
+	androidBadge lipgloss.Style
+	webBadge     lipgloss.Style
+
+	upgradeStyle lipgloss.Style
+
+	mutedStyle lipgloss.Style
+	errorStyle lipgloss.Style
+
+	activeTabStyle   lipgloss.Style
+	inactiveTabStyle lipgloss.Style
+
+	sectionTitleStyle lipgloss.Style
+
+	// Named selectors called out by the styleset format itself
+	// (settings.value, debug.danger, plugin.running), used directly by
+	// renderSettings/renderDebug/renderPlugins instead of ad-hoc
+	// lipgloss.NewStyle() calls.
+	settingsValueStyle lipgloss.Style
+	debugDangerStyle   lipgloss.Style
+	pluginRunningStyle lipgloss.Style
 )
 
+func init() {
+	applyTheme(theme.Default())
+}
+
+// applyTheme rebuilds every color and style in this file from ss, falling
+// back to lazycap's original Capacitor palette for any selector ss
+// doesn't define. Called once at startup and again whenever the user
+// cycles the "theme" setting (see handleSettingsInput's "choice" case).
+func applyTheme(ss *theme.Styleset) {
+	color := func(selector, fallback string) lipgloss.Color {
+		resolved := ss.Resolve(selector, theme.Style{FG: fallback}).FG
+		if resolved == "" {
+			resolved = fallback
+		}
+		return lipgloss.Color(resolved)
+	}
+	style := func(selector string, fallback theme.Style) lipgloss.Style {
+		s := ss.Resolve(selector, fallback)
+		out := lipgloss.NewStyle()
+		if s.FG != "" {
+			out = out.Foreground(lipgloss.Color(s.FG))
+		}
+		if s.BG != "" {
+			out = out.Background(lipgloss.Color(s.BG))
+		}
+		if s.Bold {
+			out = out.Bold(true)
+		}
+		if s.Italic {
+			out = out.Italic(true)
+		}
+		if s.Underline {
+			out = out.Underline(true)
+		}
+		return out
+	}
+
+	capBlue = color("brand.primary", "#119EFF")
+	capCyan = color("brand.secondary", "#73B7F6")
+	capDark = color("brand.bg", "#16161D")
+	capLight = color("text.primary", "#ECEDEE")
+	capGray = color("border.inactive", "#4A4A5A")
+
+	successColor = color("status.success", "#4ADE80")
+	errorColor = color("status.error", "#F87171")
+	warnColor = color("status.warning", "#FBBF24")
+	mutedColor = color("text.muted", "#64748B")
+
+	iosColor = color("platform.ios", "#0A84FF")
+	androidColor = color("platform.android", "#34D399")
+	webColor = color("platform.web", "#F97316")
+
+	baseStyle = lipgloss.NewStyle().Background(capDark)
+
+	headerStyle = lipgloss.NewStyle().Foreground(capLight).Bold(true).Padding(0, 1).MarginBottom(1)
+	projectStyle = lipgloss.NewStyle().Foreground(capCyan)
+	dividerStyle = lipgloss.NewStyle().Foreground(capGray)
+	titleStyle = lipgloss.NewStyle().Foreground(capBlue).Bold(true).MarginBottom(1)
+
+	activePaneStyle = lipgloss.NewStyle().Border(lipgloss.RoundedBorder()).BorderForeground(capBlue).Padding(1, 2)
+	inactivePaneStyle = lipgloss.NewStyle().Border(lipgloss.RoundedBorder()).BorderForeground(capGray).Padding(1, 2)
+
+	itemStyle = lipgloss.NewStyle().Foreground(capLight).PaddingLeft(2)
+	selectedStyle = lipgloss.NewStyle().Foreground(capDark).Background(capBlue).Bold(true).PaddingLeft(2).PaddingRight(2)
+
+	onlineStyle = lipgloss.NewStyle().Foreground(successColor)
+	offlineStyle = lipgloss.NewStyle().Foreground(errorColor)
+
+	runningStyle = lipgloss.NewStyle().Foreground(capCyan)
+	successStyle = lipgloss.NewStyle().Foreground(successColor)
+	failedStyle = lipgloss.NewStyle().Foreground(errorColor)
+
+	logPaneStyle = lipgloss.NewStyle().Border(lipgloss.RoundedBorder()).BorderForeground(capGray).Padding(0, 1)
+	activeLogPaneStyle = lipgloss.NewStyle().Border(lipgloss.RoundedBorder()).BorderForeground(capBlue).Padding(0, 1)
+	logTitleStyle = lipgloss.NewStyle().Foreground(capCyan).Bold(true)
+	logEmptyStyle = lipgloss.NewStyle().Foreground(mutedColor).Italic(true)
+
+	helpStyle = lipgloss.NewStyle().Foreground(mutedColor).MarginTop(1)
+	helpKeyStyle = lipgloss.NewStyle().Foreground(capCyan).Bold(true)
+
+	iosBadge = lipgloss.NewStyle().Foreground(iosColor).Bold(true)
+	androidBadge = lipgloss.NewStyle().Foreground(androidColor).Bold(true)
+	webBadge = lipgloss.NewStyle().Foreground(webColor).Bold(true)
+
+	upgradeStyle = lipgloss.NewStyle().Foreground(warnColor).Bold(true)
+
+	mutedStyle = lipgloss.NewStyle().Foreground(mutedColor)
+	errorStyle = lipgloss.NewStyle().Foreground(errorColor)
+
+	activeTabStyle = style("tab.active", theme.Style{FG: "#16161D", BG: "#119EFF", Bold: true}).Padding(0, 1).MarginRight(1)
+	inactiveTabStyle = style("tab.inactive", theme.Style{FG: "#ECEDEE", BG: "#4A4A5A"}).Padding(0, 1).MarginRight(1)
+
+	sectionTitleStyle = titleStyle
+
+	settingsValueStyle = style("settings.value", theme.Style{FG: "#73B7F6"})
+	debugDangerStyle = style("debug.danger", theme.Style{FG: "#FBBF24", Bold: true})
+	pluginRunningStyle = style("plugin.running", theme.Style{FG: "#4ADE80"})
+}
+
 // CapacitorLogo returns the logo for welcome screen
 func CapacitorLogo() string {
 	textStyle := lipgloss.NewStyle().Foreground(capLight).Bold(true)
@@ -48,146 +202,6 @@ func LogoSmall() string {
 	return LogoCompact()
 }
 
-// Styles
-var (
-	// Base container
-	baseStyle = lipgloss.NewStyle().
-			Background(capDark)
-
-	// Header
-	headerStyle = lipgloss.NewStyle().
-			Foreground(capLight).
-			Bold(true).
-			Padding(0, 1).
-			MarginBottom(1)
-
-	// Project name in header
-	projectStyle = lipgloss.NewStyle().
-			Foreground(capCyan)
-
-	// Subtle divider
-	dividerStyle = lipgloss.NewStyle().
-			Foreground(capGray)
-
-	// Section titles
-	titleStyle = lipgloss.NewStyle().
-			Foreground(capBlue).
-			Bold(true).
-			MarginBottom(1)
-
-	// Active pane border
-	activePaneStyle = lipgloss.NewStyle().
-			Border(lipgloss.RoundedBorder()).
-			BorderForeground(capBlue).
-			Padding(1, 2)
-
-	// Inactive pane border
-	inactivePaneStyle = lipgloss.NewStyle().
-				Border(lipgloss.RoundedBorder()).
-				BorderForeground(capGray).
-				Padding(1, 2)
-
-	// List items
-	itemStyle = lipgloss.NewStyle().
-			Foreground(capLight).
-			PaddingLeft(2)
-
-	selectedStyle = lipgloss.NewStyle().
-			Foreground(capDark).
-			Background(capBlue).
-			Bold(true).
-			PaddingLeft(2).
-			PaddingRight(2)
-
-	// Device status
-	onlineStyle = lipgloss.NewStyle().
-			Foreground(successColor)
-
-	offlineStyle = lipgloss.NewStyle().
-			Foreground(errorColor)
-
-	// Process status
-	runningStyle = lipgloss.NewStyle().
-			Foreground(capCyan)
-
-	successStyle = lipgloss.NewStyle().
-			Foreground(successColor)
-
-	failedStyle = lipgloss.NewStyle().
-			Foreground(errorColor)
-
-	// Log pane
-	logPaneStyle = lipgloss.NewStyle().
-			Border(lipgloss.RoundedBorder()).
-			BorderForeground(capGray).
-			Padding(0, 1)
-
-	activeLogPaneStyle = lipgloss.NewStyle().
-				Border(lipgloss.RoundedBorder()).
-				BorderForeground(capBlue).
-				Padding(0, 1)
-
-	logTitleStyle = lipgloss.NewStyle().
-			Foreground(capCyan).
-			Bold(true)
-
-	logEmptyStyle = lipgloss.NewStyle().
-			Foreground(mutedColor).
-			Italic(true)
-
-	// Help bar
-	helpStyle = lipgloss.NewStyle().
-			Foreground(mutedColor).
-			MarginTop(1)
-
-	helpKeyStyle = lipgloss.NewStyle().
-			Foreground(capCyan).
-			Bold(true)
-
-	// Badges
-	iosBadge = lipgloss.NewStyle().
-			Foreground(iosColor).
-			Bold(true)
-
-	androidBadge = lipgloss.NewStyle().
-			Foreground(androidColor).
-			Bold(true)
-
-	webBadge = lipgloss.NewStyle().
-			Foreground(webColor).
-			Bold(true)
-
-	// Upgrade notice
-	upgradeStyle = lipgloss.NewStyle().
-			Foreground(warnColor).
-			Bold(true)
-
-	// Muted text
-	mutedStyle = lipgloss.NewStyle().
-			Foreground(mutedColor)
-
-	// Error
-	errorStyle = lipgloss.NewStyle().
-			Foreground(errorColor)
-
-	// Tab styles for process tabs
-	activeTabStyle = lipgloss.NewStyle().
-			Foreground(capDark).
-			Background(capBlue).
-			Padding(0, 1).
-			MarginRight(1).
-			Bold(true)
-
-	inactiveTabStyle = lipgloss.NewStyle().
-				Foreground(capLight).
-				Background(capGray).
-				Padding(0, 1).
-				MarginRight(1)
-
-	// Section title style (for compatibility)
-	sectionTitleStyle = titleStyle
-)
-
 // StatusDot returns a colored dot
 func StatusDot(online bool) string {
 	if online {
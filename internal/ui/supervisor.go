@@ -0,0 +1,83 @@
+package ui
+
+import (
+	"fmt"
+	"os/exec"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// scheduleRestart backs a crashed auto-restart process off exponentially
+// (1s, 2s, 4s, ... capped at 30s based on how many attempts it has already
+// used) and schedules the next attempt, cloning the original exec.Cmd's
+// Path/Args/Env/Dir so it restarts the exact same command. The caller is
+// responsible for having already confirmed AutoRestart is on and
+// RetriesLeft > 0.
+func (m *Model) scheduleRestart(p *Process) tea.Cmd {
+	if p.Cmd == nil {
+		p.Status = ProcessFatal
+		return nil
+	}
+
+	attempt := p.StartRetries - p.RetriesLeft
+	delay := time.Duration(1<<uint(attempt)) * time.Second
+	if delay > 30*time.Second {
+		delay = 30 * time.Second
+	}
+
+	p.RetriesLeft--
+	p.restartGen++
+	gen := p.restartGen
+	p.Status = ProcessBackoff
+	p.RestartAt = time.Now().Add(delay)
+
+	origCmd := p.Cmd
+	retriesLeft := p.RetriesLeft
+	processID := p.ID
+	usePTY := p.UsePTY
+
+	notify := func() tea.Msg {
+		return processRestartingMsg{processID: processID, delay: delay, retriesLeft: retriesLeft, gen: gen}
+	}
+	restart := func() tea.Msg {
+		time.Sleep(delay)
+		if p.restartGen != gen {
+			// AutoRestart was toggled off, or the tab was killed, while
+			// we were waiting out the backoff - abandon the restart.
+			return nil
+		}
+		cmd := exec.Command(origCmd.Path, origCmd.Args[1:]...)
+		cmd.Env = origCmd.Env
+		cmd.Dir = origCmd.Dir
+		ch := make(chan string, 100)
+		if usePTY {
+			return runCmdWithPTYOrPipes(processID, cmd, ch)
+		}
+		return runCmdWithPipes(processID, cmd, ch)
+	}
+	return tea.Batch(notify, restart)
+}
+
+// toggleAutoRestart flips AutoRestart on the selected process. Turning it
+// off while a restart is pending abandons that restart (see
+// scheduleRestart's gen check) and, if the process is mid-backoff, marks
+// it cancelled rather than leaving it stuck waiting.
+func (m *Model) toggleAutoRestart() {
+	p := m.getSelectedProcess()
+	if p == nil {
+		return
+	}
+	p.AutoRestart = !p.AutoRestart
+	if !p.AutoRestart {
+		p.restartGen++
+		if p.Status == ProcessBackoff {
+			p.Status = ProcessCancelled
+			p.EndTime = time.Now()
+		}
+		m.setStatus(fmt.Sprintf("Auto-restart disabled for %s", p.Name))
+	} else {
+		m.setStatus(fmt.Sprintf("Auto-restart enabled for %s", p.Name))
+	}
+	m.updateProcessMetrics()
+}
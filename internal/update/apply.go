@@ -0,0 +1,150 @@
+package update
+
+import (
+	"context"
+	"crypto/ed25519"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+
+	"github.com/icarus-itcs/lazycap/internal/download"
+	"github.com/icarus-itcs/lazycap/internal/settings"
+)
+
+// Apply downloads the version described by info, verifies its checksum
+// (and signature, if a trusted key is configured), installs it side-by-
+// side under versions/<version>/, and atomically swaps the "current"
+// symlink to point at it - the previous current is preserved as
+// "previous" for Rollback. Equivalent to ApplyWithProgress with a nil
+// progress channel.
+func Apply(info *Info) error {
+	return ApplyWithProgress(info, nil)
+}
+
+// ApplyWithProgress is Apply, optionally reporting download progress to
+// progressCh (see internal/download.Pool.Get) - used by
+// AppContext.ApplyUpdate to drive the TUI's progress bar.
+func ApplyWithProgress(info *Info, progressCh chan<- download.GenericProgress) error {
+	if info.LatestVersion == "" {
+		return fmt.Errorf("update info has no version")
+	}
+
+	destDir, err := versionDir(info.LatestVersion)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(destDir, 0o755); err != nil {
+		return fmt.Errorf("failed to create %s: %w", destDir, err)
+	}
+
+	binPath := filepath.Join(destDir, "lazycap")
+	if err := downloadVerified(info, binPath, progressCh); err != nil {
+		return err
+	}
+
+	manifestData, err := json.MarshalIndent(info, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode manifest: %w", err)
+	}
+	if err := os.WriteFile(filepath.Join(destDir, "manifest.json"), manifestData, 0o644); err != nil {
+		return fmt.Errorf("failed to write manifest: %w", err)
+	}
+
+	if err := recordPrevious(); err != nil {
+		return err
+	}
+
+	current, err := currentSymlinkPath()
+	if err != nil {
+		return err
+	}
+	return atomicSymlink(destDir, current)
+}
+
+// downloadVerified fetches info.DownloadURL via the shared download.Pool
+// (deduplicating against any other caller fetching the same version right
+// now, and caching the bytes under ~/.lazycap/cache by info.Checksum),
+// refusing to finalize destPath unless, on top of the pool's own checksum
+// check, a trusted public key's signature verifies too.
+func downloadVerified(info *Info, destPath string, progressCh chan<- download.GenericProgress) error {
+	f, _, err := download.DefaultPool().Get(context.Background(), "update:"+info.LatestVersion, info.DownloadURL, info.Checksum, progressCh)
+	if err != nil {
+		return fmt.Errorf("failed to download update: %w", err)
+	}
+	defer f.Close()
+
+	data, err := io.ReadAll(f)
+	if err != nil {
+		return fmt.Errorf("failed to read downloaded update: %w", err)
+	}
+	if err := verifySignature(data, info.Signature); err != nil {
+		return err
+	}
+
+	tmp := destPath + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o755); err != nil {
+		return fmt.Errorf("failed to write %s: %w", tmp, err)
+	}
+	defer os.Remove(tmp)
+
+	return os.Rename(tmp, destPath)
+}
+
+// verifySignature checks sig against the "updateSignaturePublicKey"
+// setting (hex-encoded ed25519, 32 bytes) - mirrors
+// internal/plugin/distribution.go's verifyManifestSignature, but against
+// its own trust anchor since lazycap's own binary and a third-party
+// plugin shouldn't share a signing key.
+func verifySignature(data []byte, sig string) error {
+	userSettings, err := settings.Load()
+	if err != nil {
+		return fmt.Errorf("failed to load settings: %w", err)
+	}
+	keyHex := userSettings.GetString("updateSignaturePublicKey")
+	if keyHex == "" {
+		return nil
+	}
+	if sig == "" {
+		return fmt.Errorf("update is unsigned but a trusted public key is configured")
+	}
+
+	key, err := hex.DecodeString(keyHex)
+	if err != nil || len(key) != ed25519.PublicKeySize {
+		return fmt.Errorf("updateSignaturePublicKey is not a valid ed25519 public key")
+	}
+	sigBytes, err := base64.StdEncoding.DecodeString(sig)
+	if err != nil {
+		return fmt.Errorf("invalid update signature encoding: %w", err)
+	}
+	if !ed25519.Verify(key, data, sigBytes) {
+		return fmt.Errorf("update signature verification failed")
+	}
+	return nil
+}
+
+// recordPrevious points the "previous" symlink at whatever "current"
+// currently resolves to, before Apply moves current forward - a no-op on
+// a first-ever install, when current doesn't exist yet.
+func recordPrevious() error {
+	current, err := currentSymlinkPath()
+	if err != nil {
+		return err
+	}
+	target, err := os.Readlink(current)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil
+		}
+		return fmt.Errorf("failed to read current symlink: %w", err)
+	}
+
+	previous, err := previousSymlinkPath()
+	if err != nil {
+		return err
+	}
+	return atomicSymlink(target, previous)
+}
@@ -0,0 +1,131 @@
+package update
+
+import (
+	"crypto/ed25519"
+	"encoding/base64"
+	"encoding/hex"
+	"os"
+	"testing"
+
+	"github.com/icarus-itcs/lazycap/internal/settings"
+)
+
+func withTempConfigDir(t *testing.T) *settings.Settings {
+	t.Helper()
+	t.Setenv("LAZYCAP_CONFIG_DIR", t.TempDir())
+	s, err := settings.Load()
+	if err != nil {
+		t.Fatalf("settings.Load: %v", err)
+	}
+	return s
+}
+
+func TestVerifySignatureNoTrustedKeyAllowsUnsigned(t *testing.T) {
+	withTempConfigDir(t)
+
+	if err := verifySignature([]byte("payload"), ""); err != nil {
+		t.Errorf("expected no error with no trusted key configured, got %v", err)
+	}
+}
+
+func TestVerifySignatureValid(t *testing.T) {
+	s := withTempConfigDir(t)
+
+	pub, priv, err := ed25519.GenerateKey(nil)
+	if err != nil {
+		t.Fatalf("GenerateKey: %v", err)
+	}
+	s.SetString("updateSignaturePublicKey", hex.EncodeToString(pub))
+	if err := s.Save(); err != nil {
+		t.Fatalf("Save: %v", err)
+	}
+
+	data := []byte("the update binary")
+	sig := base64.StdEncoding.EncodeToString(ed25519.Sign(priv, data))
+
+	if err := verifySignature(data, sig); err != nil {
+		t.Errorf("expected a valid signature to verify, got %v", err)
+	}
+}
+
+func TestVerifySignatureRejectsTamperedData(t *testing.T) {
+	s := withTempConfigDir(t)
+
+	pub, priv, err := ed25519.GenerateKey(nil)
+	if err != nil {
+		t.Fatalf("GenerateKey: %v", err)
+	}
+	s.SetString("updateSignaturePublicKey", hex.EncodeToString(pub))
+	if err := s.Save(); err != nil {
+		t.Fatalf("Save: %v", err)
+	}
+
+	sig := base64.StdEncoding.EncodeToString(ed25519.Sign(priv, []byte("the update binary")))
+
+	if err := verifySignature([]byte("a different payload"), sig); err == nil {
+		t.Error("expected verification to fail for data that doesn't match the signature")
+	}
+}
+
+func TestVerifySignatureRequiresSignatureWhenKeyConfigured(t *testing.T) {
+	s := withTempConfigDir(t)
+
+	pub, _, err := ed25519.GenerateKey(nil)
+	if err != nil {
+		t.Fatalf("GenerateKey: %v", err)
+	}
+	s.SetString("updateSignaturePublicKey", hex.EncodeToString(pub))
+	if err := s.Save(); err != nil {
+		t.Fatalf("Save: %v", err)
+	}
+
+	if err := verifySignature([]byte("payload"), ""); err == nil {
+		t.Error("expected an unsigned update to be rejected once a trusted public key is configured")
+	}
+}
+
+func TestRollbackRestoresPrevious(t *testing.T) {
+	withTempDataDir(t)
+
+	root, err := rootDir()
+	if err != nil {
+		t.Fatalf("rootDir: %v", err)
+	}
+	previous, err := previousSymlinkPath()
+	if err != nil {
+		t.Fatalf("previousSymlinkPath: %v", err)
+	}
+	current, err := currentSymlinkPath()
+	if err != nil {
+		t.Fatalf("currentSymlinkPath: %v", err)
+	}
+
+	oldVersionDir := root + "/versions/1.0.0"
+	newVersionDir := root + "/versions/2.0.0"
+	if err := atomicSymlink(oldVersionDir, previous); err != nil {
+		t.Fatalf("seed previous: %v", err)
+	}
+	if err := atomicSymlink(newVersionDir, current); err != nil {
+		t.Fatalf("seed current: %v", err)
+	}
+
+	if err := Rollback(); err != nil {
+		t.Fatalf("Rollback: %v", err)
+	}
+
+	target, err := os.Readlink(current)
+	if err != nil {
+		t.Fatalf("os.Readlink: %v", err)
+	}
+	if target != oldVersionDir {
+		t.Errorf("expected current to point at %s after rollback, got %s", oldVersionDir, target)
+	}
+}
+
+func TestRollbackFailsWithNoPrevious(t *testing.T) {
+	withTempDataDir(t)
+
+	if err := Rollback(); err == nil {
+		t.Error("expected Rollback to fail when there's no previous version recorded")
+	}
+}
@@ -0,0 +1,92 @@
+package update
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// healthState tracks whether the last run of a given version exited
+// cleanly, so CheckStartupHealth can tell an abnormal exit (process
+// killed, panicked, crashed) from a normal one across restarts.
+type healthState struct {
+	Version   string `json:"version"`
+	CleanExit bool   `json:"clean_exit"`
+	FailCount int    `json:"fail_count"`
+}
+
+func healthPath() (string, error) {
+	root, err := rootDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(root, "health.json"), nil
+}
+
+func loadHealth() (*healthState, error) {
+	path, err := healthPath()
+	if err != nil {
+		return nil, err
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return &healthState{}, nil
+		}
+		return nil, fmt.Errorf("failed to read update health state: %w", err)
+	}
+	var h healthState
+	if err := json.Unmarshal(data, &h); err != nil {
+		return &healthState{}, nil
+	}
+	return &h, nil
+}
+
+func saveHealth(h *healthState) error {
+	path, err := healthPath()
+	if err != nil {
+		return err
+	}
+	data, err := json.Marshal(h)
+	if err != nil {
+		return fmt.Errorf("failed to encode update health state: %w", err)
+	}
+	return os.WriteFile(path, data, 0o644)
+}
+
+// CheckStartupHealth should be called once, early in startup, with the
+// currently-running version. If the previous run of this same version
+// never reached RecordCleanExit and this is the second time in a row
+// that's happened, it rolls "current" back to "previous" and reports
+// rolledBack=true so the caller can tell the user a bad update was
+// reverted. Every other case just records bookkeeping and continues.
+func CheckStartupHealth(version string) (rolledBack bool, err error) {
+	h, err := loadHealth()
+	if err != nil {
+		return false, err
+	}
+
+	if h.Version != version {
+		return false, saveHealth(&healthState{Version: version})
+	}
+	if h.CleanExit {
+		return false, saveHealth(&healthState{Version: version})
+	}
+
+	h.FailCount++
+	if h.FailCount >= 2 {
+		if err := Rollback(); err != nil {
+			return false, err
+		}
+		return true, saveHealth(&healthState{})
+	}
+
+	return false, saveHealth(h)
+}
+
+// RecordCleanExit should be called just before a normal process exit, so
+// the next startup's CheckStartupHealth knows this run didn't crash.
+func RecordCleanExit(version string) error {
+	return saveHealth(&healthState{Version: version, CleanExit: true})
+}
@@ -0,0 +1,105 @@
+package update
+
+import "testing"
+
+func withTempDataDir(t *testing.T) {
+	t.Helper()
+	t.Setenv("XDG_DATA_HOME", t.TempDir())
+}
+
+func TestCheckStartupHealthFirstRunOfVersion(t *testing.T) {
+	withTempDataDir(t)
+
+	rolledBack, err := CheckStartupHealth("1.2.3")
+	if err != nil {
+		t.Fatalf("CheckStartupHealth: %v", err)
+	}
+	if rolledBack {
+		t.Error("expected no rollback on the first run of a version")
+	}
+}
+
+func TestCheckStartupHealthCleanExitDoesNotAccumulate(t *testing.T) {
+	withTempDataDir(t)
+
+	if err := RecordCleanExit("1.2.3"); err != nil {
+		t.Fatalf("RecordCleanExit: %v", err)
+	}
+	rolledBack, err := CheckStartupHealth("1.2.3")
+	if err != nil {
+		t.Fatalf("CheckStartupHealth: %v", err)
+	}
+	if rolledBack {
+		t.Error("expected no rollback after a prior clean exit")
+	}
+
+	h, err := loadHealth()
+	if err != nil {
+		t.Fatalf("loadHealth: %v", err)
+	}
+	if h.CleanExit {
+		t.Error("expected CheckStartupHealth to reset CleanExit once it's consumed the prior run's bookkeeping")
+	}
+}
+
+func TestCheckStartupHealthRollsBackAfterTwoDirtyRuns(t *testing.T) {
+	withTempDataDir(t)
+
+	root, err := rootDir()
+	if err != nil {
+		t.Fatalf("rootDir: %v", err)
+	}
+	previous, err := previousSymlinkPath()
+	if err != nil {
+		t.Fatalf("previousSymlinkPath: %v", err)
+	}
+	if err := atomicSymlink(root, previous); err != nil {
+		t.Fatalf("seed previous symlink: %v", err)
+	}
+
+	// First dirty run: a prior run of this version exists but never called
+	// RecordCleanExit, so this is strike one.
+	if err := saveHealth(&healthState{Version: "1.2.3"}); err != nil {
+		t.Fatalf("saveHealth: %v", err)
+	}
+	rolledBack, err := CheckStartupHealth("1.2.3")
+	if err != nil {
+		t.Fatalf("CheckStartupHealth (strike one): %v", err)
+	}
+	if rolledBack {
+		t.Fatal("expected no rollback on the first dirty run")
+	}
+
+	// Second dirty run in a row on the same version: strike two.
+	rolledBack, err = CheckStartupHealth("1.2.3")
+	if err != nil {
+		t.Fatalf("CheckStartupHealth (strike two): %v", err)
+	}
+	if !rolledBack {
+		t.Error("expected rollback after two dirty runs in a row on the same version")
+	}
+}
+
+func TestCheckStartupHealthDifferentVersionResetsCount(t *testing.T) {
+	withTempDataDir(t)
+
+	if err := saveHealth(&healthState{Version: "1.2.3", FailCount: 1}); err != nil {
+		t.Fatalf("saveHealth: %v", err)
+	}
+
+	rolledBack, err := CheckStartupHealth("1.3.0")
+	if err != nil {
+		t.Fatalf("CheckStartupHealth: %v", err)
+	}
+	if rolledBack {
+		t.Error("expected no rollback when the running version differs from the recorded one")
+	}
+
+	h, err := loadHealth()
+	if err != nil {
+		t.Fatalf("loadHealth: %v", err)
+	}
+	if h.FailCount != 0 {
+		t.Errorf("expected FailCount to reset for a new version, got %d", h.FailCount)
+	}
+}
@@ -0,0 +1,124 @@
+package update
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/icarus-itcs/lazycap/internal/settings"
+)
+
+// rootDir returns the update subsystem's root under the user data dir:
+//
+//	versions/<semver>/lazycap
+//	versions/<semver>/manifest.json
+//	current -> versions/<semver>
+//	previous -> versions/<old-semver>
+func rootDir() (string, error) {
+	dataDir, err := settings.DataDir()
+	if err != nil {
+		return "", err
+	}
+	dir := filepath.Join(dataDir, "update")
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return "", fmt.Errorf("failed to create %s: %w", dir, err)
+	}
+	return dir, nil
+}
+
+func versionsDir() (string, error) {
+	root, err := rootDir()
+	if err != nil {
+		return "", err
+	}
+	dir := filepath.Join(root, "versions")
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return "", fmt.Errorf("failed to create %s: %w", dir, err)
+	}
+	return dir, nil
+}
+
+func versionDir(version string) (string, error) {
+	dir, err := versionsDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, version), nil
+}
+
+func currentSymlinkPath() (string, error) {
+	root, err := rootDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(root, "current"), nil
+}
+
+func previousSymlinkPath() (string, error) {
+	root, err := rootDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(root, "previous"), nil
+}
+
+// CurrentBinaryPath resolves current/lazycap, the executable a launcher
+// script or relaunch should exec.
+func CurrentBinaryPath() (string, error) {
+	link, err := currentSymlinkPath()
+	if err != nil {
+		return "", err
+	}
+	target, err := os.Readlink(link)
+	if err != nil {
+		return "", fmt.Errorf("no current version installed: %w", err)
+	}
+	return filepath.Join(target, "lazycap"), nil
+}
+
+// atomicSymlink points linkPath at target by writing a temporary symlink
+// and renaming it into place, so a reader never observes a missing or
+// half-written link - the same swap-then-rename pattern the request asks
+// for, just factored out since both Apply and Rollback need it.
+func atomicSymlink(target, linkPath string) error {
+	tmp := linkPath + ".tmp"
+	os.Remove(tmp)
+	if err := os.Symlink(target, tmp); err != nil {
+		return fmt.Errorf("failed to create symlink: %w", err)
+	}
+	if err := os.Rename(tmp, linkPath); err != nil {
+		return fmt.Errorf("failed to swap symlink %s: %w", linkPath, err)
+	}
+	return nil
+}
+
+func firstRunMarkerPath(version string) (string, error) {
+	dir, err := versionDir(version)
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, ".first-run-ok"), nil
+}
+
+// IsTrial reports whether version hasn't yet completed a first clean run
+// - i.e. it was installed by Apply but lazycap hasn't called
+// MarkFirstRunComplete for it, making it eligible for the two-strikes
+// auto-rollback in CheckStartupHealth.
+func IsTrial(version string) bool {
+	marker, err := firstRunMarkerPath(version)
+	if err != nil {
+		return false
+	}
+	_, err = os.Stat(marker)
+	return os.IsNotExist(err)
+}
+
+// MarkFirstRunComplete records that version has run cleanly at least
+// once, so it's no longer considered a trial install.
+func MarkFirstRunComplete(version string) error {
+	marker, err := firstRunMarkerPath(version)
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(marker, []byte("ok\n"), 0o644)
+}
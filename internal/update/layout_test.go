@@ -0,0 +1,61 @@
+package update
+
+import (
+	"os"
+	"testing"
+)
+
+func TestIsTrialUnmarkedVersion(t *testing.T) {
+	withTempDataDir(t)
+
+	if !IsTrial("1.2.3") {
+		t.Error("expected an installed-but-never-run version to be a trial")
+	}
+}
+
+func TestIsTrialAfterMarkFirstRunComplete(t *testing.T) {
+	withTempDataDir(t)
+
+	// MarkFirstRunComplete only ever runs against a version Apply already
+	// installed, so its versions/<version>/ directory is guaranteed to
+	// exist - seed that here rather than in MarkFirstRunComplete itself.
+	dir, err := versionDir("1.2.3")
+	if err != nil {
+		t.Fatalf("versionDir: %v", err)
+	}
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		t.Fatalf("MkdirAll: %v", err)
+	}
+
+	if err := MarkFirstRunComplete("1.2.3"); err != nil {
+		t.Fatalf("MarkFirstRunComplete: %v", err)
+	}
+	if IsTrial("1.2.3") {
+		t.Error("expected a version to stop being a trial once MarkFirstRunComplete is recorded")
+	}
+	// A different version's marker must not leak across versions.
+	if !IsTrial("1.3.0") {
+		t.Error("expected an unrelated version to still be a trial")
+	}
+}
+
+func TestAtomicSymlinkSwapsExistingLink(t *testing.T) {
+	withTempDataDir(t)
+	dir := t.TempDir()
+	link := dir + "/current"
+
+	if err := atomicSymlink(dir+"/v1", link); err != nil {
+		t.Fatalf("atomicSymlink (first): %v", err)
+	}
+	if err := atomicSymlink(dir+"/v2", link); err != nil {
+		t.Fatalf("atomicSymlink (second): %v", err)
+	}
+
+	target, err := os.Readlink(link)
+	if err != nil {
+		t.Fatalf("readlink: %v", err)
+	}
+	if target != dir+"/v2" {
+		t.Errorf("expected symlink to point at v2, got %s", target)
+	}
+}
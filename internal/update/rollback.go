@@ -0,0 +1,27 @@
+package update
+
+import (
+	"fmt"
+	"os"
+)
+
+// Rollback repoints "current" back at whatever "previous" resolves to -
+// the inverse of Apply's recordPrevious step. It's called explicitly by
+// a failed-update recovery flow, and automatically by CheckStartupHealth
+// after two abnormal exits in a row on the same version.
+func Rollback() error {
+	previous, err := previousSymlinkPath()
+	if err != nil {
+		return err
+	}
+	target, err := os.Readlink(previous)
+	if err != nil {
+		return fmt.Errorf("no previous version to roll back to: %w", err)
+	}
+
+	current, err := currentSymlinkPath()
+	if err != nil {
+		return err
+	}
+	return atomicSymlink(target, current)
+}
@@ -0,0 +1,66 @@
+// Package update implements lazycap's self-update pipeline: checking a
+// remote manifest for a newer version, installing it side-by-side under
+// the user data dir, and atomically swapping a "current" symlink so a
+// running instance is never overwritten out from under itself. See
+// Apply, Rollback, and CheckStartupHealth for the three moving parts.
+package update
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/Masterminds/semver/v3"
+)
+
+// Info describes the result of a version check - how preflight.Results
+// reports whether an update is available (see UpdateInfo/SetVersionInfo).
+type Info struct {
+	CurrentVersion  string `json:"current_version"`
+	LatestVersion   string `json:"latest_version"`
+	UpdateAvailable bool   `json:"update_available"`
+	DownloadURL     string `json:"download_url"`
+	Checksum        string `json:"checksum"` // sha256, hex-encoded
+	Signature       string `json:"signature,omitempty"`
+}
+
+var updateHTTPClient = &http.Client{Timeout: 15 * time.Second}
+
+// Check fetches manifestURL (a JSON document shaped like Info, minus
+// CurrentVersion/UpdateAvailable) and compares it against currentVersion
+// using semver, returning an Info ready to hand to Apply.
+func Check(manifestURL, currentVersion string) (*Info, error) {
+	resp, err := updateHTTPClient.Get(manifestURL)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch update manifest: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("update manifest request returned %s", resp.Status)
+	}
+
+	var info Info
+	if err := json.NewDecoder(resp.Body).Decode(&info); err != nil {
+		return nil, fmt.Errorf("failed to parse update manifest: %w", err)
+	}
+	info.CurrentVersion = currentVersion
+
+	if currentVersion == "" || currentVersion == "dev" {
+		info.UpdateAvailable = false
+		return &info, nil
+	}
+
+	current, err := semver.NewVersion(currentVersion)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse current version %q: %w", currentVersion, err)
+	}
+	latest, err := semver.NewVersion(info.LatestVersion)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse latest version %q: %w", info.LatestVersion, err)
+	}
+	info.UpdateAvailable = latest.GreaterThan(current)
+
+	return &info, nil
+}
@@ -14,7 +14,12 @@ var (
 )
 
 func main() {
-	if err := lazycap.Execute(version, commit, date); err != nil {
+	meta := lazycap.AppMeta{
+		Version: version,
+		Commit:  commit,
+		Date:    date,
+	}
+	if err := lazycap.Execute(meta); err != nil {
 		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
 		os.Exit(1)
 	}
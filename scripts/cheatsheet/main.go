@@ -0,0 +1,98 @@
+// Command cheatsheet generates per-view Markdown documentation from the
+// keybinding registry. Run `go run scripts/cheatsheet/main.go generate` to
+// (re)write docs/keybindings/, or `check` to verify the committed docs are
+// up to date (non-zero exit on drift, suitable for CI).
+package main
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+
+	"github.com/icarus-itcs/lazycap/internal/keybindings"
+	_ "github.com/icarus-itcs/lazycap/internal/ui"
+)
+
+const docsDir = "docs/keybindings"
+
+func main() {
+	mode := "generate"
+	if len(os.Args) > 1 {
+		mode = os.Args[1]
+	}
+
+	switch mode {
+	case "generate":
+		if err := generate(docsDir); err != nil {
+			fmt.Fprintf(os.Stderr, "cheatsheet: %v\n", err)
+			os.Exit(1)
+		}
+	case "check":
+		if err := check(); err != nil {
+			fmt.Fprintf(os.Stderr, "cheatsheet: %v\n", err)
+			os.Exit(1)
+		}
+	default:
+		fmt.Fprintf(os.Stderr, "cheatsheet: unknown mode %q (expected generate or check)\n", mode)
+		os.Exit(1)
+	}
+}
+
+// generate writes one Markdown file per view under outDir.
+func generate(outDir string) error {
+	if err := os.MkdirAll(outDir, 0o755); err != nil {
+		return fmt.Errorf("failed to create %s: %w", outDir, err)
+	}
+
+	for view, content := range renderViews() {
+		path := filepath.Join(outDir, view+".md")
+		if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+			return fmt.Errorf("failed to write %s: %w", path, err)
+		}
+	}
+	return nil
+}
+
+// check regenerates into a temp dir and diffs it against the committed
+// docs, exiting non-zero if they differ.
+func check() error {
+	tmpDir, err := os.MkdirTemp("", "lazycap-cheatsheet-*")
+	if err != nil {
+		return fmt.Errorf("failed to create temp dir: %w", err)
+	}
+	defer os.RemoveAll(tmpDir)
+
+	if err := generate(tmpDir); err != nil {
+		return err
+	}
+
+	diff := exec.Command("diff", "-ru", docsDir, tmpDir)
+	diff.Stdout = os.Stdout
+	diff.Stderr = os.Stderr
+	if err := diff.Run(); err != nil {
+		return fmt.Errorf("keybinding docs are out of date, run `go run scripts/cheatsheet/main.go generate`: %w", err)
+	}
+	return nil
+}
+
+// renderViews groups the registry by view and renders each to Markdown.
+func renderViews() map[string]string {
+	byView := make(map[string][]keybindings.Binding)
+	for _, b := range keybindings.All() {
+		byView[b.View] = append(byView[b.View], b)
+	}
+
+	out := make(map[string]string, len(byView))
+	for view, bindings := range byView {
+		sort.Slice(bindings, func(i, j int) bool { return bindings[i].Key < bindings[j].Key })
+
+		md := fmt.Sprintf("# %s keybindings\n\n| Key | Description |\n| --- | --- |\n", view)
+		for _, b := range bindings {
+			md += fmt.Sprintf("| `%s` | %s |\n", b.Key, b.Description)
+		}
+		out[view] = md
+	}
+	return out
+}